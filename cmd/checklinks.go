@@ -0,0 +1,54 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/NickolasHKraus/archor/internal/linkcheck"
+)
+
+var concurrency int
+
+// checkLinksCmd represents the check-links command
+var checkLinksCmd = &cobra.Command{
+	Use:   "check-links <source>",
+	Short: "Check the links of an RSS feed",
+	Long: `Check-links requests every URL referenced by the feed at source and
+reports the links that are unreachable or respond with a non-2xx status,
+along with the element that references them. Links that are not http(s)
+URLs (e.g. mailto: links) are skipped.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := newFetchClient(cmd)
+		if err != nil {
+			return err
+		}
+		r, err := c.Fetch(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+		var broken int
+		for _, res := range linkcheck.Check(cmd.Context(), c.HTTP(), r.LinkRefs(), concurrency) {
+			if res.Broken() {
+				broken++
+				fmt.Fprintf(cmd.OutOrStdout(), "%s: %s: %v\n", res.Path, res.URL, res.Err)
+			}
+		}
+		if broken > 0 {
+			return fmt.Errorf("found %d broken links", broken)
+		}
+		return nil
+	},
+}
+
+func init() {
+	archorCmd.AddCommand(checkLinksCmd)
+
+	addHTTPFlags(checkLinksCmd)
+	checkLinksCmd.Flags().IntVar(&concurrency, "concurrency", linkcheck.DefaultConcurrency, "number of links to check concurrently")
+}
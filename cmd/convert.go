@@ -0,0 +1,64 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/NickolasHKraus/archor/internal/convert"
+)
+
+var (
+	convertTo     string
+	convertOutput string
+	convertIndent bool
+)
+
+// convertCmd represents the convert command
+var convertCmd = &cobra.Command{
+	Use:   "convert <source>",
+	Short: "Convert a feed to RSS, Atom or JSON Feed",
+	Long: `Convert reads the RSS 2.0, Atom or RSS 1.0 (RDF) feed at source and writes
+it in the format given by --to: rss (RSS 2.0), atom (Atom 1.0) or json
+(JSON Feed), to standard output or to the file given by --output.
+
+The source is an http(s) URL, a file:// URI, a local path, or "-" to read
+the feed from standard input.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := newFetchClient(cmd)
+		if err != nil {
+			return err
+		}
+		r, err := c.Fetch(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+		var o convert.Options
+		if convertIndent {
+			o.Indent = "  "
+		}
+		b, err := o.Convert(r, convert.Format(convertTo))
+		if err != nil {
+			return err
+		}
+		if convertOutput != "" {
+			return os.WriteFile(convertOutput, b, 0o644)
+		}
+		_, err = cmd.OutOrStdout().Write(b)
+		return err
+	},
+}
+
+func init() {
+	archorCmd.AddCommand(convertCmd)
+
+	addHTTPFlags(convertCmd)
+	convertCmd.Flags().StringVar(&convertTo, "to", string(convert.RSS), "output format: rss, atom or json")
+	convertCmd.Flags().BoolVar(&convertIndent, "indent", false, "indent JSON Feed output")
+	convertCmd.Flags().StringVarP(&convertOutput, "output", "o", "", "file to write the converted feed to (default is standard output)")
+}
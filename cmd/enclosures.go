@@ -0,0 +1,51 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/NickolasHKraus/archor/internal/enclosures"
+)
+
+var enclosuresFormat string
+
+// enclosuresCmd represents the enclosures command
+var enclosuresCmd = &cobra.Command{
+	Use:   "enclosures <source>",
+	Short: "Print the enclosure URLs of a feed",
+	Long: `Enclosures reads the RSS 2.0, Atom or RSS 1.0 (RDF) feed at source and prints
+the URL of the enclosure of each item, one per line, for use with external
+downloaders. With --format json, it prints a JSON array of the form
+[{"url": string, "type": string, "length": number}].
+
+The source is an http(s) URL, a file:// URI, a local path, or "-" to read
+the feed from standard input.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		f := enclosures.Format(enclosuresFormat)
+		if f != enclosures.Text && f != enclosures.JSON {
+			return fmt.Errorf("invalid --format %q", enclosuresFormat)
+		}
+		c, err := newFetchClient(cmd)
+		if err != nil {
+			return err
+		}
+		r, err := c.Fetch(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+		return enclosures.Write(cmd.OutOrStdout(), enclosures.List(r), f)
+	},
+}
+
+func init() {
+	archorCmd.AddCommand(enclosuresCmd)
+
+	addHTTPFlags(enclosuresCmd)
+	enclosuresCmd.Flags().StringVar(&enclosuresFormat, "format", string(enclosures.Text), "output format: text or json")
+}
@@ -0,0 +1,50 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/spf13/cobra"
+
+	"github.com/NickolasHKraus/archor/internal/fetch"
+	"github.com/NickolasHKraus/archor/internal/httpclient"
+)
+
+var (
+	httpConfig = httpclient.Config{RetryBackoff: httpclient.DefaultRetryBackoff}
+	proxy      string
+)
+
+// addHTTPFlags adds the flags configuring HTTP requests to cmd, a command
+// that fetches feeds.
+func addHTTPFlags(cmd *cobra.Command) {
+	cmd.Flags().DurationVar(&httpConfig.Timeout, "timeout", httpclient.DefaultTimeout, "timeout for HTTP requests")
+	cmd.Flags().IntVar(&httpConfig.Retries, "retries", httpclient.DefaultRetries, "number of times to retry a failed HTTP request")
+	cmd.Flags().IntVar(&httpConfig.MaxRedirects, "max-redirects", httpclient.DefaultMaxRedirects, "maximum number of redirects followed for an HTTP request (negative disables redirects)")
+	cmd.Flags().StringVar(&proxy, "proxy", "", "URL of the HTTP proxy to use (default is taken from HTTP_PROXY, HTTPS_PROXY and NO_PROXY)")
+	cmd.Flags().StringVar(&httpConfig.Username, "auth-user", "", "user name for HTTP basic authentication, sent only to the host of the feed")
+	cmd.Flags().StringVar(&httpConfig.Password, "auth-pass", "", "password for HTTP basic authentication")
+	cmd.Flags().StringVar(&httpConfig.BearerToken, "bearer", "", "bearer token sent in the Authorization header to the host of the feed")
+}
+
+// newFetchClient returns a fetch.Client configured by the flags added by
+// addHTTPFlags, reading standard input from cmd.
+func newFetchClient(cmd *cobra.Command) (*fetch.Client, error) {
+	c := httpConfig
+	if c.BearerToken != "" && c.Username != "" {
+		return nil, fmt.Errorf("--bearer cannot be used with --auth-user")
+	}
+	if proxy != "" {
+		u, err := url.Parse(proxy)
+		if err != nil || !u.IsAbs() {
+			return nil, fmt.Errorf("invalid --proxy %q", proxy)
+		}
+		c.Proxy = u
+	}
+	f := fetch.New(c)
+	f.Stdin = cmd.InOrStdin()
+	return f, nil
+}
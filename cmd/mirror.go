@@ -0,0 +1,167 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/NickolasHKraus/archor/internal/mirror"
+	"github.com/NickolasHKraus/archor/pkg/rss"
+)
+
+var (
+	mirrorOpts mirror.Options
+	since      string
+	dateFormat string
+	dedup      string
+	archiveBy  string
+	transforms []string
+	watch      time.Duration
+	fromFile   string
+	workers    int
+	verbose    bool
+)
+
+// mirrorCmd represents the mirror command
+var mirrorCmd = &cobra.Command{
+	Use:   "mirror <source> [destination] | --from-file <list> [destination]",
+	Short: "Mirror an RSS feed to a local directory",
+	Long: `Mirror fetches the RSS feed at source and writes it to the destination
+directory (default is the current directory). Atom and RSS 1.0 (RDF) feeds
+are converted to RSS 2.0.
+
+Enclosures are downloaded to the destination directory alongside the feed.
+
+The source is an http(s) URL, a file:// URI, a local path, or "-" to read
+the feed from standard input.
+
+With --from-file, the feeds listed in the given list are mirrored
+concurrently. Each line of the list names a feed: its source, optionally
+followed by a destination directory relative to the destination (by default,
+a directory named after the source). Blank lines and lines starting with "#"
+are ignored. The list itself is read like a feed source.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if fromFile != "" {
+			return cobra.MaximumNArgs(1)(cmd, args)
+		}
+		return cobra.RangeArgs(1, 2)(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		o := mirrorOpts
+		o.Destination = "."
+		if fromFile == "" {
+			o.Source, args = args[0], args[1:]
+		}
+		c, err := newFetchClient(cmd)
+		if err != nil {
+			return err
+		}
+		o.Client = c
+		o.Log = cmd.ErrOrStderr()
+		if verbose {
+			o.Logger = slog.New(slog.NewTextHandler(cmd.ErrOrStderr(), nil))
+		}
+		if since != "" {
+			t, err := time.Parse(time.RFC3339, since)
+			if err != nil {
+				return fmt.Errorf("invalid --since: %w", err)
+			}
+			o.Since = t
+		}
+		for _, rule := range transforms {
+			fn, err := mirror.ParseTransform(rule)
+			if err != nil {
+				return err
+			}
+			o.Transforms = append(o.Transforms, fn)
+		}
+		o.Dedup = rss.DedupStrategy(dedup)
+		if !o.Dedup.IsValid() {
+			return fmt.Errorf("invalid --dedup %q", dedup)
+		}
+		for _, id := range o.DisableRules {
+			if !rss.IsLintRule(id) {
+				return fmt.Errorf("invalid --disable-rule %q", id)
+			}
+		}
+		if archiveBy != "" {
+			o.ArchiveBy = mirror.ArchiveBy(archiveBy)
+			if !o.ArchiveBy.IsValid() {
+				return fmt.Errorf("invalid --archive-by %q", archiveBy)
+			}
+		}
+		o.DateFormat = rss.DateFormat(dateFormat)
+		if !o.DateFormat.IsValid() {
+			return fmt.Errorf("invalid --date-format %q", dateFormat)
+		}
+		if len(args) > 0 {
+			o.Destination = args[0]
+		}
+		if fromFile != "" {
+			if watch > 0 {
+				return fmt.Errorf("--watch cannot be used with --from-file")
+			}
+			return mirror.RunList(cmd.Context(), o, fromFile, workers)
+		}
+		if watch > 0 {
+			if o.Source == "-" {
+				return fmt.Errorf("--watch cannot be used with standard input")
+			}
+			return mirror.Watch(cmd.Context(), o, watch)
+		}
+		return mirror.Run(cmd.Context(), o)
+	},
+}
+
+func init() {
+	archorCmd.AddCommand(mirrorCmd)
+
+	addHTTPFlags(mirrorCmd)
+	mirrorCmd.Flags().StringVar(&mirrorOpts.Filename, "filename", mirror.DefaultFilename, "name of the mirrored feed, a template with {{.Title}} and {{.Date}} placeholders")
+	mirrorCmd.Flags().Int64Var(&mirrorOpts.MaxSize, "max-size", 0, "maximum size of the feed in bytes (default is no limit)")
+	mirrorCmd.Flags().IntVar(&mirrorOpts.MaxItemsAllowed, "max-items-allowed", 0, "fail if the feed has more than `n` items (0 means no limit)")
+	mirrorCmd.Flags().BoolVar(&mirrorOpts.Enclosures, "enclosures", true, "download enclosures")
+	mirrorCmd.Flags().Int64Var(&mirrorOpts.MaxTotalSize, "max-total-size", 0, "stop downloading content once its total size would exceed this many `bytes` (0 means no limit)")
+	mirrorCmd.Flags().Int64Var(&mirrorOpts.RateLimit, "rate-limit", 0, "limit content downloads to this many `bytes` per second in aggregate (0 means no limit)")
+	mirrorCmd.Flags().BoolVar(&mirrorOpts.Media, "media", false, "download media:content objects")
+	mirrorCmd.Flags().BoolVar(&mirrorOpts.Repair, "repair", false, "fix common feed problems before writing")
+	mirrorCmd.Flags().BoolVar(&mirrorOpts.NormalizeUnicode, "normalize-unicode", false, "with --repair, normalize titles and descriptions to Unicode NFC")
+	mirrorCmd.Flags().BoolVar(&mirrorOpts.FixMojibake, "fix-mojibake", false, "with --repair, fix double-encoded UTF-8 (e.g. \"cafÃ©\") in titles and descriptions")
+	mirrorCmd.Flags().BoolVar(&mirrorOpts.RequireValid, "require-valid", false, "fail without writing anything if the feed is invalid")
+	mirrorCmd.Flags().StringArrayVar(&mirrorOpts.StripElements, "strip-element", nil, "remove the elements with this `name` (e.g. author or dc:creator) from the channel and items (repeatable)")
+	mirrorCmd.Flags().StringVar(&since, "since", "", "only mirror items published on or after this RFC 3339 date")
+	mirrorCmd.Flags().BoolVar(&mirrorOpts.StrictDates, "strict-dates", false, "with --since, drop items whose publication date is missing or invalid")
+	mirrorCmd.Flags().StringVar(&mirrorOpts.BaseURL, "base-url", "", "resolve relative URLs in the feed against this URL")
+	mirrorCmd.Flags().BoolVar(&mirrorOpts.CleanLinks, "clean-links", false, "remove tracking query parameters from links")
+	mirrorCmd.Flags().StringSliceVar(&mirrorOpts.TrackingParams, "tracking-params", rss.DefaultTrackingParams, "query parameters removed by --clean-links")
+	mirrorCmd.Flags().IntVar(&mirrorOpts.MaxItems, "max-items", 0, "mirror only the `n` most recent items (0 means all)")
+	mirrorCmd.Flags().BoolVar(&mirrorOpts.UTCDates, "utc-dates", false, "convert dates to UTC")
+	mirrorCmd.Flags().StringVar(&dateFormat, "date-format", string(rss.DateFormatRFC1123Z), "format of dates: rfc822, rfc1123z or rfc3339")
+	mirrorCmd.Flags().BoolVar(&mirrorOpts.PreserveDates, "preserve-dates", false, "keep the publication and last build dates of the channel")
+	mirrorCmd.Flags().BoolVar(&mirrorOpts.Sanitize, "sanitize", false, "remove unsafe HTML from item descriptions and content")
+	mirrorCmd.Flags().StringArrayVar(&transforms, "transform", nil, "rewrite each item using a `rule`: title-prefix=<text>, title-suffix=<text> or description-prefix=<text> (repeatable)")
+	mirrorCmd.Flags().BoolVar(&mirrorOpts.Strict, "strict", false, "treat warnings, such as content not matching its declared type, as errors (except recommendations, such as missing guids)")
+	mirrorCmd.Flags().DurationVar(&mirrorOpts.FutureDateThreshold, "future-date-threshold", rss.DefaultFutureDateThreshold, "warn about items dated more than this `duration` in the future (negative disables the check)")
+	mirrorCmd.Flags().BoolVar(&mirrorOpts.Force, "force", false, "rewrite the feed even if it is identical to the previously mirrored one")
+	mirrorCmd.Flags().BoolVar(&mirrorOpts.Podcast, "podcast", false, "mirror the feed as a podcast: warn about items without a valid enclosure (an error with --strict) and keep iTunes elements prefixed")
+	mirrorCmd.Flags().StringSliceVar(&mirrorOpts.DisableRules, "disable-rule", nil, "do not check the lint rule with this `id`: "+strings.Join(rss.LintRules(), ", ")+" (repeatable)")
+	mirrorCmd.Flags().DurationVar(&watch, "watch", 0, "mirror the feed repeatedly at this `interval`, honoring the ttl, skipHours and skipDays of the channel")
+	mirrorCmd.Flags().StringVar(&fromFile, "from-file", "", "mirror the feeds listed in this `list`, one source and optional destination per line")
+	mirrorCmd.Flags().IntVar(&workers, "workers", mirror.DefaultWorkers, "with --from-file, number of feeds mirrored concurrently")
+	mirrorCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "log the operations of the mirror")
+	mirrorCmd.Flags().BoolVar(&mirrorOpts.PreserveGenerator, "preserve-generator", false, "keep the generator of the source feed instead of identifying archor")
+	mirrorCmd.Flags().BoolVar(&mirrorOpts.KeepOriginal, "keep-original", false, "also write the feed as fetched, before any changes")
+	mirrorCmd.Flags().BoolVar(&mirrorOpts.Gzip, "gzip", false, "compress the mirrored feed with gzip, adding a .gz extension")
+	mirrorCmd.Flags().BoolVar(&mirrorOpts.SplitItems, "split-items", false, "also write each item to its own file in the items directory")
+	mirrorCmd.Flags().BoolVar(&mirrorOpts.Sitemap, "sitemap", false, "also write a sitemap.xml listing the link of each item")
+	mirrorCmd.Flags().StringVar(&archiveBy, "archive-by", "", "also write the items to a feed for each `period` of publication: month (e.g. feed-2023-06.xml) or year")
+	mirrorCmd.Flags().StringArrayVar(&mirrorOpts.Merge, "merge", nil, "merge the items of the feed at this `source` into the mirrored feed (repeatable)")
+	mirrorCmd.Flags().StringVar(&dedup, "dedup", string(rss.DedupGUIDOrLink), "key identifying duplicate items when merging: guid-or-link, guid, link or title+link")
+	mirrorCmd.Flags().StringVar(&mirrorOpts.NotifyURL, "notify-url", "", "URL to POST a JSON notification to after a mirror that changed the feed")
+}
@@ -0,0 +1,28 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/NickolasHKraus/archor/internal/selftest"
+)
+
+// selftestCmd represents the selftest command
+var selftestCmd = &cobra.Command{
+	Use:   "selftest",
+	Short: "Check that archor works end to end",
+	Long: `Selftest generates a feed in memory, marshals it, parses it again,
+validates it and converts it to Atom and JSON Feed, reporting the result of
+each step. It exits with a non-zero status if a step fails.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return selftest.Run(cmd.OutOrStdout())
+	},
+}
+
+func init() {
+	archorCmd.AddCommand(selftestCmd)
+}
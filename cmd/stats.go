@@ -0,0 +1,42 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/NickolasHKraus/archor/internal/stats"
+)
+
+// statsCmd represents the stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats <source>",
+	Short: "Print statistics of a feed",
+	Long: `Stats reads the RSS 2.0, Atom or RSS 1.0 (RDF) feed at source and prints its
+number of items, the range of their publication dates, the average number of
+items published per day, the number of items in each category, and whether
+the feed has enclosures and an image.
+
+The source is an http(s) URL, a file:// URI, a local path, or "-" to read
+the feed from standard input.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := newFetchClient(cmd)
+		if err != nil {
+			return err
+		}
+		r, err := c.Fetch(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+		return stats.Compute(r).Write(cmd.OutOrStdout())
+	},
+}
+
+func init() {
+	archorCmd.AddCommand(statsCmd)
+
+	addHTTPFlags(statsCmd)
+}
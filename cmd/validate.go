@@ -0,0 +1,59 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/NickolasHKraus/archor/internal/report"
+)
+
+var validateFormat string
+
+// validateCmd represents the validate command
+var validateCmd = &cobra.Command{
+	Use:   "validate <source>",
+	Short: "Validate a feed against the RSS 2.0 specification",
+	Long: `Validate reads the RSS 2.0, Atom or RSS 1.0 (RDF) feed at source, validates
+it against the RSS 2.0 specification and prints a report of the invalid
+elements in the format given by --format: text, or json for a report of the
+form {"valid": bool, "errors": [{"path": string, "message": string}]}.
+Validate exits with a non-zero status if the feed is invalid.
+
+The source is an http(s) URL, a file:// URI, a local path, or "-" to read
+the feed from standard input.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		f := report.Format(validateFormat)
+		if f != report.Text && f != report.JSON {
+			return fmt.Errorf("invalid --format %q", validateFormat)
+		}
+		c, err := newFetchClient(cmd)
+		if err != nil {
+			return err
+		}
+		r, err := c.Fetch(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+		rep := report.New(r)
+		if err := rep.Write(cmd.OutOrStdout(), f); err != nil {
+			return err
+		}
+		if !rep.Valid {
+			return fmt.Errorf("%s is invalid", args[0])
+		}
+		return nil
+	},
+}
+
+func init() {
+	archorCmd.AddCommand(validateCmd)
+
+	addHTTPFlags(validateCmd)
+	validateCmd.Flags().StringVar(&validateFormat, "format", string(report.Text), "report format: text or json")
+}
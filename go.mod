@@ -1,10 +1,12 @@
 module github.com/NickolasHKraus/archor
 
-go 1.19
+go 1.21
 
 require (
 	github.com/spf13/cobra v1.6.1
 	github.com/spf13/viper v1.14.0
+	golang.org/x/net v0.0.0-20221014081412-f15817d10f9b
+	golang.org/x/text v0.4.0
 )
 
 require (
@@ -21,7 +23,6 @@ require (
 	github.com/spf13/pflag v1.0.5 // indirect
 	github.com/subosito/gotenv v1.4.1 // indirect
 	golang.org/x/sys v0.0.0-20220908164124-27713097b956 // indirect
-	golang.org/x/text v0.4.0 // indirect
 	gopkg.in/ini.v1 v1.67.0 // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
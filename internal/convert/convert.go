@@ -0,0 +1,54 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package convert converts feeds between the formats supported by archor.
+package convert
+
+import (
+	"fmt"
+
+	"github.com/NickolasHKraus/archor/pkg/rss"
+)
+
+// Format is a feed format to which a feed can be converted.
+type Format string
+
+const (
+	// RSS is RSS 2.0.
+	RSS Format = "rss"
+	// Atom is Atom 1.0.
+	Atom Format = "atom"
+	// JSON is JSON Feed.
+	JSON Format = "json"
+)
+
+// Options configures the conversion of a feed.
+type Options struct {
+	// Indent, if set, is the indentation of each nesting level of JSON
+	// output. RSS and Atom output is always indented.
+	Indent string
+}
+
+// Convert returns the encoding of r in the format to using the default
+// options.
+func Convert(r *rss.RSS, to Format) ([]byte, error) {
+	return Options{}.Convert(r, to)
+}
+
+// Convert returns the encoding of r in the format to using the options in o.
+func (o Options) Convert(r *rss.RSS, to Format) ([]byte, error) {
+	switch to {
+	case RSS:
+		return rss.Marshal(r)
+	case Atom:
+		return r.ToAtom()
+	case JSON:
+		b, err := rss.JSONFeedOptions{Indent: o.Indent}.Marshal(r)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, '\n'), nil
+	}
+	return nil, fmt.Errorf("unsupported format %q", to)
+}
@@ -0,0 +1,96 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package convert
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/NickolasHKraus/archor/pkg/rss"
+)
+
+func readFeed(t *testing.T, name string) *rss.RSS {
+	t.Helper()
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	r, err := rss.ParseAny(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestConvert(t *testing.T) {
+	r := readFeed(t, "../../test/data/rss-0.xml")
+	for _, to := range []Format{RSS, Atom} {
+		b, err := Convert(r, to)
+		if err != nil {
+			t.Fatalf("Convert(%s): %v", to, err)
+		}
+		got, err := rss.ParseAny(bytes.NewReader(b))
+		if err != nil {
+			t.Fatalf("Convert(%s): %v", to, err)
+		}
+		if got.Channel.Title != "Liftoff News" || len(got.Channel.Item) != 4 {
+			t.Errorf("Convert(%s) = %q with %d items, want %q with 4 items", to, got.Channel.Title, len(got.Channel.Item), "Liftoff News")
+		}
+	}
+
+	b, err := Convert(r, JSON)
+	if err != nil {
+		t.Fatalf("Convert(json): %v", err)
+	}
+	var feed struct {
+		Version string `json:"version"`
+		Title   string `json:"title"`
+		Items   []struct {
+			ID  string `json:"id"`
+			URL string `json:"url"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(b, &feed); err != nil {
+		t.Fatal(err)
+	}
+	if feed.Version != rss.JSONFeedVersion || feed.Title != "Liftoff News" || len(feed.Items) != 4 {
+		t.Errorf("Convert(json) = %s", b)
+	}
+	if want := "http://liftoff.msfc.nasa.gov/2003/06/03.html#item573"; feed.Items[0].ID != want {
+		t.Errorf("items[0].id = %q, want %q", feed.Items[0].ID, want)
+	}
+
+	if _, err := Convert(r, "yaml"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+func TestConvertIndent(t *testing.T) {
+	r := readFeed(t, "../../test/data/rss-0.xml")
+	compact, err := Convert(r, JSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	indented, err := Options{Indent: "  "}.Convert(r, JSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Count(compact, []byte("\n")) != 1 || !bytes.Contains(indented, []byte("\n  \"title\": \"Liftoff News\"")) {
+		t.Errorf("Convert() =\n%s\nindented =\n%s", compact, indented)
+	}
+	var a, b interface{}
+	if err := json.Unmarshal(compact, &a); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(indented, &b); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(a, b) {
+		t.Error("indented JSON Feed differs from compact JSON Feed")
+	}
+}
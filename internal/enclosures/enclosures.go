@@ -0,0 +1,79 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package enclosures lists the enclosures of a feed.
+package enclosures
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/NickolasHKraus/archor/pkg/rss"
+)
+
+// Format is a format in which enclosures can be written.
+type Format string
+
+const (
+	// Text is the URL of each enclosure, one per line.
+	Text Format = "text"
+	// JSON is a JSON array of Enclosure objects.
+	JSON Format = "json"
+)
+
+// Enclosure is an enclosure of an item of a feed.
+type Enclosure struct {
+	// URL is the URL of the enclosure.
+	URL string `json:"url"`
+	// Type is the MIME type of the enclosure.
+	Type string `json:"type"`
+	// Length is the size of the enclosure in bytes, or 0 if it is not a
+	// valid number.
+	Length int64 `json:"length"`
+}
+
+// List returns the enclosures of the items of r, in order. Items without an
+// enclosure, or with an enclosure without a URL, are skipped.
+func List(r *rss.RSS) []Enclosure {
+	encs := []Enclosure{}
+	if r.Channel == nil {
+		return encs
+	}
+	for _, item := range r.Channel.Item {
+		e := item.Enclosure
+		if e == nil || e.URL == "" {
+			continue
+		}
+		length, _ := strconv.ParseInt(strings.TrimSpace(e.Length), 10, 64)
+		encs = append(encs, Enclosure{URL: string(e.URL), Type: e.Type, Length: length})
+	}
+	return encs
+}
+
+// Write writes encs to w in the format f.
+func Write(w io.Writer, encs []Enclosure, f Format) error {
+	switch f {
+	case Text:
+		for _, e := range encs {
+			if _, err := fmt.Fprintln(w, e.URL); err != nil {
+				return err
+			}
+		}
+		return nil
+	case JSON:
+		if encs == nil {
+			encs = []Enclosure{}
+		}
+		b, err := json.MarshalIndent(encs, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(append(b, '\n'))
+		return err
+	}
+	return fmt.Errorf("unsupported format %q", f)
+}
@@ -0,0 +1,89 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package enclosures
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/NickolasHKraus/archor/pkg/rss"
+)
+
+const testFeed = "../../test/data/rss-enclosures.xml"
+
+// want are the enclosures of testFeed.
+var want = []Enclosure{
+	{URL: "http://example.com/episodes/1.mp3", Type: "audio/mpeg", Length: 12216320},
+	{URL: "http://example.com/episodes/2.m4a", Type: "audio/x-m4a", Length: 24986239},
+	{URL: "http://example.com/episodes/3.mp4", Type: "video/mp4"},
+}
+
+func readFeed(t *testing.T, name string) *rss.RSS {
+	t.Helper()
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	r, err := rss.ParseAny(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestList(t *testing.T) {
+	if got := List(readFeed(t, testFeed)); !reflect.DeepEqual(got, want) {
+		t.Errorf("List() = %+v, want %+v", got, want)
+	}
+	if got := List(readFeed(t, "../../test/data/rss-0.xml")); got == nil || len(got) != 0 {
+		t.Errorf("List() = %#v, want an empty list", got)
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, want, Text); err != nil {
+		t.Fatal(err)
+	}
+	const text = `http://example.com/episodes/1.mp3
+http://example.com/episodes/2.m4a
+http://example.com/episodes/3.mp4
+`
+	if buf.String() != text {
+		t.Errorf("Write() =\n%s\nwant\n%s", buf.String(), text)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	tests := [][]Enclosure{want, nil}
+	for _, encs := range tests {
+		var buf bytes.Buffer
+		if err := Write(&buf, encs, JSON); err != nil {
+			t.Fatal(err)
+		}
+		var got []map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("Write() = %s: %v", buf.String(), err)
+		}
+		if got == nil || len(got) != len(encs) {
+			t.Errorf("Write() = %s, want %d enclosures", buf.String(), len(encs))
+			continue
+		}
+		for i, e := range encs {
+			if got[i]["url"] != e.URL || got[i]["type"] != e.Type || got[i]["length"] != float64(e.Length) {
+				t.Errorf("enclosure %d = %v, want %+v", i, got[i], e)
+			}
+		}
+	}
+}
+
+func TestWriteUnsupportedFormat(t *testing.T) {
+	if err := Write(&bytes.Buffer{}, want, Format("csv")); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
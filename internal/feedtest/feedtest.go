@@ -0,0 +1,63 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package feedtest provides utilities for testing code that handles feeds.
+package feedtest
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/NickolasHKraus/archor/pkg/rss"
+)
+
+// epoch is the publication date of the most recent item of a generated feed.
+var epoch = time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+var words = []string{
+	"apollo", "booster", "capsule", "docking", "engine", "flight", "gravity",
+	"horizon", "launch", "lunar", "mission", "module", "orbit", "payload",
+	"rocket", "satellite", "shuttle", "station", "telemetry", "thrust",
+}
+
+// GenerateFeed returns a valid feed with nItems items. The titles,
+// descriptions, links, guids and publication dates of the items are derived
+// from seed, so that the same seed always generates the same feed. Items are
+// ordered from newest to oldest, and their guids are unique.
+func GenerateFeed(nItems int, seed int64) *rss.RSS {
+	rnd := rand.New(rand.NewSource(seed))
+	c := &rss.Channel{
+		Title:         rss.Title(fmt.Sprintf("Generated Feed %d", seed)),
+		Link:          rss.Link(fmt.Sprintf("http://example.com/%d/", seed)),
+		Description:   rss.Description(fmt.Sprintf("A feed generated from seed %d.", seed)),
+		Language:      "en-us",
+		LastBuildDate: rss.LastBuildDate(epoch.Format(time.RFC1123Z)),
+	}
+	date := epoch
+	for i := 0; i < nItems; i++ {
+		id := fmt.Sprintf("%d-%d-%08x", seed, i, rnd.Uint32())
+		link := fmt.Sprintf("http://example.com/%d/items/%s", seed, id)
+		c.Item = append(c.Item, &rss.Item{
+			Title:       rss.Title(phrase(rnd, 4)),
+			Link:        rss.Link(link),
+			Description: rss.Description(phrase(rnd, 12) + "."),
+			GUID:        &rss.GUID{Value: link},
+			PubDate:     rss.PubDate(date.Format(time.RFC1123Z)),
+		})
+		date = date.Add(-time.Duration(1+rnd.Intn(48*60)) * time.Minute)
+	}
+	return &rss.RSS{Version: "2.0", Channel: c}
+}
+
+// phrase returns n random words, the first capitalized.
+func phrase(rnd *rand.Rand, n int) string {
+	w := make([]string, n)
+	for i := range w {
+		w[i] = words[rnd.Intn(len(words))]
+	}
+	w[0] = strings.ToUpper(w[0][:1]) + w[0][1:]
+	return strings.Join(w, " ")
+}
@@ -0,0 +1,57 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package feedtest
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/NickolasHKraus/archor/pkg/rss"
+)
+
+func TestGenerateFeed(t *testing.T) {
+	r := GenerateFeed(100, 1)
+	if err := r.Validate(); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+	if got := len(r.Channel.Item); got != 100 {
+		t.Fatalf("len(Channel.Item) = %d, want 100", got)
+	}
+	guids := make(map[string]bool)
+	for i, item := range r.Channel.Item {
+		if guids[item.GUID.Value] {
+			t.Errorf("item[%d]: duplicate guid %q", i, item.GUID.Value)
+		}
+		guids[item.GUID.Value] = true
+		if i == 0 {
+			continue
+		}
+		prev, _ := r.Channel.Item[i-1].PubDate.Time()
+		cur, _ := item.PubDate.Time()
+		if !cur.Before(prev) {
+			t.Errorf("item[%d] published %s, not before item[%d] (%s)", i, cur, i-1, prev)
+		}
+	}
+}
+
+func TestGenerateFeedReproducible(t *testing.T) {
+	a, err := rss.Marshal(GenerateFeed(10, 42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := rss.Marshal(GenerateFeed(10, 42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(a, b) {
+		t.Errorf("feeds generated from the same seed differ:\n%s\n%s", a, b)
+	}
+	c, err := rss.Marshal(GenerateFeed(10, 43))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(a, c) {
+		t.Error("feeds generated from different seeds are equal")
+	}
+}
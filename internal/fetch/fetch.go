@@ -0,0 +1,196 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package fetch fetches and parses feeds.
+package fetch
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/NickolasHKraus/archor/internal/httpclient"
+	"github.com/NickolasHKraus/archor/internal/source"
+	"github.com/NickolasHKraus/archor/pkg/rss"
+)
+
+// Client fetches feeds. It retries failed requests as configured by its
+// httpclient.Config, sends the credentials of the httpclient.Config only to
+// the host of the feed and, when fetching a feed it fetched before, sends a
+// conditional request using the ETag and Last-Modified headers of the
+// previous response, so that an unchanged feed is not downloaded again.
+//
+// A Client is safe for concurrent use.
+type Client struct {
+	// Config configures the HTTP client used for requests.
+	Config httpclient.Config
+	// Stdin is read when fetching "-". If nil, os.Stdin is read.
+	Stdin io.Reader
+
+	once sync.Once
+	http *http.Client
+
+	mu    sync.Mutex
+	cache map[string]entry
+}
+
+// entry is a feed fetched over HTTP, along with the validators of the
+// response with which it was fetched.
+type entry struct {
+	etag         string
+	lastModified string
+	body         []byte
+}
+
+// Response is a feed fetched by Client.Get.
+type Response struct {
+	// Feed is the parsed feed.
+	Feed *rss.RSS
+	// Body is the feed as fetched, decompressed if it was compressed with
+	// gzip.
+	Body []byte
+	// URL is the URL from which the feed was fetched, after following
+	// redirects. It is empty if the feed was not fetched over HTTP.
+	URL string
+	// NotModified reports whether the server responded with 304 Not
+	// Modified, in which case Body is that of the previous response.
+	NotModified bool
+}
+
+// New returns a Client making requests with an HTTP client configured using
+// c.
+func New(c httpclient.Config) *Client {
+	return &Client{Config: c}
+}
+
+// HTTP returns the HTTP client used for requests, which is configured using
+// c.Config when first needed.
+func (c *Client) HTTP() *http.Client {
+	c.once.Do(func() {
+		c.http = httpclient.New(c.Config)
+	})
+	return c.http
+}
+
+// Fetch fetches and parses the RSS 2.0, Atom or RSS 1.0 (RDF) feed at url,
+// which is any source accepted by source.Open. If the server responds to a
+// conditional request with 304 Not Modified, the feed is parsed from the
+// body of the previous response.
+func (c *Client) Fetch(ctx context.Context, url string) (*rss.RSS, error) {
+	resp, err := c.Get(ctx, url, rss.ParseOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Feed, nil
+}
+
+// Get is like Fetch, but also returns the body of the feed and the URL from
+// which it was fetched, and parses the feed with o. The o.MaxBytes limit
+// applies to the feed once decompressed.
+func (c *Client) Get(ctx context.Context, url string, o rss.ParseOptions) (*Response, error) {
+	if !source.IsHTTP(url) {
+		return c.open(ctx, url, o)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(httpclient.WithCredentials(ctx, req.URL.Host))
+	c.mu.Lock()
+	prev, cached := c.cache[url]
+	c.mu.Unlock()
+	if cached {
+		if prev.etag != "" {
+			req.Header.Set("If-None-Match", prev.etag)
+		}
+		if prev.lastModified != "" {
+			req.Header.Set("If-Modified-Since", prev.lastModified)
+		}
+	}
+	resp, err := c.HTTP().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	final := resp.Request.URL.String()
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached:
+		r, err := o.ParseAny(bytes.NewReader(prev.body))
+		if err != nil {
+			return nil, err
+		}
+		return &Response{Feed: r, Body: prev.body, URL: final, NotModified: true}, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("GET %s: unexpected status %s", url, resp.Status)
+	}
+	rc, err := source.Decompress(resp.Body, strings.HasSuffix(url, ".gz"))
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	body, err := readAll(rc, o.MaxBytes)
+	if err != nil {
+		return nil, err
+	}
+	r, err := o.ParseAny(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	e := entry{etag: resp.Header.Get("ETag"), lastModified: resp.Header.Get("Last-Modified"), body: body}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e.etag == "" && e.lastModified == "" {
+		delete(c.cache, url)
+	} else {
+		if c.cache == nil {
+			c.cache = make(map[string]entry)
+		}
+		c.cache[url] = e
+	}
+	return &Response{Feed: r, Body: body, URL: final}, nil
+}
+
+// open reads and parses the feed at the source url, which is not fetched
+// over HTTP.
+func (c *Client) open(ctx context.Context, url string, o rss.ParseOptions) (*Response, error) {
+	stdin := c.Stdin
+	if stdin == nil {
+		stdin = os.Stdin
+	}
+	rc, err := source.Open(ctx, c.HTTP(), url, stdin)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	body, err := readAll(rc, o.MaxBytes)
+	if err != nil {
+		return nil, err
+	}
+	r, err := o.ParseAny(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	return &Response{Feed: r, Body: body}, nil
+}
+
+// readAll reads r until EOF. If maxBytes is positive, it returns
+// rss.ErrFeedTooLarge if r is longer than maxBytes.
+func readAll(r io.Reader, maxBytes int64) ([]byte, error) {
+	if maxBytes <= 0 {
+		return io.ReadAll(r)
+	}
+	b, err := io.ReadAll(io.LimitReader(r, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(b)) > maxBytes {
+		return nil, rss.ErrFeedTooLarge
+	}
+	return b, nil
+}
@@ -0,0 +1,243 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package fetch
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/NickolasHKraus/archor/internal/httpclient"
+	"github.com/NickolasHKraus/archor/pkg/rss"
+)
+
+const testFeed = "../../test/data/rss-0.xml"
+
+func TestFetchRetries(t *testing.T) {
+	b, err := os.ReadFile(testFeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(b)
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		retries int
+		wantErr bool
+	}{
+		{1, true},
+		{2, false},
+	}
+	for _, tt := range tests {
+		atomic.StoreInt32(&requests, 0)
+		c := New(httpclient.Config{Retries: tt.retries})
+		r, err := c.Fetch(context.Background(), ts.URL)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Retries=%d: Fetch() error = %v, wantErr %v", tt.retries, err, tt.wantErr)
+			continue
+		}
+		if err == nil && r.Channel.Title == "" {
+			t.Errorf("Retries=%d: Fetch() returned a feed without a title", tt.retries)
+		}
+		if got := atomic.LoadInt32(&requests); got != int32(tt.retries)+1 {
+			t.Errorf("Retries=%d: %d requests, want %d", tt.retries, got, tt.retries+1)
+		}
+	}
+}
+
+func TestFetchNotModified(t *testing.T) {
+	b, err := os.ReadFile(testFeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const etag = `"v1"`
+	const lastModified = "Mon, 29 May 2023 10:00:00 GMT"
+	tests := []struct {
+		name    string
+		header  string
+		value   string
+		request string
+	}{
+		{"ETag", "ETag", etag, "If-None-Match"},
+		{"Last-Modified", "Last-Modified", lastModified, "If-Modified-Since"},
+	}
+	for _, tt := range tests {
+		var requests, notModified int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			if r.Header.Get(tt.request) == tt.value {
+				atomic.AddInt32(&notModified, 1)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set(tt.header, tt.value)
+			w.Write(b)
+		}))
+		c := New(httpclient.Config{})
+		first, err := c.Fetch(context.Background(), ts.URL)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		second, err := c.Fetch(context.Background(), ts.URL)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		ts.Close()
+		if requests != 2 || notModified != 1 {
+			t.Errorf("%s: %d requests, %d not modified, want 2 and 1", tt.name, requests, notModified)
+		}
+		if !first.Equal(second) {
+			t.Errorf("%s: feed fetched after 304 differs from the feed fetched before", tt.name)
+		}
+	}
+}
+
+func TestFetchWithoutValidators(t *testing.T) {
+	b, err := os.ReadFile(testFeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var conditional int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" || r.Header.Get("If-Modified-Since") != "" {
+			atomic.AddInt32(&conditional, 1)
+		}
+		w.Write(b)
+	}))
+	defer ts.Close()
+
+	c := New(httpclient.Config{})
+	for i := 0; i < 2; i++ {
+		if _, err := c.Fetch(context.Background(), ts.URL); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if conditional != 0 {
+		t.Errorf("%d conditional requests, want 0", conditional)
+	}
+}
+
+func TestFetchUnexpectedStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A 304 without a previous response cannot be served from the cache.
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer ts.Close()
+
+	_, err := New(httpclient.Config{}).Fetch(context.Background(), ts.URL)
+	if err == nil || !strings.Contains(err.Error(), "304") {
+		t.Errorf("Fetch() error = %v, want unexpected status 304", err)
+	}
+}
+
+func TestFetchSources(t *testing.T) {
+	b, err := os.ReadFile(testFeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{"path", testFeed},
+		{"gzip", testFeed + ".gz"},
+		{"atom", "../../test/data/atom-0.xml"},
+		{"stdin", "-"},
+	}
+	for _, tt := range tests {
+		c := &Client{Stdin: strings.NewReader(string(b))}
+		r, err := c.Fetch(context.Background(), tt.url)
+		if err != nil {
+			t.Errorf("%s: Fetch(%q) error = %v", tt.name, tt.url, err)
+			continue
+		}
+		if r.Channel.Title == "" {
+			t.Errorf("%s: Fetch(%q) returned a feed without a title", tt.name, tt.url)
+		}
+	}
+}
+
+func TestGet(t *testing.T) {
+	b, err := os.ReadFile(testFeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write(b)
+	zw.Close()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/feed.xml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(b)
+	})
+	mux.HandleFunc("/feed.xml.gz", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(gz.Bytes())
+	})
+	mux.Handle("/old.xml", http.RedirectHandler("/feed.xml", http.StatusMovedPermanently))
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	tests := []struct {
+		name    string
+		url     string
+		wantURL string
+	}{
+		{"http", ts.URL + "/feed.xml", ts.URL + "/feed.xml"},
+		{"redirect", ts.URL + "/old.xml", ts.URL + "/feed.xml"},
+		{"gzip", ts.URL + "/feed.xml.gz", ts.URL + "/feed.xml.gz"},
+		{"path", testFeed, ""},
+	}
+	for _, tt := range tests {
+		resp, err := New(httpclient.Config{}).Get(context.Background(), tt.url, rss.ParseOptions{})
+		if err != nil {
+			t.Errorf("%s: Get(%q) error = %v", tt.name, tt.url, err)
+			continue
+		}
+		if resp.URL != tt.wantURL {
+			t.Errorf("%s: URL = %q, want %q", tt.name, resp.URL, tt.wantURL)
+		}
+		if !bytes.Equal(resp.Body, b) {
+			t.Errorf("%s: Body differs from the feed", tt.name)
+		}
+		if resp.Feed.Channel.Title == "" {
+			t.Errorf("%s: Get(%q) returned a feed without a title", tt.name, tt.url)
+		}
+	}
+}
+
+func TestGetLimit(t *testing.T) {
+	b, err := os.ReadFile(testFeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(b)
+	}))
+	defer ts.Close()
+
+	size := int64(len(b))
+	for _, url := range []string{ts.URL, testFeed} {
+		c := New(httpclient.Config{})
+		if _, err := c.Get(context.Background(), url, rss.ParseOptions{MaxBytes: size}); err != nil {
+			t.Errorf("Get(%q, %d) error = %v", url, size, err)
+		}
+		if _, err := c.Get(context.Background(), url, rss.ParseOptions{MaxBytes: size - 1}); !errors.Is(err, rss.ErrFeedTooLarge) {
+			t.Errorf("Get(%q, %d) error = %v, want ErrFeedTooLarge", url, size-1, err)
+		}
+	}
+}
@@ -1,4 +1,189 @@
 // Copyright 2022 Nickolas Kraus. All rights reserved.
 // Use of this source code is governed by a MIT-style
 // license that can be found in the LICENSE file.
+
+// Package httpclient provides the HTTP client used to fetch feeds and their
+// content.
 package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/NickolasHKraus/archor/internal/version"
+)
+
+const (
+	// DefaultTimeout is the default time limit for an HTTP request.
+	DefaultTimeout = 30 * time.Second
+	// DefaultRetries is the default number of times a failed request is
+	// retried.
+	DefaultRetries = 2
+	// DefaultRetryBackoff is the default delay before the first retry.
+	DefaultRetryBackoff = time.Second
+	// DefaultMaxRedirects is the default number of redirects followed for a
+	// request.
+	DefaultMaxRedirects = 10
+)
+
+var (
+	// ErrTooManyRedirects is returned when a request is redirected more
+	// than Config.MaxRedirects times.
+	ErrTooManyRedirects = errors.New("too many redirects")
+	// ErrRedirectLoop is returned when a request is redirected to a URL it
+	// was already redirected from.
+	ErrRedirectLoop = errors.New("redirect loop")
+)
+
+// Config configures an HTTP client.
+type Config struct {
+	// Timeout is the time limit for a request, including reading the
+	// response body. A zero value means no timeout.
+	Timeout time.Duration
+	// Retries is the number of times a request is retried after a network
+	// error or a 429 or 5xx response.
+	Retries int
+	// RetryBackoff is the delay before the first retry. The delay doubles
+	// with each subsequent retry.
+	RetryBackoff time.Duration
+	// Proxy, if set, is the proxy through which all requests are sent. By
+	// default, the proxy is taken from the HTTP_PROXY, HTTPS_PROXY and
+	// NO_PROXY environment variables.
+	Proxy *url.URL
+	// Username and Password, if Username is set, are sent using HTTP basic
+	// authentication with the requests to the host given to
+	// WithCredentials.
+	Username string
+	Password string
+	// BearerToken, if set, is sent in an Authorization header with the
+	// requests to the host given to WithCredentials. It takes precedence
+	// over Username and Password.
+	BearerToken string
+	// MaxRedirects is the number of redirects followed for a request. If
+	// zero, DefaultMaxRedirects is used. If negative, redirects are not
+	// followed, and the redirect response is returned.
+	MaxRedirects int
+}
+
+// UserAgent returns the value of the User-Agent header set on all requests.
+func UserAgent() string {
+	return "archor/" + version.Version
+}
+
+// credentialsKey is the context key of the host given to WithCredentials.
+type credentialsKey struct{}
+
+// WithCredentials returns a copy of ctx with which requests to host, a host or
+// host:port as in url.URL.Host, carry the credentials of the Config of the
+// client. Requests to any other host, such as a host the request is
+// redirected to or a host a feed links to, never carry them, nor do requests
+// made with a context not returned by WithCredentials.
+func WithCredentials(ctx context.Context, host string) context.Context {
+	return context.WithValue(ctx, credentialsKey{}, host)
+}
+
+// New returns an HTTP client configured using c.
+func New(c Config) *http.Client {
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	base.Proxy = http.ProxyFromEnvironment
+	if c.Proxy != nil {
+		base.Proxy = http.ProxyURL(c.Proxy)
+	}
+	return &http.Client{
+		Timeout:       c.Timeout,
+		Transport:     &transport{base: base, config: c},
+		CheckRedirect: checkRedirect(c.MaxRedirects),
+	}
+}
+
+// checkRedirect returns the redirect policy of a client following at most max
+// redirects (see Config.MaxRedirects). A redirect to a URL already requested
+// is a loop, and fails with ErrRedirectLoop.
+func checkRedirect(max int) func(*http.Request, []*http.Request) error {
+	if max == 0 {
+		max = DefaultMaxRedirects
+	}
+	return func(req *http.Request, via []*http.Request) error {
+		if max < 0 {
+			return http.ErrUseLastResponse
+		}
+		for _, prev := range via {
+			if prev.URL.String() == req.URL.String() {
+				return fmt.Errorf("%w: %s", ErrRedirectLoop, req.URL)
+			}
+		}
+		if len(via) > max {
+			return fmt.Errorf("%w: stopped after %d", ErrTooManyRedirects, max)
+		}
+		return nil
+	}
+}
+
+// transport is an http.RoundTripper that sets the User-Agent header on all
+// requests, sets the Authorization header on the requests to the host given to
+// WithCredentials and retries failed requests.
+type transport struct {
+	base   http.RoundTripper
+	config Config
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	retries := t.config.Retries
+	if req.Body != nil && req.GetBody == nil {
+		// The body cannot be replayed.
+		retries = 0
+	}
+	backoff := t.config.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		r := req.Clone(req.Context())
+		r.Header.Set("User-Agent", UserAgent())
+		switch {
+		case !authorized(req):
+		case t.config.BearerToken != "":
+			r.Header.Set("Authorization", "Bearer "+t.config.BearerToken)
+		case t.config.Username != "":
+			r.SetBasicAuth(t.config.Username, t.config.Password)
+		}
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r.Body = body
+		}
+		resp, err := t.base.RoundTrip(r)
+		if attempt >= retries || !retryable(resp, err) {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		select {
+		case <-time.After(backoff):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		backoff *= 2
+	}
+}
+
+// authorized returns true if req is to the host given to WithCredentials.
+func authorized(req *http.Request) bool {
+	host, ok := req.Context().Value(credentialsKey{}).(string)
+	return ok && strings.EqualFold(host, req.URL.Host)
+}
+
+// retryable returns true if a request that failed with resp or err may
+// succeed if retried.
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
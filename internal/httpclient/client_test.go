@@ -2,3 +2,309 @@
 // Use of this source code is governed by a MIT-style
 // license that can be found in the LICENSE file.
 package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewTimeout(t *testing.T) {
+	done := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(5 * time.Second):
+		case <-done:
+		}
+	}))
+	defer ts.Close()
+	defer close(done)
+
+	c := New(Config{Timeout: 50 * time.Millisecond})
+	start := time.Now()
+	_, err := c.Get(ts.URL)
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("request took %v, want ~50ms", elapsed)
+	}
+}
+
+func TestNewUserAgent(t *testing.T) {
+	var got string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("User-Agent")
+	}))
+	defer ts.Close()
+
+	resp, err := New(Config{Timeout: DefaultTimeout}).Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if want := "archor/dev"; got != want {
+		t.Errorf("User-Agent = %q, want %q", got, want)
+	}
+}
+
+func TestNewRetries(t *testing.T) {
+	var attempts int32
+	var bodies []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+
+	c := New(Config{Retries: 2, RetryBackoff: time.Millisecond})
+	resp, err := c.Post(ts.URL, "text/plain", strings.NewReader("payload"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Errorf("attempts = %d, want 3", got)
+	}
+	for i, b := range bodies {
+		if b != "payload" {
+			t.Errorf("attempt %d body = %q, want %q", i+1, b, "payload")
+		}
+	}
+}
+
+func TestNewRetriesExhausted(t *testing.T) {
+	var attempts int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	resp, err := New(Config{Retries: 1, RetryBackoff: time.Millisecond}).Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", resp.StatusCode)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 2 {
+		t.Errorf("attempts = %d, want 2", got)
+	}
+}
+
+func TestNewNoRetryOnClientError(t *testing.T) {
+	var attempts int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		http.NotFound(w, r)
+	}))
+	defer ts.Close()
+
+	resp, err := New(Config{Retries: 3, RetryBackoff: time.Millisecond}).Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if got := atomic.LoadInt32(&attempts); got != 1 {
+		t.Errorf("attempts = %d, want 1", got)
+	}
+}
+
+func TestNewProxy(t *testing.T) {
+	var got string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.String()
+	}))
+	defer proxy.Close()
+	u, err := url.Parse(proxy.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := New(Config{Timeout: DefaultTimeout, Proxy: u}).Get("http://feeds.example.com/rss.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if want := "http://feeds.example.com/rss.xml"; got != want {
+		t.Errorf("proxied request = %q, want %q", got, want)
+	}
+}
+
+func TestNewAuthorization(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		ok     func(r *http.Request) bool
+	}{
+		{
+			"basic",
+			Config{Username: "alice", Password: "s3cret"},
+			func(r *http.Request) bool {
+				user, pass, ok := r.BasicAuth()
+				return ok && user == "alice" && pass == "s3cret"
+			},
+		},
+		{
+			"bearer",
+			Config{BearerToken: "t0ken"},
+			func(r *http.Request) bool {
+				return r.Header.Get("Authorization") == "Bearer t0ken"
+			},
+		},
+	}
+	for _, tt := range tests {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !tt.ok(r) {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		}))
+		for _, c := range []Config{{}, tt.config} {
+			req, err := http.NewRequestWithContext(WithCredentials(context.Background(), ts.Listener.Addr().String()), http.MethodGet, ts.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := New(c).Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			want := http.StatusOK
+			if c == (Config{}) {
+				want = http.StatusUnauthorized
+			}
+			if resp.StatusCode != want {
+				t.Errorf("%s: StatusCode = %d, want %d", tt.name, resp.StatusCode, want)
+			}
+		}
+		ts.Close()
+	}
+}
+
+func TestNewAuthorizationOtherHost(t *testing.T) {
+	var other atomic.Value
+	other.Store("")
+	ts2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		other.Store(r.Header.Get("Authorization"))
+	}))
+	defer ts2.Close()
+	var feed atomic.Value
+	feed.Store("")
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		feed.Store(r.Header.Get("Authorization"))
+		http.Redirect(w, r, ts2.URL, http.StatusFound)
+	}))
+	defer ts.Close()
+
+	c := New(Config{BearerToken: "secret"})
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		wantFeed string
+	}{
+		{"with credentials", WithCredentials(context.Background(), ts.Listener.Addr().String()), "Bearer secret"},
+		{"without credentials", context.Background(), ""},
+	}
+	for _, tt := range tests {
+		req, err := http.NewRequestWithContext(tt.ctx, http.MethodGet, ts.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := c.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if got := feed.Load().(string); got != tt.wantFeed {
+			t.Errorf("%s: Authorization = %q, want %q", tt.name, got, tt.wantFeed)
+		}
+		if got := other.Load().(string); got != "" {
+			t.Errorf("%s: Authorization after redirect to another host = %q, want none", tt.name, got)
+		}
+	}
+}
+
+// newRedirectServer returns a server on which /r/<n> redirects to /r/<n-1>,
+// /r/0 responds with "ok", and /loop/a and /loop/b redirect to each other.
+func newRedirectServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/r/0":
+			io.WriteString(w, "ok")
+		case "/loop/a":
+			http.Redirect(w, r, "/loop/b", http.StatusFound)
+		case "/loop/b":
+			http.Redirect(w, r, "/loop/a", http.StatusFound)
+		default:
+			var n int
+			if _, err := fmt.Sscanf(r.URL.Path, "/r/%d", &n); err != nil {
+				http.NotFound(w, r)
+				return
+			}
+			http.Redirect(w, r, fmt.Sprintf("/r/%d", n-1), http.StatusMovedPermanently)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestNewRedirects(t *testing.T) {
+	ts := newRedirectServer(t)
+	tests := []struct {
+		max  int
+		path string
+		want error
+	}{
+		{0, "/r/3", nil},
+		{0, "/r/10", nil},
+		{0, "/r/11", ErrTooManyRedirects},
+		{3, "/r/3", nil},
+		{3, "/r/4", ErrTooManyRedirects},
+		{0, "/loop/a", ErrRedirectLoop},
+		{100, "/loop/a", ErrRedirectLoop},
+	}
+	for _, tt := range tests {
+		resp, err := New(Config{MaxRedirects: tt.max}).Get(ts.URL + tt.path)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("MaxRedirects=%d: Get(%s) error = %v, want %v", tt.max, tt.path, err, tt.want)
+		}
+		if err != nil {
+			continue
+		}
+		resp.Body.Close()
+		if resp.Request.URL.Path != "/r/0" {
+			t.Errorf("MaxRedirects=%d: Get(%s) ended at %s, want /r/0", tt.max, tt.path, resp.Request.URL.Path)
+		}
+	}
+}
+
+func TestNewRedirectsDisabled(t *testing.T) {
+	ts := newRedirectServer(t)
+	resp, err := New(Config{MaxRedirects: -1}).Get(ts.URL + "/r/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMovedPermanently {
+		t.Errorf("StatusCode = %d, want %d", resp.StatusCode, http.StatusMovedPermanently)
+	}
+}
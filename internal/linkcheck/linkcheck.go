@@ -0,0 +1,123 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package linkcheck checks that the links of a feed are reachable.
+package linkcheck
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/NickolasHKraus/archor/pkg/rss"
+)
+
+// DefaultConcurrency is the default number of links checked concurrently.
+const DefaultConcurrency = 4
+
+// Result is the result of checking a link.
+type Result struct {
+	rss.LinkRef
+	// Status is the HTTP status code of the response, or 0 if the link was
+	// skipped or unreachable.
+	Status int
+	// Skipped is true if the link was not checked because it is not an
+	// http(s) URL (e.g. a mailto: link).
+	Skipped bool
+	// Err is the reason the link is broken, or nil if it is not.
+	Err error
+}
+
+// Broken returns true if the link is unreachable or responded with a non-2xx
+// status.
+func (r Result) Broken() bool {
+	return r.Err != nil
+}
+
+// Check issues a HEAD request for each link in refs, using up to concurrency
+// concurrent requests, and returns the results in the order of refs. Each URL
+// is requested only once, however many elements reference it. Requests are
+// canceled when ctx is done.
+func Check(ctx context.Context, c *http.Client, refs []rss.LinkRef, concurrency int) []Result {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	type check struct {
+		status  int
+		skipped bool
+		err     error
+	}
+	var (
+		mu     sync.Mutex
+		checks = make(map[string]*check)
+		urls   = make(chan string)
+		wg     sync.WaitGroup
+	)
+	for _, ref := range refs {
+		checks[ref.URL] = nil
+	}
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for u := range urls {
+				status, skipped, err := checkURL(ctx, c, u)
+				mu.Lock()
+				checks[u] = &check{status: status, skipped: skipped, err: err}
+				mu.Unlock()
+			}
+		}()
+	}
+	for u := range checks {
+		urls <- u
+	}
+	close(urls)
+	wg.Wait()
+
+	results := make([]Result, len(refs))
+	for i, ref := range refs {
+		ch := checks[ref.URL]
+		results[i] = Result{LinkRef: ref, Status: ch.status, Skipped: ch.skipped, Err: ch.err}
+	}
+	return results
+}
+
+// checkURL requests rawURL and returns the response status. Servers that do
+// not support HEAD are retried with GET.
+func checkURL(ctx context.Context, c *http.Client, rawURL string) (status int, skipped bool, err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return 0, false, err
+	}
+	if u.Scheme != "" && u.Scheme != "http" && u.Scheme != "https" {
+		return 0, true, nil
+	}
+	if !rss.IsValidURL(rawURL) {
+		return 0, false, fmt.Errorf("not an absolute URL")
+	}
+	resp, err := request(ctx, c, http.MethodHead, rawURL)
+	if err == nil && resp.StatusCode == http.StatusMethodNotAllowed {
+		resp.Body.Close()
+		resp, err = request(ctx, c, http.MethodGet, rawURL)
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, false, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return resp.StatusCode, false, nil
+}
+
+// request issues a request with the given method for rawURL using c.
+func request(ctx context.Context, c *http.Client, method, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
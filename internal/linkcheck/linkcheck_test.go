@@ -0,0 +1,71 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package linkcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/NickolasHKraus/archor/pkg/rss"
+)
+
+func TestCheck(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		switch r.URL.Path {
+		case "/ok":
+		case "/get-only":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	refs := []rss.LinkRef{
+		{Path: "link", URL: ts.URL + "/ok"},
+		{Path: "item[0].link", URL: ts.URL + "/missing"},
+		{Path: "item[0].comments", URL: "mailto:editor@example.com"},
+		{Path: "item[1].link", URL: ts.URL + "/ok"},
+		{Path: "item[1].enclosure", URL: ts.URL + "/get-only"},
+		{Path: "item[2].link", URL: "/relative"},
+	}
+	results := Check(context.Background(), http.DefaultClient, refs, 2)
+	if len(results) != len(refs) {
+		t.Fatalf("got %d results, want %d", len(results), len(refs))
+	}
+	tests := []struct {
+		status  int
+		skipped bool
+		broken  bool
+	}{
+		{200, false, false},
+		{404, false, true},
+		{0, true, false},
+		{200, false, false},
+		{200, false, false},
+		{0, false, true},
+	}
+	for i, tt := range tests {
+		r := results[i]
+		if r.LinkRef != refs[i] {
+			t.Errorf("results[%d].LinkRef = %+v, want %+v", i, r.LinkRef, refs[i])
+		}
+		if r.Status != tt.status || r.Skipped != tt.skipped || r.Broken() != tt.broken {
+			t.Errorf("results[%d] (%s) = status %d, skipped %v, broken %v (%v); want %d, %v, %v",
+				i, r.Path, r.Status, r.Skipped, r.Broken(), r.Err, tt.status, tt.skipped, tt.broken)
+		}
+	}
+	// /ok is referenced twice but requested once; /get-only is requested
+	// twice (HEAD, then GET).
+	if got := atomic.LoadInt32(&requests); got != 4 {
+		t.Errorf("server received %d requests, want 4", got)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package mirror
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/NickolasHKraus/archor/pkg/rss"
+)
+
+// ArchiveBy is the period by which the items of a mirrored feed are grouped
+// into archive feeds (see Options.ArchiveBy).
+type ArchiveBy string
+
+const (
+	// ArchiveByMonth groups items by the month of their publication date,
+	// e.g. "feed-2023-06.xml".
+	ArchiveByMonth ArchiveBy = "month"
+	// ArchiveByYear groups items by the year of their publication date,
+	// e.g. "feed-2023.xml".
+	ArchiveByYear ArchiveBy = "year"
+)
+
+// UndatedBucket is the bucket of the archive feed to which items whose
+// publication date is missing or cannot be parsed are written.
+const UndatedBucket = "undated"
+
+// IsValid returns true if the period is one of the ArchiveBy constants.
+func (a ArchiveBy) IsValid() bool {
+	switch a {
+	case ArchiveByMonth, ArchiveByYear:
+		return true
+	}
+	return false
+}
+
+// bucket returns the bucket of the item: its year, or year and month, of
+// publication, in the time zone of its publication date, or UndatedBucket.
+func (a ArchiveBy) bucket(item *rss.Item) string {
+	t, err := item.PubDate.Time()
+	if err != nil {
+		return UndatedBucket
+	}
+	if a == ArchiveByYear {
+		return t.Format("2006")
+	}
+	return t.Format("2006-01")
+}
+
+// ArchiveFilename returns the name of the archive feed of bucket for the
+// mirrored feed name, e.g. "feed-2023-06.xml" for "feed.xml".
+func ArchiveFilename(name, bucket string) string {
+	ext := filepath.Ext(name)
+	return strings.TrimSuffix(name, ext) + "-" + bucket + ext
+}
+
+// writeArchive writes the items of r to archive feeds grouped by
+// o.ArchiveBy, next to the mirrored feed name. Each archive feed is an RSS
+// document containing the channel of r with only the items of its bucket.
+func (o Options) writeArchive(r *rss.RSS, name string) error {
+	buckets := make(map[string][]*rss.Item)
+	for _, item := range r.Channel.Item {
+		b := o.ArchiveBy.bucket(item)
+		buckets[b] = append(buckets[b], item)
+	}
+	keys := make([]string, 0, len(buckets))
+	for b := range buckets {
+		keys = append(keys, b)
+	}
+	sort.Strings(keys)
+	for _, b := range keys {
+		c := *r.Channel
+		c.Item = buckets[b]
+		doc := *r
+		doc.Channel = &c
+		out, err := rss.MarshalOptions{PreserveOrder: true}.Marshal(&doc)
+		if err != nil {
+			return err
+		}
+		path := ArchiveFilename(name, b)
+		if o.Gzip {
+			path += GzipExt
+		}
+		if err := o.writeFeed(filepath.Join(o.Destination, path), out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package mirror
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/NickolasHKraus/archor/pkg/rss"
+)
+
+func TestRunArchiveBy(t *testing.T) {
+	const doc = `<rss version="2.0"><channel>
+<title>Site</title><link>http://example.com/</link><description>Example</description>
+<item><title>a</title><guid>1</guid><pubDate>Mon, 29 May 2023 10:00:00 +0000</pubDate></item>
+<item><title>b</title><guid>2</guid><pubDate>Thu, 01 Jun 2023 10:00:00 +0000</pubDate></item>
+<item><title>c</title><guid>3</guid><pubDate>Fri, 30 Jun 2023 23:30:00 -0200</pubDate></item>
+<item><title>d</title><guid>4</guid><pubDate>yesterday</pubDate></item>
+</channel></rss>`
+	tests := []struct {
+		by   ArchiveBy
+		want map[string][]rss.Title
+	}{
+		{ArchiveByMonth, map[string][]rss.Title{
+			"feed-2023-05.xml": {"a"},
+			"feed-2023-06.xml": {"b", "c"},
+			"feed-undated.xml": {"d"},
+			"feed.xml":         {"a", "b", "c", "d"},
+			"manifest.json":    nil,
+		}},
+		{ArchiveByYear, map[string][]rss.Title{
+			"feed-2023.xml":    {"a", "b", "c"},
+			"feed-undated.xml": {"d"},
+			"feed.xml":         {"a", "b", "c", "d"},
+			"manifest.json":    nil,
+		}},
+	}
+	for _, tt := range tests {
+		dst := t.TempDir()
+		o := Options{Source: "-", Destination: dst, Stdin: strings.NewReader(doc), ArchiveBy: tt.by}
+		if err := Run(context.Background(), o); err != nil {
+			t.Fatal(err)
+		}
+		entries, err := os.ReadDir(dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names, want []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		for name := range tt.want {
+			want = append(want, name)
+		}
+		sort.Strings(want)
+		if strings.Join(names, " ") != strings.Join(want, " ") {
+			t.Errorf("ArchiveBy=%s: files = %v, want %v", tt.by, names, want)
+			continue
+		}
+		for name, titles := range tt.want {
+			if !strings.HasPrefix(name, "feed-") {
+				continue
+			}
+			r := readFeed(t, filepath.Join(dst, name))
+			if err := r.Validate(); err != nil {
+				t.Errorf("ArchiveBy=%s: %s: Validate() = %v", tt.by, name, err)
+			}
+			var got []rss.Title
+			for _, item := range r.Channel.Item {
+				got = append(got, item.Title)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(titles) {
+				t.Errorf("ArchiveBy=%s: %s items = %v, want %v", tt.by, name, got, titles)
+			}
+		}
+	}
+}
+
+func TestArchiveFilename(t *testing.T) {
+	tests := []struct {
+		name, bucket, want string
+	}{
+		{"feed.xml", "2023-06", "feed-2023-06.xml"},
+		{"liftoff-news.rss", "2003", "liftoff-news-2003.rss"},
+		{"feed", UndatedBucket, "feed-undated"},
+	}
+	for _, tt := range tests {
+		if got := ArchiveFilename(tt.name, tt.bucket); got != tt.want {
+			t.Errorf("ArchiveFilename(%q, %q) = %q, want %q", tt.name, tt.bucket, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,415 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package mirror
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/NickolasHKraus/archor/internal/source"
+	"github.com/NickolasHKraus/archor/pkg/rss"
+)
+
+// downloadContent downloads the content referenced by the items of r to
+// o.Destination: enclosures if o.Enclosures is set and media:content objects
+// if o.Media is set. It returns a manifest entry for each download.
+//
+// Content recorded in the manifest of a previous mirror is not downloaded
+// again if its local copy matches the recorded checksum and its upstream
+// size and ETag are unchanged. Only one copy of identical content is kept:
+// the manifest entries of content with the same checksum share a path.
+//
+// Relative content URLs are resolved against o.BaseURL, if set, or against
+// the channel link.
+//
+// If o.MaxTotalSize is set, content is skipped, and logged, once the total
+// size of the content kept would exceed it.
+func (o Options) downloadContent(ctx context.Context, r *rss.RSS) ([]ContentEntry, error) {
+	if r.Channel == nil {
+		return nil, nil
+	}
+	base := o.BaseURL
+	if base == "" {
+		base = string(r.Channel.Link)
+	}
+	prev := make(map[string]ContentEntry)
+	if m, err := ReadManifest(o.Destination); err == nil {
+		for _, e := range m.Content {
+			prev[e.URL] = e
+		}
+	}
+	entries := []ContentEntry{}
+	used := make(map[string]bool)
+	// paths maps the checksums of the content kept to their paths.
+	paths := make(map[string]string)
+	// total is the size of the content kept, and exhausted is set once
+	// content is skipped for exceeding o.MaxTotalSize.
+	var total int64
+	var exhausted bool
+	for i, item := range r.Channel.Item {
+		for _, c := range o.content(item) {
+			var err error
+			if c.URL, err = resolveContentURL(c.URL, base); err != nil {
+				return nil, err
+			}
+			skip := func() {
+				exhausted = true
+				o.logf("skipped: item[%d]: %s: total size budget of %d bytes reached\n", i, c.URL, o.MaxTotalSize)
+				o.logger().Info("content skipped", "url", c.URL, "item", i, "budget", o.MaxTotalSize)
+			}
+			if exhausted {
+				skip()
+				continue
+			}
+			if e, ok := prev[c.URL]; ok && o.unchanged(ctx, e) {
+				if _, kept := paths[e.SHA256]; !kept && o.MaxTotalSize > 0 && total+e.Size > o.MaxTotalSize {
+					skip()
+					continue
+				}
+				o.logf("unchanged: %s\n", c.URL)
+				o.logger().Info("content cache hit", "url", c.URL, "path", e.Path)
+				used[e.Path] = true
+				if _, ok := paths[e.SHA256]; !ok {
+					paths[e.SHA256] = e.Path
+					total += e.Size
+				}
+				entries = append(entries, e)
+				continue
+			}
+			max := int64(-1)
+			if o.MaxTotalSize > 0 {
+				if max = o.MaxTotalSize - total; max <= 0 {
+					skip()
+					continue
+				}
+			}
+			e, err := o.download(ctx, c, localName(c.URL, c.Type, used), max)
+			if errors.Is(err, errTooLarge) {
+				skip()
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			if p, ok := paths[e.SHA256]; ok && p != e.Path {
+				if err := os.Remove(filepath.Join(o.Destination, e.Path)); err != nil {
+					return nil, err
+				}
+				o.logger().Info("content deduplicated", "url", c.URL, "path", p)
+				e.Path = p
+			} else {
+				paths[e.SHA256] = e.Path
+				total += e.Size
+			}
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+// unchanged returns true if the local copy of the content recorded in e
+// matches its checksum, and the size and ETag of the content reported by a
+// HEAD request match those recorded in e.
+func (o Options) unchanged(ctx context.Context, e ContentEntry) bool {
+	f, err := os.Open(filepath.Join(o.Destination, e.Path))
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil || hex.EncodeToString(h.Sum(nil)) != e.SHA256 {
+		return false
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, e.URL, nil)
+	if err != nil {
+		return false
+	}
+	resp, err := o.client().Do(req)
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false
+	}
+	if resp.ContentLength >= 0 && resp.ContentLength != e.Size {
+		return false
+	}
+	return resp.Header.Get("ETag") == e.ETag
+}
+
+// resolveContentURL resolves the content URL s against the URL base. It
+// returns an error if s is relative and base is not an absolute URL.
+func resolveContentURL(s, base string) (string, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid content URL %q: %w", s, err)
+	}
+	if u.IsAbs() {
+		return s, nil
+	}
+	b, err := url.Parse(base)
+	if err != nil || !b.IsAbs() {
+		return "", fmt.Errorf("cannot resolve relative content URL %q: no base URL is set and the channel link %q is not an absolute URL", s, base)
+	}
+	return b.ResolveReference(u).String(), nil
+}
+
+// content is a reference to content to download.
+type content struct {
+	// URL is the location of the content.
+	URL string
+	// Type is the declared MIME type of the content, if any.
+	Type string
+}
+
+// content returns the content of item to download.
+func (o Options) content(item *rss.Item) []content {
+	var cs []content
+	if o.Enclosures && item.Enclosure != nil && item.Enclosure.URL != "" {
+		cs = append(cs, content{URL: string(item.Enclosure.URL), Type: item.Enclosure.Type})
+	}
+	if o.Media {
+		for _, mc := range item.MediaContent {
+			if mc.URL != "" {
+				cs = append(cs, content{URL: string(mc.URL), Type: mc.Type})
+			}
+		}
+	}
+	return cs
+}
+
+// errTooLarge is returned by download for content larger than its limit.
+var errTooLarge = errors.New("content too large")
+
+// download fetches c and writes it to the file name in o.Destination. If the
+// type of the fetched content differs from the declared type, a warning is
+// logged, or an error is returned if o.Strict is set. If max is not negative
+// and the content is larger than max bytes, the download is discarded and
+// errTooLarge is returned.
+//
+// The content is written to name with the suffix PartSuffix and renamed to
+// name once complete. If a download is interrupted, the partial file is
+// kept, and the next download of c resumes from its end if the server
+// supports range requests.
+func (o Options) download(ctx context.Context, c content, name string, max int64) (ContentEntry, error) {
+	e := ContentEntry{URL: c.URL, Path: name}
+	part := filepath.Join(o.Destination, name+PartSuffix)
+	f, err := os.OpenFile(part, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return e, err
+	}
+	defer f.Close()
+	offset, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return e, err
+	}
+	resp, err := o.get(ctx, c.URL, offset)
+	if err != nil {
+		return e, err
+	}
+	defer resp.Body.Close()
+	e.ETag = resp.Header.Get("ETag")
+	br := bufio.NewReader(o.limiter.reader(ctx, resp.Body))
+	// head reads the content from its start, which is in the partial file if
+	// the download is resumed.
+	head := br
+	if resp.StatusCode == http.StatusPartialContent {
+		o.logger().Info("content download resumed", "url", c.URL, "path", name, "offset", offset)
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return e, err
+		}
+		head = bufio.NewReader(io.LimitReader(f, offset))
+	} else {
+		offset = 0
+		if err := f.Truncate(0); err != nil {
+			return e, err
+		}
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return e, err
+		}
+	}
+	if got := contentType(resp.Header, head); c.Type != "" && !sameType(c.Type, got) {
+		if o.Strict {
+			return e, fmt.Errorf("download %s: declared type %s, got %s", c.URL, c.Type, got)
+		}
+		o.logf("warning: %s: declared type %s, got %s\n", c.URL, c.Type, got)
+	}
+	h := sha256.New()
+	if offset > 0 {
+		// Hash the partial file, leaving f at its end.
+		if _, err := io.Copy(h, head); err != nil {
+			return e, err
+		}
+	}
+	var body io.Reader = br
+	if max >= 0 {
+		// Read one byte past max to tell whether the content exceeds it.
+		body = io.LimitReader(br, max-offset+1)
+	}
+	n, err := io.Copy(io.MultiWriter(f, h), body)
+	if err != nil {
+		return e, fmt.Errorf("download %s: %w", c.URL, err)
+	}
+	e.Size = offset + n
+	if max >= 0 && e.Size > max {
+		f.Close()
+		if err := os.Remove(part); err != nil {
+			return e, err
+		}
+		return e, fmt.Errorf("download %s: %w", c.URL, errTooLarge)
+	}
+	e.SHA256 = hex.EncodeToString(h.Sum(nil))
+	if err := f.Close(); err != nil {
+		return e, err
+	}
+	if err := os.Rename(part, filepath.Join(o.Destination, name)); err != nil {
+		return e, err
+	}
+	o.logger().Info("content downloaded", "url", c.URL, "path", name, "bytes", e.Size)
+	return e, nil
+}
+
+// PartSuffix is the suffix of the name of content being downloaded.
+const PartSuffix = ".part"
+
+// get sends a GET request for url. If offset is positive, only the content
+// from offset on is requested. The response status is 206 Partial Content if
+// the server returns only that part of the content, or 200 OK if it returns
+// the entire content.
+func (o Options) get(ctx context.Context, url string, offset int64) (*http.Response, error) {
+	if offset == 0 {
+		return source.GetResponse(ctx, o.client(), url)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	resp, err := o.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return resp, nil
+	case resp.StatusCode == http.StatusPartialContent && strings.HasPrefix(resp.Header.Get("Content-Range"), fmt.Sprintf("bytes %d-", offset)):
+		return resp, nil
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		// The partial content is stale, e.g. the content has shrunk.
+		resp.Body.Close()
+		return source.GetResponse(ctx, o.client(), url)
+	}
+	resp.Body.Close()
+	return nil, fmt.Errorf("GET %s: unexpected status %s", url, resp.Status)
+}
+
+// contentType returns the type of the content read from br: the
+// Content-Type header, or the type sniffed from the first bytes of br if the
+// header is missing or generic.
+func contentType(h http.Header, br *bufio.Reader) string {
+	if t := h.Get("Content-Type"); t != "" && !sameType(t, "application/octet-stream") {
+		return t
+	}
+	b, _ := br.Peek(512)
+	return http.DetectContentType(b)
+}
+
+// sameType returns true if the MIME types a and b are the same, ignoring
+// parameters. A generic type (application/octet-stream) matches any type.
+func sameType(a, b string) bool {
+	a, b = mediaType(a), mediaType(b)
+	return a == b || a == "application/octet-stream" || b == "application/octet-stream"
+}
+
+func mediaType(s string) string {
+	t, _, err := mime.ParseMediaType(s)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(s))
+	}
+	return t
+}
+
+// preferredExts are the extensions used for common content types, which
+// have several registered extensions (e.g. audio/mpeg has .mp2, .mp3 and
+// .mpga) or none in the built-in table of the mime package.
+var preferredExts = map[string]string{
+	"audio/aac":       ".aac",
+	"audio/flac":      ".flac",
+	"audio/mp4":       ".m4a",
+	"audio/mpeg":      ".mp3",
+	"audio/ogg":       ".ogg",
+	"audio/opus":      ".opus",
+	"audio/wav":       ".wav",
+	"audio/x-m4a":     ".m4a",
+	"application/pdf": ".pdf",
+	"image/gif":       ".gif",
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/webp":      ".webp",
+	"video/mp4":       ".mp4",
+	"video/quicktime": ".mov",
+	"video/webm":      ".webm",
+	"video/x-m4v":     ".m4v",
+}
+
+// contentExt returns the file extension of content with the declared MIME
+// type typ whose URL path has the extension urlExt. If urlExt is an extension
+// of typ (see mime.ExtensionsByType), or typ is missing, generic or unknown,
+// urlExt is returned. Otherwise, the preferred extension of typ is returned.
+func contentExt(typ, urlExt string) string {
+	t := mediaType(typ)
+	if t == "" || t == "application/octet-stream" {
+		return urlExt
+	}
+	var exts []string
+	if ext, ok := preferredExts[t]; ok {
+		exts = append(exts, ext)
+	}
+	if registered, err := mime.ExtensionsByType(t); err == nil {
+		exts = append(exts, registered...)
+	}
+	if len(exts) == 0 {
+		return urlExt
+	}
+	for _, ext := range exts {
+		if strings.EqualFold(ext, urlExt) {
+			return urlExt
+		}
+	}
+	return exts[0]
+}
+
+// localName returns the name of the local copy of the content at rawURL with
+// the declared MIME type typ, derived from the last element of its path, with
+// the extension given by contentExt. Names already in used are disambiguated
+// with a numeric suffix, and the returned name is added to used.
+func localName(rawURL, typ string, used map[string]bool) string {
+	base := "content"
+	if u, err := url.Parse(rawURL); err == nil {
+		if b := path.Base(u.Path); b != "/" && b != "." && b != ".." {
+			base = b
+		}
+	}
+	stem := strings.TrimSuffix(base, path.Ext(base))
+	ext := contentExt(typ, path.Ext(base))
+	name := stem + ext
+	for i := 1; used[name]; i++ {
+		name = fmt.Sprintf("%s-%d%s", stem, i, ext)
+	}
+	used[name] = true
+	return name
+}
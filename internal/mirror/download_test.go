@@ -0,0 +1,558 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package mirror
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/NickolasHKraus/archor/internal/fetch"
+	"github.com/NickolasHKraus/archor/internal/httpclient"
+)
+
+const testMediaFeed = "../../test/data/rss-media.xml"
+
+// newContentServer returns a server serving the feed template name at
+// /feed.xml, with {{.}} replaced by the server URL, and the body "content of
+// <path>" at every other path.
+func newContentServer(t *testing.T, name string) *httptest.Server {
+	t.Helper()
+	tmpl := template.Must(template.ParseFiles(name))
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/feed.xml" {
+			if err := tmpl.Execute(w, ts.URL); err != nil {
+				t.Error(err)
+			}
+			return
+		}
+		w.Write([]byte("content of " + r.URL.Path))
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func assertFile(t *testing.T, name, want string) {
+	t.Helper()
+	b, err := os.ReadFile(name)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if string(b) != want {
+		t.Errorf("%s = %q, want %q", name, b, want)
+	}
+}
+
+func TestRunEnclosures(t *testing.T) {
+	ts := newContentServer(t, testMediaFeed)
+	dst := t.TempDir()
+	if err := Run(context.Background(), Options{Source: ts.URL + "/feed.xml", Destination: dst, Enclosures: true}); err != nil {
+		t.Fatal(err)
+	}
+	assertFile(t, filepath.Join(dst, "1.mp3"), "content of /episodes/1.mp3")
+	assertFile(t, filepath.Join(dst, "2.mp3"), "content of /episodes/2.mp3")
+	if _, err := os.Stat(filepath.Join(dst, "1.jpg")); !os.IsNotExist(err) {
+		t.Errorf("expected media:content not to be downloaded, got %v", err)
+	}
+}
+
+func TestRunCredentials(t *testing.T) {
+	var mu sync.Mutex
+	auth := make(map[string]string)
+	record := func(r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		auth[r.URL.Path] = r.Header.Get("Authorization")
+	}
+	content := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		record(r)
+		w.Write([]byte("content of " + r.URL.Path))
+	}))
+	defer content.Close()
+	tmpl := template.Must(template.ParseFiles(testMediaFeed))
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		record(r)
+		if err := tmpl.Execute(w, content.URL); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	o := Options{
+		Source:      ts.URL + "/feed.xml",
+		Destination: t.TempDir(),
+		Enclosures:  true,
+		Client:      fetch.New(httpclient.Config{BearerToken: "secret"}),
+	}
+	if err := Run(context.Background(), o); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"/feed.xml":       "Bearer secret",
+		"/episodes/1.mp3": "",
+		"/episodes/2.mp3": "",
+	}
+	if !reflect.DeepEqual(auth, want) {
+		t.Errorf("Authorization by path = %v, want %v", auth, want)
+	}
+}
+
+func TestRunMedia(t *testing.T) {
+	ts := newContentServer(t, testMediaFeed)
+	dst := t.TempDir()
+	if err := Run(context.Background(), Options{Source: ts.URL + "/feed.xml", Destination: dst, Enclosures: true, Media: true}); err != nil {
+		t.Fatal(err)
+	}
+	assertFile(t, filepath.Join(dst, "1.mp3"), "content of /episodes/1.mp3")
+	assertFile(t, filepath.Join(dst, "1.jpg"), "content of /images/1.jpg")
+	assertFile(t, filepath.Join(dst, "2.jpg"), "content of /images/2.jpg")
+}
+
+func TestRunContentTypeMismatch(t *testing.T) {
+	tmpl := template.Must(template.ParseFiles(testMediaFeed))
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/feed.xml" {
+			tmpl.Execute(w, ts.URL)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte("<html><body>Not Found</body></html>"))
+	}))
+	defer ts.Close()
+
+	var log bytes.Buffer
+	o := Options{Source: ts.URL + "/feed.xml", Destination: t.TempDir(), Enclosures: true, Log: &log}
+	if err := Run(context.Background(), o); err != nil {
+		t.Fatal(err)
+	}
+	want := "warning: " + ts.URL + "/episodes/1.mp3: declared type audio/mpeg, got text/html; charset=utf-8"
+	if !strings.Contains(log.String(), want) {
+		t.Errorf("log = %q, want it to contain %q", log.String(), want)
+	}
+
+	o.Strict = true
+	o.Destination = t.TempDir()
+	if err := Run(context.Background(), o); err == nil || !strings.Contains(err.Error(), "declared type audio/mpeg") {
+		t.Errorf("Run(context.Background(), ) error = %v, want content type mismatch", err)
+	}
+}
+
+func TestRunCanceled(t *testing.T) {
+	tmpl := template.Must(template.ParseFiles(testMediaFeed))
+	started := make(chan struct{}, 1)
+	done := make(chan struct{})
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/feed.xml" {
+			tmpl.Execute(w, ts.URL)
+			return
+		}
+		// Stall the download until the test ends.
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}))
+	defer ts.Close()
+	defer close(done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+	errc := make(chan error, 1)
+	go func() {
+		errc <- Run(ctx, Options{Source: ts.URL + "/feed.xml", Destination: t.TempDir(), Enclosures: true})
+	}()
+	select {
+	case err := <-errc:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Run() error = %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after the context was canceled")
+	}
+}
+
+func TestSameType(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"audio/mpeg", "audio/mpeg", true},
+		{"audio/mpeg", "Audio/MPEG", true},
+		{"text/html", "text/html; charset=utf-8", true},
+		{"audio/mpeg", "application/octet-stream", true},
+		{"audio/mpeg", "text/html", false},
+		{"image/jpeg", "image/png", false},
+	}
+	for _, tt := range tests {
+		if got := sameType(tt.a, tt.b); got != tt.want {
+			t.Errorf("sameType(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestLocalName(t *testing.T) {
+	used := make(map[string]bool)
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"http://a.com/episodes/1.mp3", "1.mp3"},
+		{"http://b.com/other/1.mp3", "1-1.mp3"},
+		{"http://c.com/1.mp3?x=1", "1-2.mp3"},
+		{"http://a.com/", "content"},
+		{"http://a.com/..", "content-1"},
+	}
+	for _, tt := range tests {
+		if got := localName(tt.in, "", used); got != tt.want {
+			t.Errorf("localName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestContentExt(t *testing.T) {
+	tests := []struct {
+		typ, urlExt string
+		want        string
+	}{
+		{"audio/mpeg", ".mp3", ".mp3"},
+		{"audio/mpeg", "", ".mp3"},
+		{"audio/mpeg", ".php", ".mp3"},
+		{"audio/mpeg; charset=binary", ".bin", ".mp3"},
+		{"audio/mp4", ".M4A", ".M4A"},
+		{"video/mp4", ".m4a", ".mp4"},
+		{"image/jpeg", ".jpeg", ".jpeg"},
+		{"application/x-unknown-type", ".dat", ".dat"},
+		{"application/octet-stream", ".bin", ".bin"},
+		{"", ".ogg", ".ogg"},
+	}
+	for _, tt := range tests {
+		if got := contentExt(tt.typ, tt.urlExt); got != tt.want {
+			t.Errorf("contentExt(%q, %q) = %q, want %q", tt.typ, tt.urlExt, got, tt.want)
+		}
+	}
+}
+
+func TestLocalNameType(t *testing.T) {
+	used := make(map[string]bool)
+	tests := []struct {
+		url, typ string
+		want     string
+	}{
+		{"http://a.com/download.php?id=1", "audio/mpeg", "download.mp3"},
+		{"http://a.com/download.php?id=2", "audio/mpeg", "download-1.mp3"},
+		{"http://a.com/episodes/2", "audio/mpeg", "2.mp3"},
+		{"http://a.com/episodes/3.data", "application/x-unknown-type", "3.data"},
+	}
+	for _, tt := range tests {
+		if got := localName(tt.url, tt.typ, used); got != tt.want {
+			t.Errorf("localName(%q, %q) = %q, want %q", tt.url, tt.typ, got, tt.want)
+		}
+	}
+}
+
+func TestRunSkipsUnchangedContent(t *testing.T) {
+	tmpl := template.Must(template.ParseFiles(testMediaFeed))
+	var gets int32
+	etag := `"v1"`
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/feed.xml" {
+			tmpl.Execute(w, ts.URL)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		body := "content of " + r.URL.Path
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		if r.Method == http.MethodGet {
+			atomic.AddInt32(&gets, 1)
+			w.Write([]byte(body))
+		}
+	}))
+	defer ts.Close()
+
+	dst := t.TempDir()
+	o := Options{Source: ts.URL + "/feed.xml", Destination: dst, Enclosures: true}
+	if err := Run(context.Background(), o); err != nil {
+		t.Fatal(err)
+	}
+	if got := atomic.SwapInt32(&gets, 0); got != 2 {
+		t.Fatalf("first run downloaded %d files, want 2", got)
+	}
+	if err := Run(context.Background(), o); err != nil {
+		t.Fatal(err)
+	}
+	if got := atomic.SwapInt32(&gets, 0); got != 0 {
+		t.Errorf("second run downloaded %d files, want 0", got)
+	}
+
+	// A modified local copy is downloaded again.
+	if err := os.WriteFile(filepath.Join(dst, "1.mp3"), []byte("corrupt"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Run(context.Background(), o); err != nil {
+		t.Fatal(err)
+	}
+	if got := atomic.SwapInt32(&gets, 0); got != 1 {
+		t.Errorf("run after modifying a local copy downloaded %d files, want 1", got)
+	}
+	assertFile(t, filepath.Join(dst, "1.mp3"), "content of /episodes/1.mp3")
+
+	// Content whose ETag changed is downloaded again.
+	etag = `"v2"`
+	if err := Run(context.Background(), o); err != nil {
+		t.Fatal(err)
+	}
+	if got := atomic.SwapInt32(&gets, 0); got != 2 {
+		t.Errorf("run after changing the ETag downloaded %d files, want 2", got)
+	}
+}
+
+func TestRunMaxItems(t *testing.T) {
+	ts := newContentServer(t, testMediaFeed)
+	dst := t.TempDir()
+	o := Options{Source: ts.URL + "/feed.xml", Destination: dst, Enclosures: true, MaxItems: 1}
+	if err := Run(context.Background(), o); err != nil {
+		t.Fatal(err)
+	}
+	r := readFeed(t, filepath.Join(dst, DefaultFilename))
+	if len(r.Channel.Item) != 1 || r.Channel.Item[0].Title != "Episode 2" {
+		t.Fatalf("items = %+v, want only the most recent item, Episode 2", r.Channel.Item)
+	}
+	assertFile(t, filepath.Join(dst, "2.mp3"), "content of /episodes/2.mp3")
+	if _, err := os.Stat(filepath.Join(dst, "1.mp3")); !os.IsNotExist(err) {
+		t.Errorf("expected enclosure of removed item not to be downloaded, got %v", err)
+	}
+}
+
+func TestRunDeduplicatesContent(t *testing.T) {
+	var gets int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			atomic.AddInt32(&gets, 1)
+		}
+		w.Write([]byte("the same episode"))
+	}))
+	defer ts.Close()
+	doc := `<rss version="2.0"><channel><title>Site</title>
+<item><title>a</title><enclosure url="` + ts.URL + `/episodes/a.mp3" length="16" type="audio/mpeg"/></item>
+<item><title>b</title><enclosure url="` + ts.URL + `/reruns/b.mp3" length="16" type="audio/mpeg"/></item>
+</channel></rss>`
+	dst := t.TempDir()
+	o := Options{Source: "-", Destination: dst, Stdin: strings.NewReader(doc), Enclosures: true}
+	if err := Run(context.Background(), o); err != nil {
+		t.Fatal(err)
+	}
+	assertFile(t, filepath.Join(dst, "a.mp3"), "the same episode")
+	if _, err := os.Stat(filepath.Join(dst, "b.mp3")); !os.IsNotExist(err) {
+		t.Errorf("expected a single copy of identical content, got %v", err)
+	}
+	m, err := ReadManifest(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Content) != 2 || m.Content[0].Path != "a.mp3" || m.Content[1].Path != "a.mp3" {
+		t.Errorf("Content = %+v, want both entries to point to a.mp3", m.Content)
+	}
+
+	// Deduplicated content is not downloaded again.
+	atomic.StoreInt32(&gets, 0)
+	o.Stdin = strings.NewReader(doc)
+	if err := Run(context.Background(), o); err != nil {
+		t.Fatal(err)
+	}
+	if got := atomic.LoadInt32(&gets); got != 0 {
+		t.Errorf("second run downloaded %d files, want 0", got)
+	}
+}
+
+func TestRunResumesDownload(t *testing.T) {
+	episode := bytes.Repeat([]byte("0123456789"), 1000)
+	var gets int32
+	var ranges []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "audio/mpeg")
+		if atomic.AddInt32(&gets, 1) == 1 {
+			// Interrupt the first download halfway.
+			w.Header().Set("Content-Length", strconv.Itoa(len(episode)))
+			w.Write(episode[:len(episode)/2])
+			return
+		}
+		ranges = append(ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(episode))
+	}))
+	defer ts.Close()
+	doc := `<rss version="2.0"><channel><title>Site</title>
+<item><title>a</title><enclosure url="` + ts.URL + `/episode.mp3" length="10000" type="audio/mpeg"/></item>
+</channel></rss>`
+	dst := t.TempDir()
+	o := Options{Source: "-", Destination: dst, Enclosures: true}
+
+	o.Stdin = strings.NewReader(doc)
+	if err := Run(context.Background(), o); err == nil {
+		t.Fatal("expected error for interrupted download")
+	}
+	assertFile(t, filepath.Join(dst, "episode.mp3"+PartSuffix), string(episode[:len(episode)/2]))
+
+	o.Stdin = strings.NewReader(doc)
+	if err := Run(context.Background(), o); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"bytes=5000-"}; !reflect.DeepEqual(ranges, want) {
+		t.Errorf("Range = %q, want %q", ranges, want)
+	}
+	assertFile(t, filepath.Join(dst, "episode.mp3"), string(episode))
+	if _, err := os.Stat(filepath.Join(dst, "episode.mp3"+PartSuffix)); !os.IsNotExist(err) {
+		t.Errorf("expected partial file to be removed, got %v", err)
+	}
+	m, err := ReadManifest(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(episode)
+	if e := m.Content[0]; e.Size != int64(len(episode)) || e.SHA256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("Content[0] = %+v, want size %d and checksum of the entire episode", e, len(episode))
+	}
+}
+
+func TestRunRelativeEnclosure(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("content of " + r.URL.Path))
+	}))
+	defer ts.Close()
+	feed := func(link string) string {
+		return `<rss version="2.0"><channel><title>Podcast</title><link>` + link + `</link>
+<item><title>1</title><enclosure url="episodes/1.mp3" length="6" type="audio/mpeg"/></item>
+</channel></rss>`
+	}
+	tests := []struct {
+		name    string
+		link    string
+		baseURL string
+		want    string
+	}{
+		{"channel link", ts.URL + "/podcast/", "", "content of /podcast/episodes/1.mp3"},
+		{"base URL", "/podcast/", ts.URL + "/files/", "content of /files/episodes/1.mp3"},
+		{"base URL over channel link", ts.URL + "/podcast/", ts.URL + "/files/", "content of /files/episodes/1.mp3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := t.TempDir()
+			o := Options{Source: "-", Stdin: strings.NewReader(feed(tt.link)), Destination: dst, Enclosures: true, BaseURL: tt.baseURL}
+			if err := Run(context.Background(), o); err != nil {
+				t.Fatal(err)
+			}
+			assertFile(t, filepath.Join(dst, "1.mp3"), tt.want)
+		})
+	}
+
+	o := Options{Source: "-", Stdin: strings.NewReader(feed("")), Destination: t.TempDir(), Enclosures: true}
+	if err := Run(context.Background(), o); err == nil || !strings.Contains(err.Error(), `relative content URL "episodes/1.mp3"`) {
+		t.Errorf("Run() without a base = %v, want an error naming the relative URL", err)
+	}
+}
+
+func TestRunMaxTotalSize(t *testing.T) {
+	tmpl := template.Must(template.ParseFiles(testMediaFeed))
+	var gets int32
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/feed.xml" {
+			tmpl.Execute(w, ts.URL)
+			return
+		}
+		atomic.AddInt32(&gets, 1)
+		w.Write([]byte("content of " + r.URL.Path))
+	}))
+	defer ts.Close()
+
+	// Each file is 26 bytes long.
+	const size = int64(len("content of /episodes/1.mp3"))
+	tests := []struct {
+		max      int64
+		want     []string
+		gets     int32
+		skipped  []string
+		manifest int
+	}{
+		{0, []string{"1.mp3", "2.mp3"}, 2, nil, 2},
+		{2 * size, []string{"1.mp3", "2.mp3"}, 2, nil, 2},
+		{2*size - 1, []string{"1.mp3"}, 2, []string{"item[1]"}, 1},
+		{size, []string{"1.mp3"}, 1, []string{"item[1]"}, 1},
+		{size - 1, nil, 1, []string{"item[0]", "item[1]"}, 0},
+	}
+	for _, tt := range tests {
+		atomic.StoreInt32(&gets, 0)
+		dst := t.TempDir()
+		var log bytes.Buffer
+		o := Options{Source: ts.URL + "/feed.xml", Destination: dst, Enclosures: true, MaxTotalSize: tt.max, Log: &log}
+		if err := Run(context.Background(), o); err != nil {
+			t.Fatalf("MaxTotalSize=%d: %v", tt.max, err)
+		}
+		if got := atomic.LoadInt32(&gets); got != tt.gets {
+			t.Errorf("MaxTotalSize=%d: %d downloads, want %d", tt.max, got, tt.gets)
+		}
+		for _, name := range []string{"1.mp3", "2.mp3"} {
+			_, err := os.Stat(filepath.Join(dst, name))
+			if kept := err == nil; kept != contains(tt.want, name) {
+				t.Errorf("MaxTotalSize=%d: %s kept = %t, want %t", tt.max, name, kept, !kept)
+			}
+			if _, err := os.Stat(filepath.Join(dst, name+PartSuffix)); !os.IsNotExist(err) {
+				t.Errorf("MaxTotalSize=%d: %s%s left behind", tt.max, name, PartSuffix)
+			}
+		}
+		var skipped []string
+		for _, line := range strings.Split(strings.TrimSpace(log.String()), "\n") {
+			if strings.HasPrefix(line, "skipped: ") {
+				skipped = append(skipped, strings.SplitN(strings.TrimPrefix(line, "skipped: "), ":", 2)[0])
+			}
+		}
+		if strings.Join(skipped, " ") != strings.Join(tt.skipped, " ") {
+			t.Errorf("MaxTotalSize=%d: skipped %v, want %v\n%s", tt.max, skipped, tt.skipped, log.String())
+		}
+		if r := readFeed(t, filepath.Join(dst, DefaultFilename)); len(r.Channel.Item) != 2 {
+			t.Errorf("MaxTotalSize=%d: feed has %d items, want 2", tt.max, len(r.Channel.Item))
+		}
+		m, err := ReadManifest(dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(m.Content) != tt.manifest {
+			t.Errorf("MaxTotalSize=%d: manifest has %d content entries, want %d", tt.max, len(m.Content), tt.manifest)
+		}
+	}
+}
+
+func contains(s []string, v string) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package mirror
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+
+	"github.com/NickolasHKraus/archor/pkg/rss"
+)
+
+// filenameData is the data available to a filename template.
+type filenameData struct {
+	// Title is the title of the channel.
+	Title string
+	// Date is the last build date of the channel, or its publication date if
+	// it has none, or the current date if it has neither, as YYYY-MM-DD.
+	Date string
+}
+
+// renderFilename renders the filename template text for the channel c. The
+// result is sanitized so that it is a single, portable path element.
+func renderFilename(text string, c *rss.Channel) (string, error) {
+	tmpl, err := template.New("filename").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid filename template: %w", err)
+	}
+	data := filenameData{Title: string(c.Title), Date: channelDate(c).Format("2006-01-02")}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("invalid filename template: %w", err)
+	}
+	name := sanitizeFilename(buf.String())
+	if name == "" || name == "." || name == ".." {
+		return "", fmt.Errorf("filename template %q renders to invalid filename %q", text, name)
+	}
+	return name, nil
+}
+
+// channelDate returns the last build date of c, or its publication date if it
+// has none, or the current time if it has neither.
+func channelDate(c *rss.Channel) time.Time {
+	if t, err := c.LastBuildDate.Time(); err == nil {
+		return t
+	}
+	if t, err := c.PubDate.Time(); err == nil {
+		return t
+	}
+	return time.Now()
+}
+
+// sanitizeFilename replaces characters that are unsafe in filenames (path
+// separators, whitespace, control characters and characters reserved on
+// Windows) with "-", collapsing runs of them into one.
+func sanitizeFilename(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.TrimSpace(s) {
+		if unicode.IsSpace(r) || unicode.IsControl(r) || strings.ContainsRune(`/\:*?"<>|`, r) {
+			if !dash {
+				b.WriteByte('-')
+			}
+			dash = true
+			continue
+		}
+		b.WriteRune(r)
+		dash = false
+	}
+	return b.String()
+}
@@ -0,0 +1,81 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package mirror
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/NickolasHKraus/archor/pkg/rss"
+)
+
+func TestRenderFilename(t *testing.T) {
+	c := &rss.Channel{
+		Title:   "Liftoff News: Space/Exploration",
+		PubDate: "Tue, 10 Jun 2003 04:00:00 GMT",
+	}
+	tests := []struct {
+		tmpl string
+		want string
+	}{
+		{DefaultFilename, "feed.xml"},
+		{"{{.Title}}.xml", "Liftoff-News-Space-Exploration.xml"},
+		{"{{.Title}}-{{.Date}}.xml", "Liftoff-News-Space-Exploration-2003-06-10.xml"},
+		{"../{{.Date}}.xml", "..-2003-06-10.xml"},
+	}
+	for _, tt := range tests {
+		got, err := renderFilename(tt.tmpl, c)
+		if err != nil {
+			t.Errorf("renderFilename(%q): %v", tt.tmpl, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("renderFilename(%q) = %q, want %q", tt.tmpl, got, tt.want)
+		}
+	}
+}
+
+func TestRenderFilenameLastBuildDate(t *testing.T) {
+	c := &rss.Channel{
+		PubDate:       "Tue, 10 Jun 2003 04:00:00 GMT",
+		LastBuildDate: "Wed, 11 Jun 2003 09:41:01 GMT",
+	}
+	if got, err := renderFilename("{{.Date}}.xml", c); err != nil || got != "2003-06-11.xml" {
+		t.Errorf("renderFilename() = %q, %v; want %q", got, err, "2003-06-11.xml")
+	}
+}
+
+func TestRenderFilenameErrors(t *testing.T) {
+	c := &rss.Channel{}
+	for _, tmpl := range []string{"{{.Title", "{{.Missing}}", "{{.Title}}", ".."} {
+		if got, err := renderFilename(tmpl, c); err == nil {
+			t.Errorf("renderFilename(%q) = %q, want error", tmpl, got)
+		}
+	}
+}
+
+func TestRunFilename(t *testing.T) {
+	dst := t.TempDir()
+	if err := Run(context.Background(), Options{Source: testFeed, Destination: dst, Filename: "{{.Title}}.xml"}); err != nil {
+		t.Fatal(err)
+	}
+	readFeed(t, filepath.Join(dst, "Liftoff-News.xml"))
+}
+
+func TestSanitizeFilename(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"feed.xml", "feed.xml"},
+		{" a  b\tc ", "a-b-c"},
+		{`a/b\c:d*e?f"g<h>i|j`, "a-b-c-d-e-f-g-h-i-j"},
+		{"a / b", "a-b"},
+	}
+	for _, tt := range tests {
+		if got := sanitizeFilename(tt.in); got != tt.want {
+			t.Errorf("sanitizeFilename(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
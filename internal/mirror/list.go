@@ -0,0 +1,135 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package mirror
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/NickolasHKraus/archor/internal/source"
+)
+
+// DefaultWorkers is the default number of feeds of a list mirrored
+// concurrently.
+const DefaultWorkers = 4
+
+// Job is a feed of a feed list (see ReadList).
+type Job struct {
+	// Source is the location of the feed, as in Options.Source.
+	Source string
+	// Destination is the directory to which the feed is written. A relative
+	// destination is relative to the destination of the list. If empty, it
+	// is derived from Source (see JobDestination).
+	Destination string
+}
+
+// ReadList reads a feed list from r. Each line of the list names a feed: its
+// source, optionally followed by whitespace and a destination directory.
+// Blank lines and lines starting with "#" are ignored.
+func ReadList(r io.Reader) ([]Job, error) {
+	var jobs []Job
+	s := bufio.NewScanner(r)
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) > 2 {
+			return nil, fmt.Errorf("line %d: want a source and an optional destination, got %q", n, line)
+		}
+		job := Job{Source: fields[0]}
+		if len(fields) == 2 {
+			job.Destination = fields[1]
+		}
+		jobs = append(jobs, job)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// JobDestination returns the name of the directory to which the feed at src
+// is written if its job has no destination: the host and path of the URL,
+// or the base name of the local path, sanitized as a single path element
+// (e.g. "example.com-blog-feed.xml").
+func JobDestination(src string) string {
+	name := filepath.Base(src)
+	if u, err := url.Parse(src); err == nil && u.Host != "" {
+		name = u.Host + u.Path
+	}
+	return strings.Trim(sanitizeFilename(name), "-")
+}
+
+// RunList mirrors the feeds of the feed list at list, which is read like a
+// feed (see Options.Source), with up to workers feeds mirrored concurrently.
+// Each feed is mirrored with the options o, with the source and destination
+// of its job; the destination of a job is relative to o.Destination. A
+// RateLimit applies to the downloads of all feeds in aggregate.
+//
+// A failed mirror does not stop the others: RunList returns the errors of
+// all failed mirrors, joined, each prefixed with the source of its feed.
+func RunList(ctx context.Context, o Options, list string, workers int) error {
+	o.Client = o.fetcher()
+	rc, err := source.Open(withCredentials(ctx, list), o.client(), list, o.stdin())
+	if err != nil {
+		return err
+	}
+	jobs, err := ReadList(rc)
+	rc.Close()
+	if err != nil {
+		return fmt.Errorf("%s: %w", list, err)
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	if o.limiter == nil {
+		o.limiter = newLimiter(o.RateLimit)
+	}
+	var (
+		wg   sync.WaitGroup
+		next = make(chan int)
+		errs = make([]error, len(jobs))
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				if err := o.runJob(ctx, jobs[i]); err != nil {
+					errs[i] = fmt.Errorf("%s: %w", jobs[i].Source, err)
+				}
+			}
+		}()
+	}
+	for i := range jobs {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// runJob mirrors the feed of job.
+func (o Options) runJob(ctx context.Context, job Job) error {
+	dst := job.Destination
+	if dst == "" {
+		dst = JobDestination(job.Source)
+	}
+	if !filepath.IsAbs(dst) {
+		dst = filepath.Join(o.Destination, dst)
+	}
+	o.Source = job.Source
+	o.Destination = dst
+	_, err := o.run(ctx)
+	return err
+}
@@ -0,0 +1,125 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package mirror
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadList(t *testing.T) {
+	const list = `# feeds
+http://example.com/a.xml
+
+  http://example.com/b.xml   b
+feed.xml /tmp/c
+`
+	jobs, err := ReadList(strings.NewReader(list))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Job{
+		{Source: "http://example.com/a.xml"},
+		{Source: "http://example.com/b.xml", Destination: "b"},
+		{Source: "feed.xml", Destination: "/tmp/c"},
+	}
+	if !reflect.DeepEqual(jobs, want) {
+		t.Errorf("ReadList() = %+v, want %+v", jobs, want)
+	}
+	if _, err := ReadList(strings.NewReader("a b c\n")); err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("ReadList() = %v, want an error on line 1", err)
+	}
+}
+
+func TestJobDestination(t *testing.T) {
+	tests := []struct {
+		src, want string
+	}{
+		{"http://example.com/blog/feed.xml", "example.com-blog-feed.xml"},
+		{"https://example.com:8080/", "example.com-8080"},
+		{"../../test/data/rss-0.xml", "rss-0.xml"},
+	}
+	for _, tt := range tests {
+		if got := JobDestination(tt.src); got != tt.want {
+			t.Errorf("JobDestination(%q) = %q, want %q", tt.src, got, tt.want)
+		}
+	}
+}
+
+// newListServer returns a server serving the feed list list at /feeds.txt,
+// with {{.}} replaced by the server URL, and the test feed at every path
+// starting with /feeds/ except /feeds/missing.xml.
+func newListServer(t *testing.T, list string) *httptest.Server {
+	t.Helper()
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/feeds.txt":
+			fmt.Fprint(w, strings.ReplaceAll(list, "{{.}}", ts.URL))
+		case r.URL.Path == "/feeds/missing.xml":
+			http.NotFound(w, r)
+		case strings.HasPrefix(r.URL.Path, "/feeds/"):
+			http.ServeFile(w, r, testFeed)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestRunList(t *testing.T) {
+	ts := newListServer(t, `{{.}}/feeds/a.xml a
+{{.}}/feeds/b.xml b
+{{.}}/feeds/c.xml
+`)
+	dst := t.TempDir()
+	if err := RunList(context.Background(), Options{Destination: dst}, ts.URL+"/feeds.txt", 2); err != nil {
+		t.Fatal(err)
+	}
+	for _, dir := range []string{"a", "b", JobDestination(ts.URL + "/feeds/c.xml")} {
+		r := readFeed(t, filepath.Join(dst, dir, DefaultFilename))
+		if got := len(r.Channel.Item); got != 4 {
+			t.Errorf("%s: len(Channel.Item) = %d, want 4", dir, got)
+		}
+		if _, err := ReadManifest(filepath.Join(dst, dir)); err != nil {
+			t.Errorf("%s: %v", dir, err)
+		}
+	}
+}
+
+func TestRunListErrors(t *testing.T) {
+	ts := newListServer(t, `{{.}}/feeds/missing.xml a
+{{.}}/feeds/b.xml b
+{{.}}/feeds/missing.xml c
+`)
+	dst := t.TempDir()
+	err := RunList(context.Background(), Options{Destination: dst}, ts.URL+"/feeds.txt", 3)
+	if err == nil {
+		t.Fatal("RunList() = nil, want an error")
+	}
+	lines := strings.Split(err.Error(), "\n")
+	for _, line := range lines {
+		if !strings.HasPrefix(line, ts.URL+"/feeds/missing.xml: ") {
+			t.Errorf("error %q does not name the missing feed", line)
+		}
+	}
+	if len(lines) != 2 {
+		t.Errorf("RunList() = %v, want two errors", err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "b", DefaultFilename)); err != nil {
+		t.Errorf("the feed of a successful job was not written: %v", err)
+	}
+
+	if err := RunList(context.Background(), Options{Destination: dst}, ts.URL+"/missing.txt", 1); err == nil {
+		t.Error("RunList() of a missing list = nil, want an error")
+	}
+}
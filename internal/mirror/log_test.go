@@ -0,0 +1,81 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package mirror
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+)
+
+// recordingHandler is a slog.Handler that records the log records it
+// handles.
+type recordingHandler struct {
+	mu      sync.Mutex
+	records []slog.Record
+}
+
+func (h *recordingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *recordingHandler) Handle(_ context.Context, r slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, r)
+	return nil
+}
+
+func (h *recordingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h *recordingHandler) WithGroup(string) slog.Handler { return h }
+
+// attrs returns the attributes of the first record with the message msg, or
+// nil if there is none.
+func (h *recordingHandler) attrs(msg string) map[string]slog.Value {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, r := range h.records {
+		if r.Message != msg {
+			continue
+		}
+		attrs := make(map[string]slog.Value)
+		r.Attrs(func(a slog.Attr) bool {
+			attrs[a.Key] = a.Value
+			return true
+		})
+		return attrs
+	}
+	return nil
+}
+
+func TestRunLogger(t *testing.T) {
+	ts := newContentServer(t, testMediaFeed)
+	h := &recordingHandler{}
+	o := Options{Source: ts.URL + "/feed.xml", Destination: t.TempDir(), Enclosures: true, Logger: slog.New(h)}
+	if err := Run(context.Background(), o); err != nil {
+		t.Fatal(err)
+	}
+	if a := h.attrs("fetch started"); a == nil || a["source"].String() != o.Source {
+		t.Errorf("fetch started = %v, want source %q", a, o.Source)
+	}
+	if a := h.attrs("feed parsed"); a == nil || a["items"].Int64() != 2 {
+		t.Errorf("feed parsed = %v, want 2 items", a)
+	}
+	body := "content of /episodes/1.mp3"
+	if a := h.attrs("content downloaded"); a == nil || a["bytes"].Int64() != int64(len(body)) {
+		t.Errorf("content downloaded = %v, want %d bytes", a, len(body))
+	}
+	if a := h.attrs("feed written"); a == nil || a["content"].Int64() != 2 {
+		t.Errorf("feed written = %v, want 2 content files", a)
+	}
+
+	// Content is not downloaded again.
+	h.records = nil
+	if err := Run(context.Background(), o); err != nil {
+		t.Fatal(err)
+	}
+	if a := h.attrs("content cache hit"); a == nil || a["path"].String() != "1.mp3" {
+		t.Errorf("content cache hit = %v, want path 1.mp3", a)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package mirror
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ManifestFilename is the name of the manifest written to the destination
+// directory of a mirror.
+const ManifestFilename = "manifest.json"
+
+// Manifest records the result of a mirror.
+type Manifest struct {
+	// Source is the source of the feed, as in Options.Source.
+	Source string `json:"source"`
+	// ResolvedURL is the URL from which the feed was fetched, after
+	// following redirects, if Source is an http(s) URL.
+	ResolvedURL string `json:"resolved_url,omitempty"`
+	// FeedSHA256 is the hex-encoded SHA-256 checksum of the feed as
+	// fetched. A mirror of an identical feed is skipped (see
+	// Options.Force), even if the server does not support conditional
+	// requests.
+	FeedSHA256 string `json:"feed_sha256,omitempty"`
+	// Feed is the name of the mirrored feed in the destination directory.
+	Feed string `json:"feed"`
+	// FetchedAt is the time at which the feed was fetched.
+	FetchedAt time.Time `json:"fetched_at"`
+	// ItemCount is the number of items in the mirrored feed.
+	ItemCount int `json:"item_count"`
+	// Content lists the downloaded enclosures and media objects.
+	Content []ContentEntry `json:"content"`
+}
+
+// ContentEntry records a downloaded enclosure or media object.
+type ContentEntry struct {
+	// URL is the original URL of the content.
+	URL string `json:"url"`
+	// Path is the path of the local copy, relative to the destination
+	// directory.
+	Path string `json:"path"`
+	// Size is the size of the content in bytes.
+	Size int64 `json:"size"`
+	// SHA256 is the hex-encoded SHA-256 checksum of the content.
+	SHA256 string `json:"sha256"`
+	// ETag is the entity tag of the content, if the server sent one.
+	ETag string `json:"etag,omitempty"`
+}
+
+// ReadManifest reads the manifest in the directory dir.
+func ReadManifest(dir string) (*Manifest, error) {
+	b, err := os.ReadFile(filepath.Join(dir, ManifestFilename))
+	if err != nil {
+		return nil, err
+	}
+	m := &Manifest{}
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// write writes m to the directory dir.
+func (m *Manifest) write(dir string) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, ManifestFilename), append(b, '\n'), 0o644)
+}
@@ -0,0 +1,131 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package mirror
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRunManifest(t *testing.T) {
+	ts := newContentServer(t, testMediaFeed)
+	dst := t.TempDir()
+	src := ts.URL + "/feed.xml"
+	start := time.Now()
+	if err := Run(context.Background(), Options{Source: src, Destination: dst, Enclosures: true}); err != nil {
+		t.Fatal(err)
+	}
+	m, err := ReadManifest(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Source != src || m.ResolvedURL != src || m.Feed != DefaultFilename || m.ItemCount != 2 {
+		t.Errorf("Manifest = %+v", m)
+	}
+	if m.FetchedAt.Before(start.Add(-time.Second)) || m.FetchedAt.After(time.Now()) {
+		t.Errorf("FetchedAt = %s, want the time of the mirror", m.FetchedAt)
+	}
+	if len(m.Content) != 2 {
+		t.Fatalf("len(Content) = %d, want 2", len(m.Content))
+	}
+	for i, e := range m.Content {
+		name := []string{"1.mp3", "2.mp3"}[i]
+		body := "content of /episodes/" + name
+		sum := sha256.Sum256([]byte(body))
+		want := ContentEntry{
+			URL:    ts.URL + "/episodes/" + name,
+			Path:   name,
+			Size:   int64(len(body)),
+			SHA256: hex.EncodeToString(sum[:]),
+		}
+		if e != want {
+			t.Errorf("Content[%d] = %+v, want %+v", i, e, want)
+		}
+	}
+}
+
+func TestRunManifestFormat(t *testing.T) {
+	dst := t.TempDir()
+	if err := Run(context.Background(), Options{Source: testFeed, Destination: dst}); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(filepath.Join(dst, ManifestFilename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"source", "feed", "fetched_at", "item_count", "content"} {
+		if _, ok := m[key]; !ok {
+			t.Errorf("manifest has no %q key:\n%s", key, b)
+		}
+	}
+	if content, ok := m["content"].([]interface{}); !ok || len(content) != 0 {
+		t.Errorf("content = %v, want an empty list", m["content"])
+	}
+}
+
+func TestRunUnchangedFeed(t *testing.T) {
+	// The server sends neither an ETag nor a Last-Modified header.
+	doc := []byte(`<rss version="2.0"><channel><title>Site</title><item><title>a</title><guid>1</guid></item></channel></rss>`)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(doc)
+	}))
+	t.Cleanup(ts.Close)
+	dst := t.TempDir()
+	path := filepath.Join(dst, DefaultFilename)
+	o := Options{Source: ts.URL, Destination: dst}
+	old := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	mirror := func() time.Time {
+		t.Helper()
+		if err := os.Chtimes(path, old, old); err != nil && !os.IsNotExist(err) {
+			t.Fatal(err)
+		}
+		if err := Run(context.Background(), o); err != nil {
+			t.Fatal(err)
+		}
+		fi, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return fi.ModTime()
+	}
+
+	mirror()
+	m, err := ReadManifest(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum := sha256.Sum256(doc); m.FeedSHA256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("FeedSHA256 = %q, want the checksum of the feed", m.FeedSHA256)
+	}
+	if mtime := mirror(); !mtime.Equal(old) {
+		t.Errorf("identical feed was rewritten at %s", mtime)
+	}
+
+	o.Force = true
+	if mtime := mirror(); mtime.Equal(old) {
+		t.Error("identical feed was not rewritten with Force")
+	}
+
+	o.Force = false
+	doc = bytes.Replace(doc, []byte("<title>a</title>"), []byte("<title>b</title>"), 1)
+	if mtime := mirror(); mtime.Equal(old) {
+		t.Error("changed feed was not rewritten")
+	}
+	if r := readFeed(t, path); r.Channel.Item[0].Title != "b" {
+		t.Errorf("Item[0].Title = %q, want %q", r.Channel.Item[0].Title, "b")
+	}
+}
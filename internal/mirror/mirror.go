@@ -0,0 +1,557 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package mirror implements mirroring of an RSS feed to a local directory.
+package mirror
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/NickolasHKraus/archor/internal/fetch"
+	"github.com/NickolasHKraus/archor/internal/httpclient"
+	"github.com/NickolasHKraus/archor/internal/source"
+	"github.com/NickolasHKraus/archor/pkg/rss"
+)
+
+// DefaultFilename is the default name of the mirrored feed in the destination
+// directory.
+const DefaultFilename = "feed.xml"
+
+// GzipExt is the extension appended to the names of files compressed with
+// gzip (see Options.Gzip).
+const GzipExt = ".gz"
+
+// Options configures a mirror.
+type Options struct {
+	// Source is the location of the feed: an http(s) URL, a file:// URI, a
+	// local path, or "-" for standard input.
+	Source string
+	// Merge are the locations of additional feeds, in the same forms as
+	// Source, whose items are merged into the feed (see
+	// rss.MergeOptions.Merge).
+	Merge []string
+	// Dedup is the strategy by which duplicate items are identified when
+	// merging feeds. If empty, rss.DedupGUIDOrLink is used.
+	Dedup rss.DedupStrategy
+	// Destination is the directory to which the feed is written.
+	Destination string
+	// Filename is a text/template for the name of the mirrored feed, with
+	// the fields {{.Title}} (the channel title) and {{.Date}} (the channel
+	// date as YYYY-MM-DD). The rendered name is sanitized for filesystem
+	// safety. If empty, DefaultFilename is used.
+	Filename string
+	// Client fetches the feed and makes the other requests of the mirror,
+	// using its HTTP client. If nil, a Client with the default
+	// httpclient.Config is used.
+	Client *fetch.Client
+	// Stdin is read when Source is "-". If nil, the Stdin of Client, or
+	// else os.Stdin, is used.
+	Stdin io.Reader
+	// MaxSize is the maximum size of the feed in bytes. A zero value means
+	// no limit.
+	MaxSize int64
+	// MaxItemsAllowed, if positive, is the maximum number of items the feed
+	// may have. A feed with more items is rejected with rss.ErrTooManyItems
+	// as soon as parsing reaches the first item over the limit, as it likely
+	// comes from a misbehaving source.
+	MaxItemsAllowed int
+	// RateLimit, if positive, is the maximum rate, in bytes per second, at
+	// which enclosures and media:content objects are downloaded, in
+	// aggregate.
+	RateLimit int64
+	// Enclosures downloads the enclosure of each item.
+	Enclosures bool
+	// Media downloads the media:content objects of each item.
+	Media bool
+	// MaxTotalSize, if positive, is the maximum total size, in bytes, of the
+	// enclosures and media:content objects kept by a mirror, including
+	// those kept from a previous mirror. Once content would exceed it, that
+	// content and the content of the following items is skipped, and the
+	// feed is still written. A zero value means no limit.
+	MaxTotalSize int64
+	// Repair applies safe fixes for common feed problems before writing.
+	Repair bool
+	// NormalizeUnicode also converts titles and descriptions to Unicode
+	// Normalization Form C when repairing the feed (see
+	// rss.RepairOptions.NormalizeUnicode).
+	NormalizeUnicode bool
+	// FixMojibake also repairs double-encoded UTF-8 in titles and
+	// descriptions when repairing the feed (see
+	// rss.RepairOptions.FixMojibake).
+	FixMojibake bool
+	// RequireValid fails the mirror, before anything is written, if the
+	// feed is invalid (see rss.RSS.Validate). The feed is validated after
+	// Repair, if set. By default, invalid feeds are mirrored.
+	RequireValid bool
+	// Log receives a line for each change made by Repair. If nil, changes
+	// are not reported.
+	Log io.Writer
+	// StripElements are the names of child elements removed from the
+	// channel and its items, such as "author" (see rss.RSS.StripElements).
+	StripElements []string
+	// Since, if non-zero, removes items published before it.
+	Since time.Time
+	// StrictDates removes items whose publication date is missing or cannot
+	// be parsed when Since is set. By default they are kept.
+	StrictDates bool
+	// BaseURL, if set, is the URL against which relative URLs in the feed
+	// are resolved.
+	BaseURL string
+	// CleanLinks removes tracking query parameters from the URLs referenced
+	// by the feed.
+	CleanLinks bool
+	// TrackingParams are the query parameters removed if CleanLinks is set.
+	// If empty, rss.DefaultTrackingParams are removed.
+	TrackingParams []string
+	// MaxItems, if positive, is the maximum number of items to mirror. Only
+	// the most recent items are kept.
+	MaxItems int
+	// UTCDates converts the dates of the feed to UTC.
+	UTCDates bool
+	// DateFormat, if set, is the format in which the dates of the feed are
+	// written. By default, dates are written as in the source feed.
+	DateFormat rss.DateFormat
+	// PreserveDates keeps the publication and last build dates of the
+	// channel. By default, the last build date is set to the time at which
+	// the feed last changed (see rss.RSS.Equal), and a missing publication
+	// date is set to that of the latest item (see rss.Channel.LatestPubDate).
+	PreserveDates bool
+	// Sanitize removes potentially unsafe HTML, such as scripts and event
+	// handlers, from the description and content of each item.
+	Sanitize bool
+	// Transforms are applied, in order, to each item of the feed (see
+	// rss.Channel.TransformItems).
+	Transforms []Transform
+	// Strict fails the mirror on conditions that are otherwise logged as
+	// warnings: the feed having lint warnings (see rss.RSS.Lint), such as
+	// unknown elements, or the type of downloaded content differing from its
+	// declared type. All the lint warnings are returned, as
+	// rss.ValidationErrors, except those of recommendations (see
+	// rss.IsRecommendation), which are still only logged.
+	Strict bool
+	// FutureDateThreshold is how far in the future an item may be dated
+	// before it is reported with a lint warning (see
+	// rss.LintOptions.FutureDateThreshold).
+	FutureDateThreshold time.Duration
+	// Force rewrites the feed even if it is identical to the feed fetched
+	// and written by the previous mirror to Destination, which is otherwise
+	// left untouched, along with the original feed, items, archive and sitemap.
+	Force bool
+	// Podcast mirrors the feed as a podcast: items without a valid
+	// enclosure are reported with a lint warning (see
+	// rss.LintOptions.Podcast), and the iTunes namespace is declared on the
+	// <rss> element so that iTunes elements keep their itunes: prefix.
+	Podcast bool
+	// DisableRules are the IDs of the lint rules that are not checked (see
+	// rss.LintOptions.DisableRules).
+	DisableRules []string
+	// Logger, if set, receives structured events describing the operations
+	// of the mirror, such as fetches and downloads.
+	Logger *slog.Logger
+	// PreserveGenerator keeps the generator of the source feed. By default,
+	// or if the source feed has none, the generator is set to
+	// rss.DefaultGenerator().
+	PreserveGenerator bool
+	// KeepOriginal also writes the feed as fetched, before any changes, next
+	// to the mirrored feed. Its name is that of the mirrored feed with
+	// ".original" inserted before the extension (see OriginalFilename).
+	KeepOriginal bool
+	// Gzip compresses the mirrored feed, and the feed as fetched if
+	// KeepOriginal is set, with gzip, appending GzipExt to their names.
+	Gzip bool
+	// SplitItems also writes each item to its own file in the ItemsDir
+	// directory of the destination.
+	SplitItems bool
+	// ArchiveBy, if set, also writes the items of the feed to archive feeds,
+	// one for each month or year of publication, named after the mirrored
+	// feed (see ArchiveFilename). Items without a valid publication date
+	// are written to the UndatedBucket archive feed.
+	ArchiveBy ArchiveBy
+	// Sitemap also writes a sitemap listing the link of each item, with its
+	// publication date as the date of last modification, to
+	// SitemapFilename in the destination.
+	Sitemap bool
+	// NotifyURL, if set, is sent a POST request with a JSON Notification
+	// after a successful mirror, unless the feed is unchanged since the
+	// previous mirror.
+	NotifyURL string
+
+	// limiter limits the rate of downloads to RateLimit. It is shared by
+	// the copies of the options made for a mirror.
+	limiter *limiter
+}
+
+// Run mirrors the feed at o.Source to o.Destination, along with a manifest
+// (see Manifest) of the mirror. Requests are canceled
+// when ctx is done.
+func Run(ctx context.Context, o Options) error {
+	_, err := o.run(ctx)
+	return err
+}
+
+// run mirrors the feed at o.Source and returns the mirrored feed.
+func (o Options) run(ctx context.Context) (*rss.RSS, error) {
+	fetchedAt := time.Now().UTC()
+	if o.limiter == nil {
+		o.limiter = newLimiter(o.RateLimit)
+	}
+	o.Client = o.fetcher()
+	o.logger().Info("fetch started", "source", o.Source)
+	resp, err := o.getFeed(ctx, o.Source)
+	if err != nil {
+		return nil, err
+	}
+	// The content of the feed is downloaded with the credentials of the
+	// client only if it is on the host of the feed.
+	ctx = withCredentials(ctx, o.Source)
+	r, resolved := resp.Feed, resp.URL
+	o.logger().Info("feed parsed", "title", r.Channel.Title, "items", len(r.Channel.Item))
+	hash := sha256.Sum256(resp.Body)
+	sum := hex.EncodeToString(hash[:])
+	if len(o.Merge) > 0 {
+		if r, err = o.merge(ctx, r); err != nil {
+			return nil, err
+		}
+	}
+	if o.Repair {
+		for _, change := range (rss.RepairOptions{NormalizeUnicode: o.NormalizeUnicode, FixMojibake: o.FixMojibake}).Repair(r) {
+			o.logf("repair: %s\n", change)
+		}
+	}
+	if o.RequireValid {
+		if err := r.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid feed: %w", err)
+		}
+	}
+	if o.BaseURL != "" {
+		base, err := url.Parse(o.BaseURL)
+		if err != nil || !base.IsAbs() {
+			return nil, fmt.Errorf("invalid base URL %q", o.BaseURL)
+		}
+		r.ResolveLinks(base)
+	}
+	if o.CleanLinks {
+		r.StripQueryParams(o.TrackingParams)
+	}
+	if o.Sanitize {
+		for _, item := range r.Channel.Item {
+			item.Description = rss.Description(rss.SanitizeHTML(string(item.Description)))
+			item.ContentEncoded = rss.ContentEncoded(rss.SanitizeHTML(string(item.ContentEncoded)))
+		}
+	}
+	for _, fn := range o.Transforms {
+		r.Channel.TransformItems(fn)
+	}
+	if len(o.StripElements) > 0 {
+		n, err := r.StripElements(o.StripElements...)
+		if err != nil {
+			return nil, err
+		}
+		o.logger().Info("elements stripped", "names", o.StripElements, "count", n)
+	}
+	if !o.Since.IsZero() {
+		r.Channel.PruneBefore(o.Since, o.StrictDates)
+	}
+	if o.UTCDates {
+		r.DatesToUTC()
+	}
+	if o.DateFormat != "" {
+		if !o.DateFormat.IsValid() {
+			return nil, fmt.Errorf("invalid date format %q", o.DateFormat)
+		}
+		r.FormatDates(o.DateFormat)
+	}
+	if o.MaxItems > 0 {
+		r.Channel.KeepLatest(o.MaxItems)
+	}
+	if !o.PreserveGenerator || r.Channel.Generator == "" {
+		r.Channel.Generator = rss.DefaultGenerator()
+	}
+	if o.Podcast {
+		if err := r.DeclareNamespace("itunes", rss.ItunesNamespace); err != nil {
+			return nil, err
+		}
+	}
+	lint := rss.LintOptions{FutureDateThreshold: o.FutureDateThreshold, DisableRules: o.DisableRules, Podcast: o.Podcast}
+	var lintErrs rss.ValidationErrors
+	for _, w := range lint.Lint(r) {
+		if o.Strict && !rss.IsRecommendation(w.Rule) {
+			lintErrs = append(lintErrs, w)
+			continue
+		}
+		o.logf("warning: %s [%s]\n", w, w.Rule)
+	}
+	if len(lintErrs) > 0 {
+		return nil, lintErrs
+	}
+	name, err := o.filename(r.Channel)
+	if err != nil {
+		return nil, err
+	}
+	archiveName := name
+	originalName := OriginalFilename(name)
+	if o.Gzip {
+		name += GzipExt
+		originalName += GzipExt
+	}
+	path := filepath.Join(o.Destination, name)
+	old := readPrevious(path)
+	if !o.PreserveDates {
+		o.stampDates(r, old, fetchedAt)
+	}
+	b, err := rss.MarshalOptions{PreserveOrder: true}.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(o.Destination, 0o755); err != nil {
+		return nil, err
+	}
+	content, err := o.downloadContent(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	// The output is only rewritten if the feed or its processing changed.
+	rewrite := o.Force || !o.sameSource(sum) || !old.Equal(r)
+	if !rewrite {
+		o.logger().Info("feed not rewritten", "path", path, "sha256", sum)
+	} else {
+		if err := o.writeFeed(path, b); err != nil {
+			return nil, err
+		}
+		if o.KeepOriginal {
+			if err := o.writeFeed(filepath.Join(o.Destination, originalName), resp.Body); err != nil {
+				return nil, err
+			}
+		}
+		if o.SplitItems {
+			if err := o.writeItems(r); err != nil {
+				return nil, err
+			}
+		}
+		if o.ArchiveBy != "" {
+			if err := o.writeArchive(r, archiveName); err != nil {
+				return nil, err
+			}
+		}
+		if o.Sitemap {
+			if err := o.writeSitemap(r); err != nil {
+				return nil, err
+			}
+		}
+	}
+	m := &Manifest{
+		Source:      o.Source,
+		ResolvedURL: resolved,
+		FeedSHA256:  sum,
+		Feed:        name,
+		FetchedAt:   fetchedAt,
+		ItemCount:   len(r.Channel.Item),
+		Content:     content,
+	}
+	if err := m.write(o.Destination); err != nil {
+		return nil, err
+	}
+	if rewrite {
+		o.logger().Info("feed written", "path", path, "bytes", len(b), "items", len(r.Channel.Item), "content", len(content))
+	}
+	if old.Equal(r) {
+		o.logger().Info("feed unchanged", "path", path)
+		return r, nil
+	}
+	if o.NotifyURL != "" {
+		if err := o.notify(ctx, old, r); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// sameSource returns true if the feed fetched from o.Source, whose SHA-256
+// checksum is sum, is identical to the feed fetched by the previous mirror to
+// o.Destination, as recorded in its manifest. Unlike a conditional request,
+// this works with servers that send neither an ETag nor a Last-Modified
+// header.
+func (o Options) sameSource(sum string) bool {
+	m, err := ReadManifest(o.Destination)
+	return err == nil && m.Source == o.Source && m.FeedSHA256 == sum
+}
+
+// stampDates sets the publication date of the channel of r to that of its
+// latest item if it has none, and its last build date to now, in the format
+// o.DateFormat. If r is otherwise equal to the previously mirrored feed old,
+// the last build date of old is kept, as the content has not changed.
+func (o Options) stampDates(r, old *rss.RSS, now time.Time) {
+	c := r.Channel
+	if c.PubDate == "" {
+		c.PubDate = c.LatestPubDate()
+	}
+	if old != nil && old.Channel.LastBuildDate != "" {
+		c.LastBuildDate = old.Channel.LastBuildDate
+		if old.Equal(r) {
+			return
+		}
+	}
+	c.LastBuildDate = rss.LastBuildDate(o.DateFormat.Format(now))
+}
+
+// writeFeed writes the feed b to the file path, compressing it with gzip if
+// o.Gzip is set.
+func (o Options) writeFeed(path string, b []byte) error {
+	if !o.Gzip {
+		return os.WriteFile(path, b, 0o644)
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(b); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// readPrevious returns the previously mirrored feed at path, which may be
+// compressed with gzip, or nil if there is none or it cannot be parsed.
+func readPrevious(path string) *rss.RSS {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	br := bufio.NewReader(f)
+	var in io.Reader = br
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil
+		}
+		in = zr
+	}
+	r, err := rss.Parse(in)
+	if err != nil {
+		return nil
+	}
+	return r
+}
+
+// OriginalFilename returns the name of the feed as fetched for the mirrored
+// feed name, e.g. "feed.original.xml" for "feed.xml".
+func OriginalFilename(name string) string {
+	ext := filepath.Ext(name)
+	return strings.TrimSuffix(name, ext) + ".original" + ext
+}
+
+// merge fetches the feeds at o.Merge and merges their items into r.
+func (o Options) merge(ctx context.Context, r *rss.RSS) (*rss.RSS, error) {
+	feeds := []*rss.RSS{r}
+	for _, src := range o.Merge {
+		o.logger().Info("fetch started", "source", src)
+		f, err := o.fetch(ctx, src)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", src, err)
+		}
+		feeds = append(feeds, f)
+	}
+	merged, err := rss.MergeOptions{Dedup: o.Dedup}.Merge(feeds...)
+	if err != nil {
+		return nil, err
+	}
+	o.logger().Info("feeds merged", "feeds", len(feeds), "items", len(merged.Channel.Item))
+	return merged, nil
+}
+
+// fetch reads and parses the feed at src.
+func (o Options) fetch(ctx context.Context, src string) (*rss.RSS, error) {
+	resp, err := o.getFeed(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Feed, nil
+}
+
+// getFeed fetches the feed at src, enforcing o.MaxSize and o.MaxItemsAllowed.
+func (o Options) getFeed(ctx context.Context, src string) (*fetch.Response, error) {
+	c := o.fetcher()
+	if src == "-" && o.Stdin != nil {
+		// o.Stdin takes precedence over the Stdin of the client. Standard
+		// input is not fetched over HTTP, so the client has nothing to share.
+		c = &fetch.Client{Config: c.Config, Stdin: o.Stdin}
+	}
+	return c.Get(ctx, src, rss.ParseOptions{MaxBytes: o.MaxSize, MaxItems: o.MaxItemsAllowed})
+}
+
+// filename returns the name of the mirrored feed of the channel c.
+func (o Options) filename(c *rss.Channel) (string, error) {
+	if o.Filename == "" {
+		return DefaultFilename, nil
+	}
+	return renderFilename(o.Filename, c)
+}
+
+func (o Options) logf(format string, a ...interface{}) {
+	if o.Log != nil {
+		fmt.Fprintf(o.Log, format, a...)
+	}
+}
+
+// discardLogger is the logger used if Options.Logger is not set.
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelError + 1}))
+
+func (o Options) logger() *slog.Logger {
+	if o.Logger != nil {
+		return o.Logger
+	}
+	return discardLogger
+}
+
+// withCredentials returns a copy of ctx with which the requests to the host of
+// src, if it is an http(s) URL, carry the credentials of the client (see
+// httpclient.WithCredentials).
+func withCredentials(ctx context.Context, src string) context.Context {
+	u, err := url.Parse(src)
+	if err != nil || !source.IsHTTP(src) {
+		return ctx
+	}
+	return httpclient.WithCredentials(ctx, u.Host)
+}
+
+// fetcher returns the Client of o, or a Client with the default
+// httpclient.Config if it is not set.
+func (o Options) fetcher() *fetch.Client {
+	if o.Client != nil {
+		return o.Client
+	}
+	c := fetch.New(httpclient.Config{})
+	c.Stdin = o.Stdin
+	return c
+}
+
+func (o Options) client() *http.Client {
+	return o.fetcher().HTTP()
+}
+
+func (o Options) stdin() io.Reader {
+	if o.Stdin != nil {
+		return o.Stdin
+	}
+	if o.Client != nil && o.Client.Stdin != nil {
+		return o.Client.Stdin
+	}
+	return os.Stdin
+}
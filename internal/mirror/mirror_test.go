@@ -0,0 +1,725 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package mirror
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NickolasHKraus/archor/internal/fetch"
+	"github.com/NickolasHKraus/archor/internal/httpclient"
+	"github.com/NickolasHKraus/archor/pkg/rss"
+)
+
+const testFeed = "../../test/data/rss-0.xml"
+
+func newFeedServer(t *testing.T, name string) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, name)
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func readFeed(t *testing.T, name string) *rss.RSS {
+	t.Helper()
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	r, err := rss.Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestRun(t *testing.T) {
+	ts := newFeedServer(t, testFeed)
+	dst := t.TempDir()
+	if err := Run(context.Background(), Options{Source: ts.URL, Destination: dst}); err != nil {
+		t.Fatal(err)
+	}
+	r := readFeed(t, filepath.Join(dst, DefaultFilename))
+	if got := len(r.Channel.Item); got != 4 {
+		t.Errorf("len(Channel.Item) = %d, want 4", got)
+	}
+}
+
+func TestRunUnexpectedStatus(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+	if err := Run(context.Background(), Options{Source: ts.URL, Destination: t.TempDir()}); err == nil {
+		t.Fatal("expected error for 404 response")
+	}
+}
+
+func TestRunRedirects(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a":
+			http.Redirect(w, r, "/b", http.StatusMovedPermanently)
+		case "/b":
+			http.Redirect(w, r, "/c", http.StatusFound)
+		case "/c":
+			http.Redirect(w, r, "/feed.xml", http.StatusTemporaryRedirect)
+		case "/feed.xml":
+			http.ServeFile(w, r, testFeed)
+		case "/loop":
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+	dst := t.TempDir()
+	if err := Run(context.Background(), Options{Source: ts.URL + "/a", Destination: dst}); err != nil {
+		t.Fatal(err)
+	}
+	m, err := ReadManifest(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := ts.URL + "/feed.xml"; m.Source != ts.URL+"/a" || m.ResolvedURL != want {
+		t.Errorf("Manifest Source = %q, ResolvedURL = %q, want ResolvedURL %q", m.Source, m.ResolvedURL, want)
+	}
+
+	tests := []struct {
+		path string
+		max  int
+		want error
+	}{
+		{"/a", 2, httpclient.ErrTooManyRedirects},
+		{"/loop", 0, httpclient.ErrRedirectLoop},
+	}
+	for _, tt := range tests {
+		c := fetch.New(httpclient.Config{MaxRedirects: tt.max})
+		err := Run(context.Background(), Options{Source: ts.URL + tt.path, Destination: t.TempDir(), Client: c})
+		if !errors.Is(err, tt.want) {
+			t.Errorf("MaxRedirects=%d: Run(%s) error = %v, want %v", tt.max, tt.path, err, tt.want)
+		}
+	}
+}
+
+func TestRunLocalPath(t *testing.T) {
+	dst := t.TempDir()
+	if err := Run(context.Background(), Options{Source: testFeed, Destination: dst}); err != nil {
+		t.Fatal(err)
+	}
+	r := readFeed(t, filepath.Join(dst, DefaultFilename))
+	if got := len(r.Channel.Item); got != 4 {
+		t.Errorf("len(Channel.Item) = %d, want 4", got)
+	}
+}
+
+func TestRunGzipPath(t *testing.T) {
+	dst := t.TempDir()
+	if err := Run(context.Background(), Options{Source: testFeed + ".gz", Destination: dst}); err != nil {
+		t.Fatal(err)
+	}
+	r := readFeed(t, filepath.Join(dst, DefaultFilename))
+	if got := len(r.Channel.Item); got != 4 {
+		t.Errorf("len(Channel.Item) = %d, want 4", got)
+	}
+}
+
+func TestRunFileURI(t *testing.T) {
+	abs, err := filepath.Abs(testFeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst := t.TempDir()
+	if err := Run(context.Background(), Options{Source: "file://" + filepath.ToSlash(abs), Destination: dst}); err != nil {
+		t.Fatal(err)
+	}
+	readFeed(t, filepath.Join(dst, DefaultFilename))
+}
+
+func TestRunStdin(t *testing.T) {
+	f, err := os.Open(testFeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	dst := t.TempDir()
+	if err := Run(context.Background(), Options{Source: "-", Destination: dst, Stdin: f}); err != nil {
+		t.Fatal(err)
+	}
+	r := readFeed(t, filepath.Join(dst, DefaultFilename))
+	if r.Channel.Title != "Liftoff News" {
+		t.Errorf("Channel.Title = %q, want %q", r.Channel.Title, "Liftoff News")
+	}
+}
+
+// sliceReader is an io.Reader whose dynamic type is not comparable.
+type sliceReader struct {
+	b []byte
+	r *bytes.Reader
+}
+
+func (s sliceReader) Read(p []byte) (int, error) {
+	return s.r.Read(p)
+}
+
+func TestRunStdinOverridesClient(t *testing.T) {
+	b, err := os.ReadFile(testFeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := fetch.New(httpclient.Config{})
+	c.Stdin = strings.NewReader("not a feed")
+	dst := t.TempDir()
+	o := Options{Source: "-", Destination: dst, Client: c, Stdin: sliceReader{b: b, r: bytes.NewReader(b)}}
+	if err := Run(context.Background(), o); err != nil {
+		t.Fatal(err)
+	}
+	r := readFeed(t, filepath.Join(dst, DefaultFilename))
+	if r.Channel.Title != "Liftoff News" {
+		t.Errorf("Channel.Title = %q, want %q", r.Channel.Title, "Liftoff News")
+	}
+}
+
+func TestRunUnsupportedScheme(t *testing.T) {
+	if err := Run(context.Background(), Options{Source: "ftp://example.com/feed.xml", Destination: t.TempDir()}); err == nil {
+		t.Fatal("expected error for unsupported scheme")
+	}
+}
+
+func TestRunMaxSize(t *testing.T) {
+	dst := t.TempDir()
+	err := Run(context.Background(), Options{Source: testFeed, Destination: dst, MaxSize: 64})
+	if !errors.Is(err, rss.ErrFeedTooLarge) {
+		t.Fatalf("got %v, want ErrFeedTooLarge", err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, DefaultFilename)); !os.IsNotExist(err) {
+		t.Errorf("expected no feed to be written, got %v", err)
+	}
+}
+
+func TestRunMaxItemsAllowed(t *testing.T) {
+	// The test feed has 4 items.
+	tests := []struct {
+		max     int
+		wantErr bool
+	}{
+		{0, false},
+		{4, false},
+		{3, true},
+	}
+	for _, tt := range tests {
+		err := Run(context.Background(), Options{Source: testFeed, Destination: t.TempDir(), MaxItemsAllowed: tt.max})
+		if got := errors.Is(err, rss.ErrTooManyItems); got != tt.wantErr || (err != nil && !got) {
+			t.Errorf("MaxItemsAllowed = %d: got %v, want ErrTooManyItems: %v", tt.max, err, tt.wantErr)
+		}
+	}
+}
+
+func TestRunKeepOriginal(t *testing.T) {
+	ts := newFeedServer(t, testFeed)
+	dst := t.TempDir()
+	if err := Run(context.Background(), Options{Source: ts.URL, Destination: dst, Repair: true, KeepOriginal: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, DefaultFilename)); err != nil {
+		t.Error(err)
+	}
+	want, err := os.ReadFile(testFeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(filepath.Join(dst, "feed.original.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("feed.original.xml differs from the served feed:\n%s", got)
+	}
+}
+
+func TestRunGzip(t *testing.T) {
+	dst := t.TempDir()
+	o := Options{Source: testFeed, Destination: dst, Gzip: true, KeepOriginal: true}
+	if err := Run(context.Background(), o); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{DefaultFilename, "feed.original.xml"} {
+		f, err := os.Open(filepath.Join(dst, name+GzipExt))
+		if err != nil {
+			t.Fatal(err)
+		}
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r, err := rss.Parse(zr)
+		f.Close()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if got := len(r.Channel.Item); got != 4 {
+			t.Errorf("%s: len(Channel.Item) = %d, want 4", name, got)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dst, DefaultFilename)); !os.IsNotExist(err) {
+		t.Errorf("expected no uncompressed feed, got %v", err)
+	}
+	m, err := ReadManifest(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Feed != DefaultFilename+GzipExt {
+		t.Errorf("Manifest.Feed = %q, want %q", m.Feed, DefaultFilename+GzipExt)
+	}
+	if readPrevious(filepath.Join(dst, DefaultFilename+GzipExt)) == nil {
+		t.Error("readPrevious() = nil, want the compressed feed")
+	}
+}
+
+func TestOriginalFilename(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{"feed.xml", "feed.original.xml"},
+		{"Liftoff-News-2003-06-10.rss", "Liftoff-News-2003-06-10.original.rss"},
+		{"feed", "feed.original"},
+	}
+	for _, tt := range tests {
+		if got := OriginalFilename(tt.name); got != tt.want {
+			t.Errorf("OriginalFilename(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRunRepair(t *testing.T) {
+	const doc = `<rss version="2.0"><channel><title> Liftoff News </title><language>EN-US</language></channel></rss>`
+	var log bytes.Buffer
+	dst := t.TempDir()
+	o := Options{Source: "-", Destination: dst, Stdin: strings.NewReader(doc), Repair: true, Log: &log}
+	if err := Run(context.Background(), o); err != nil {
+		t.Fatal(err)
+	}
+	r := readFeed(t, filepath.Join(dst, DefaultFilename))
+	if r.Channel.Title != "Liftoff News" || r.Channel.Language != "en-us" {
+		t.Errorf("feed not repaired: title %q, language %q", r.Channel.Title, r.Channel.Language)
+	}
+	want := "repair: title: trimmed whitespace\nrepair: language: lowercased \"EN-US\" to \"en-us\"\n"
+	if log.String() != want {
+		t.Errorf("log = %q, want %q", log.String(), want)
+	}
+}
+
+func TestRunSince(t *testing.T) {
+	dst := t.TempDir()
+	since := time.Date(2003, time.May, 28, 0, 0, 0, 0, time.UTC)
+	if err := Run(context.Background(), Options{Source: testFeed, Destination: dst, Since: since}); err != nil {
+		t.Fatal(err)
+	}
+	r := readFeed(t, filepath.Join(dst, DefaultFilename))
+	if got := len(r.Channel.Item); got != 2 {
+		t.Fatalf("len(Channel.Item) = %d, want 2", got)
+	}
+	for _, item := range r.Channel.Item {
+		if pt, _ := item.PubDate.Time(); pt.Before(since) {
+			t.Errorf("item published %s before %s was kept", item.PubDate, since)
+		}
+	}
+}
+
+func TestRunSinceStrictDates(t *testing.T) {
+	const doc = `<rss version="2.0"><channel>
+<item><title>a</title><pubDate>2003-06-03T09:39:21Z</pubDate></item>
+<item><title>b</title></item>
+</channel></rss>`
+	since := time.Date(2003, time.May, 28, 0, 0, 0, 0, time.UTC)
+	for _, strict := range []bool{false, true} {
+		dst := t.TempDir()
+		o := Options{Source: "-", Destination: dst, Stdin: strings.NewReader(doc), Since: since, StrictDates: strict}
+		if err := Run(context.Background(), o); err != nil {
+			t.Fatal(err)
+		}
+		want := 2
+		if strict {
+			want = 1
+		}
+		if got := len(readFeed(t, filepath.Join(dst, DefaultFilename)).Channel.Item); got != want {
+			t.Errorf("StrictDates=%v: len(Channel.Item) = %d, want %d", strict, got, want)
+		}
+	}
+}
+
+func TestRunBaseURL(t *testing.T) {
+	const doc = `<rss version="2.0"><channel><title>Site</title><link>/</link>
+<item><title>a</title><link>/article/1</link></item>
+</channel></rss>`
+	dst := t.TempDir()
+	o := Options{Source: "-", Destination: dst, Stdin: strings.NewReader(doc), BaseURL: "https://site.com"}
+	if err := Run(context.Background(), o); err != nil {
+		t.Fatal(err)
+	}
+	r := readFeed(t, filepath.Join(dst, DefaultFilename))
+	if r.Channel.Link != "https://site.com/" {
+		t.Errorf("Channel.Link = %q, want %q", r.Channel.Link, "https://site.com/")
+	}
+	if got := r.Channel.Item[0].Link; got != "https://site.com/article/1" {
+		t.Errorf("Item[0].Link = %q, want %q", got, "https://site.com/article/1")
+	}
+}
+
+func TestRunInvalidBaseURL(t *testing.T) {
+	if err := Run(context.Background(), Options{Source: testFeed, Destination: t.TempDir(), BaseURL: "site.com"}); err == nil {
+		t.Fatal("expected error for relative base URL")
+	}
+}
+
+func TestRunSanitize(t *testing.T) {
+	const doc = `<rss version="2.0" xmlns:content="http://purl.org/rss/1.0/modules/content/"><channel><title>Site</title>
+<item><description>&lt;p onclick="x()"&gt;Hi&lt;/p&gt;&lt;script&gt;x()&lt;/script&gt;</description>
+<content:encoded><![CDATA[<p>Full</p><script>x()</script>]]></content:encoded></item>
+</channel></rss>`
+	dst := t.TempDir()
+	o := Options{Source: "-", Destination: dst, Stdin: strings.NewReader(doc), Sanitize: true}
+	if err := Run(context.Background(), o); err != nil {
+		t.Fatal(err)
+	}
+	item := readFeed(t, filepath.Join(dst, DefaultFilename)).Channel.Item[0]
+	if item.Description != "<p>Hi</p>" {
+		t.Errorf("Description = %q, want %q", item.Description, "<p>Hi</p>")
+	}
+	if item.ContentEncoded != "<p>Full</p>" {
+		t.Errorf("ContentEncoded = %q, want %q", item.ContentEncoded, "<p>Full</p>")
+	}
+}
+
+func TestRunAtom(t *testing.T) {
+	dst := t.TempDir()
+	if err := Run(context.Background(), Options{Source: "../../test/data/atom-0.xml", Destination: dst}); err != nil {
+		t.Fatal(err)
+	}
+	r := readFeed(t, filepath.Join(dst, DefaultFilename))
+	if r.Channel.Title != "Example Feed" || len(r.Channel.Item) != 2 {
+		t.Errorf("Channel.Title = %q with %d items, want %q with 2 items", r.Channel.Title, len(r.Channel.Item), "Example Feed")
+	}
+}
+
+func TestRunDuplicateGUIDs(t *testing.T) {
+	const doc = `<rss version="2.0"><channel><title>Site</title>
+<item><title>a</title><guid>1</guid></item>
+<item><title>b</title><guid>1</guid></item>
+</channel></rss>`
+	var log bytes.Buffer
+	o := Options{Source: "-", Destination: t.TempDir(), Stdin: strings.NewReader(doc), Log: &log}
+	if err := Run(context.Background(), o); err != nil {
+		t.Fatal(err)
+	}
+	if want := "warning: item[1].guid: duplicate guid (same as item[0]) [duplicate-guid]\n"; log.String() != want {
+		t.Errorf("log = %q, want %q", log.String(), want)
+	}
+
+	o.Stdin = strings.NewReader(doc)
+	o.Strict = true
+	if err := Run(context.Background(), o); !errors.Is(err, rss.ErrDuplicateGUID) {
+		t.Errorf("Run() error = %v, want ErrDuplicateGUID", err)
+	}
+}
+
+func TestRunStrictUnknownElement(t *testing.T) {
+	const doc = `<rss version="2.0"><channel><title>Site</title><item><titel>a</titel></item></channel></rss>`
+	for _, strict := range []bool{false, true} {
+		o := Options{Source: "-", Destination: t.TempDir(), Stdin: strings.NewReader(doc), Strict: strict}
+		err := Run(context.Background(), o)
+		if strict != errors.Is(err, rss.ErrUnknownElement) {
+			t.Errorf("Strict=%v: Run() error = %v", strict, err)
+		}
+	}
+}
+
+func TestRunDisableRules(t *testing.T) {
+	const doc = `<rss version="2.0"><channel><title>Site</title>
+<item><title>a</title><guid>1</guid></item>
+<item><title>b</title><guid>1</guid></item>
+</channel></rss>`
+	tests := []struct {
+		disable []string
+		want    error
+	}{
+		{nil, rss.ErrDuplicateGUID},
+		{[]string{rss.RuleDuplicateGUID}, nil},
+	}
+	for _, tt := range tests {
+		o := Options{Source: "-", Destination: t.TempDir(), Stdin: strings.NewReader(doc), Strict: true, DisableRules: tt.disable}
+		if err := Run(context.Background(), o); !errors.Is(err, tt.want) {
+			t.Errorf("DisableRules=%v: Run() error = %v, want %v", tt.disable, err, tt.want)
+		}
+	}
+}
+
+func TestRunStrictLint(t *testing.T) {
+	const doc = `<rss version="2.0"><channel><title>Site</title>
+<item><title>a</title><guid>1</guid><titel>a</titel></item>
+<item><title>b</title><guid>1</guid></item>
+<item><title>c</title></item>
+</channel></rss>`
+	var log bytes.Buffer
+	o := Options{Source: "-", Destination: t.TempDir(), Stdin: strings.NewReader(doc), Strict: true, Log: &log}
+	err := Run(context.Background(), o)
+	var errs rss.ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Run() error = %v, want ValidationErrors", err)
+	}
+	var got []string
+	for _, e := range errs {
+		got = append(got, e.Rule+" "+e.Path)
+	}
+	// The missing guid is a recommendation, so it is not an error.
+	if want := []string{"unknown-element item[0].titel", "duplicate-guid item[1].guid"}; strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("Run() errors = %v, want %v", got, want)
+	}
+	if want := "warning: item[2]: missing recommended guid [missing-guid]\n"; log.String() != want {
+		t.Errorf("log = %q, want %q", log.String(), want)
+	}
+
+	// A feed with only recommendation warnings is mirrored.
+	log.Reset()
+	o.Stdin = strings.NewReader(`<rss version="2.0"><channel><title>Site</title><item><title>a</title></item></channel></rss>`)
+	if err := Run(context.Background(), o); err != nil {
+		t.Errorf("Run() error = %v, want nil", err)
+	}
+}
+
+func TestRunPodcast(t *testing.T) {
+	const podcast = "../../test/data/rss-podcast.xml"
+	dst := t.TempDir()
+	var log bytes.Buffer
+	if err := Run(context.Background(), Options{Source: podcast, Destination: dst, Podcast: true, Log: &log}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "warning: item[1]: missing enclosure [missing-enclosure]\n"; log.String() != want {
+		t.Errorf("log = %q, want %q", log.String(), want)
+	}
+	b, err := os.ReadFile(filepath.Join(dst, DefaultFilename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`xmlns:itunes="` + rss.ItunesNamespace + `"`, "<itunes:author>Example Author</itunes:author>", `<itunes:category text="Technology"></itunes:category>`, "<itunes:episode>2</itunes:episode>"} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("mirrored feed does not contain %s", want)
+		}
+	}
+
+	for _, podcastMode := range []bool{false, true} {
+		o := Options{Source: podcast, Destination: t.TempDir(), Podcast: podcastMode, Strict: true}
+		if err := Run(context.Background(), o); podcastMode != errors.Is(err, rss.ErrMissingEnclosure) {
+			t.Errorf("Podcast=%v, Strict: Run() error = %v", podcastMode, err)
+		}
+	}
+}
+
+func TestRunUTCDates(t *testing.T) {
+	const doc = `<rss version="2.0"><channel><title>Site</title>
+<item><title>a</title><pubDate>Tue, 03 Jun 2003 04:39:21 EST</pubDate></item>
+</channel></rss>`
+	dst := t.TempDir()
+	o := Options{Source: "-", Destination: dst, Stdin: strings.NewReader(doc), UTCDates: true}
+	if err := Run(context.Background(), o); err != nil {
+		t.Fatal(err)
+	}
+	r := readFeed(t, filepath.Join(dst, DefaultFilename))
+	if want := rss.PubDate("Tue, 03 Jun 2003 09:39:21 +0000"); r.Channel.Item[0].PubDate != want {
+		t.Errorf("Item[0].PubDate = %q, want %q", r.Channel.Item[0].PubDate, want)
+	}
+}
+
+func TestRunDateFormat(t *testing.T) {
+	const doc = `<rss version="2.0"><channel><title>Site</title>
+<item><title>a</title><pubDate>2003-06-03T09:39:21Z</pubDate></item>
+</channel></rss>`
+	tests := []struct {
+		format rss.DateFormat
+		want   rss.PubDate
+	}{
+		{"", "2003-06-03T09:39:21Z"},
+		{rss.DateFormatRFC822, "03 Jun 03 09:39 +0000"},
+		{rss.DateFormatRFC1123Z, "Tue, 03 Jun 2003 09:39:21 +0000"},
+		{rss.DateFormatRFC3339, "2003-06-03T09:39:21Z"},
+	}
+	for _, tt := range tests {
+		dst := t.TempDir()
+		o := Options{Source: "-", Destination: dst, Stdin: strings.NewReader(doc), DateFormat: tt.format}
+		if err := Run(context.Background(), o); err != nil {
+			t.Fatal(err)
+		}
+		r := readFeed(t, filepath.Join(dst, DefaultFilename))
+		if got := r.Channel.Item[0].PubDate; got != tt.want {
+			t.Errorf("DateFormat = %q: Item[0].PubDate = %q, want %q", tt.format, got, tt.want)
+		}
+	}
+	o := Options{Source: "-", Destination: t.TempDir(), Stdin: strings.NewReader(doc), DateFormat: "iso"}
+	if err := Run(context.Background(), o); err == nil {
+		t.Error("expected error for invalid date format")
+	}
+}
+
+func TestRunStampDates(t *testing.T) {
+	const doc = `<rss version="2.0"><channel><title>Site</title><lastBuildDate>Tue, 10 Jun 2003 09:41:01 GMT</lastBuildDate>
+<item><title>a</title><pubDate>Tue, 20 May 2003 08:56:02 GMT</pubDate></item>
+<item><title>b</title><pubDate>Tue, 03 Jun 2003 09:39:21 GMT</pubDate></item>
+</channel></rss>`
+	dst := t.TempDir()
+	start := time.Now().Add(-time.Second)
+	o := Options{Source: "-", Destination: dst, Stdin: strings.NewReader(doc)}
+	if err := Run(context.Background(), o); err != nil {
+		t.Fatal(err)
+	}
+	r := readFeed(t, filepath.Join(dst, DefaultFilename))
+	if want := rss.PubDate("Tue, 03 Jun 2003 09:39:21 GMT"); r.Channel.PubDate != want {
+		t.Errorf("Channel.PubDate = %q, want %q", r.Channel.PubDate, want)
+	}
+	built, err := r.Channel.LastBuildDate.Time()
+	if err != nil || built.Before(start) || built.After(time.Now()) {
+		t.Errorf("Channel.LastBuildDate = %q, want the time of the mirror", r.Channel.LastBuildDate)
+	}
+
+	// The last build date is kept if the feed has not changed.
+	o.Stdin = strings.NewReader(doc)
+	if err := Run(context.Background(), o); err != nil {
+		t.Fatal(err)
+	}
+	if again := readFeed(t, filepath.Join(dst, DefaultFilename)); again.Channel.LastBuildDate != r.Channel.LastBuildDate {
+		t.Errorf("Channel.LastBuildDate = %q, want %q", again.Channel.LastBuildDate, r.Channel.LastBuildDate)
+	}
+
+	dst = t.TempDir()
+	o = Options{Source: "-", Destination: dst, Stdin: strings.NewReader(doc), PreserveDates: true}
+	if err := Run(context.Background(), o); err != nil {
+		t.Fatal(err)
+	}
+	r = readFeed(t, filepath.Join(dst, DefaultFilename))
+	if r.Channel.PubDate != "" || r.Channel.LastBuildDate != "Tue, 10 Jun 2003 09:41:01 GMT" {
+		t.Errorf("PreserveDates: Channel dates = %q, %q, want them unchanged", r.Channel.PubDate, r.Channel.LastBuildDate)
+	}
+}
+
+func TestRunCleanLinks(t *testing.T) {
+	const doc = `<rss version="2.0"><channel><title>Site</title><link>https://site.com/?utm_source=rss</link>
+<item><title>a</title><link>https://site.com/article?id=1&amp;utm_medium=feed&amp;fbclid=x</link></item>
+</channel></rss>`
+	dst := t.TempDir()
+	o := Options{Source: "-", Destination: dst, Stdin: strings.NewReader(doc), CleanLinks: true}
+	if err := Run(context.Background(), o); err != nil {
+		t.Fatal(err)
+	}
+	r := readFeed(t, filepath.Join(dst, DefaultFilename))
+	if r.Channel.Link != "https://site.com/" {
+		t.Errorf("Channel.Link = %q, want %q", r.Channel.Link, "https://site.com/")
+	}
+	if got := r.Channel.Item[0].Link; got != "https://site.com/article?id=1" {
+		t.Errorf("Item[0].Link = %q, want %q", got, "https://site.com/article?id=1")
+	}
+}
+
+func TestRunGenerator(t *testing.T) {
+	tests := []struct {
+		preserve bool
+		want     rss.Generator
+	}{
+		{false, rss.DefaultGenerator()},
+		{true, "Weblog Editor 2.0"},
+	}
+	for _, tt := range tests {
+		dst := t.TempDir()
+		if err := Run(context.Background(), Options{Source: testFeed, Destination: dst, PreserveGenerator: tt.preserve}); err != nil {
+			t.Fatal(err)
+		}
+		r := readFeed(t, filepath.Join(dst, DefaultFilename))
+		if r.Channel.Generator != tt.want {
+			t.Errorf("PreserveGenerator = %v: Generator = %q, want %q", tt.preserve, r.Channel.Generator, tt.want)
+		}
+	}
+}
+
+func TestRunMerge(t *testing.T) {
+	const extra = `<rss version="2.0"><channel><title>Other</title>
+<item><title>Star City</title><link>http://liftoff.msfc.nasa.gov/news/2003/news-starcity.asp</link></item>
+<item><title>Launch</title><link>http://example.com/launch</link></item>
+</channel></rss>`
+	tests := []struct {
+		dedup rss.DedupStrategy
+		want  int
+	}{
+		{"", 6},
+		{rss.DedupLink, 5},
+	}
+	for _, tt := range tests {
+		dst := t.TempDir()
+		o := Options{Source: testFeed, Merge: []string{"-"}, Dedup: tt.dedup, Destination: dst, Stdin: strings.NewReader(extra)}
+		if err := Run(context.Background(), o); err != nil {
+			t.Fatal(err)
+		}
+		r := readFeed(t, filepath.Join(dst, DefaultFilename))
+		if got := len(r.Channel.Item); got != tt.want {
+			t.Errorf("Dedup = %q: len(Channel.Item) = %d, want %d", tt.dedup, got, tt.want)
+		}
+		if r.Channel.Title != "Liftoff News" {
+			t.Errorf("Channel.Title = %q, want %q", r.Channel.Title, "Liftoff News")
+		}
+	}
+}
+
+func TestRunRequireValid(t *testing.T) {
+	const invalidFeed = "../../test/data/rss-invalid.xml"
+	dst := t.TempDir()
+	if err := Run(context.Background(), Options{Source: invalidFeed, Destination: dst}); err != nil {
+		t.Fatalf("Run() without RequireValid = %v", err)
+	}
+
+	dst = filepath.Join(t.TempDir(), "out")
+	err := Run(context.Background(), Options{Source: invalidFeed, Destination: dst, RequireValid: true})
+	for _, want := range []error{rss.ErrMissingLink, rss.ErrMissingDescription, rss.ErrInvalidDate, rss.ErrMissingTitleOrDescription} {
+		if !errors.Is(err, want) {
+			t.Errorf("Run() = %v, want %v", err, want)
+		}
+	}
+	if _, err := os.Stat(dst); !os.IsNotExist(err) {
+		t.Errorf("destination exists after an invalid feed: %v", err)
+	}
+
+	if err := Run(context.Background(), Options{Source: testFeed, Destination: dst, RequireValid: true}); err != nil {
+		t.Errorf("Run() of a valid feed = %v", err)
+	}
+}
+
+func TestRunStripElements(t *testing.T) {
+	dst := t.TempDir()
+	if err := Run(context.Background(), Options{Source: testFeed, Destination: dst, StripElements: []string{"managingEditor", "guid"}}); err != nil {
+		t.Fatal(err)
+	}
+	r := readFeed(t, filepath.Join(dst, DefaultFilename))
+	if r.Channel.ManagingEditor != "" {
+		t.Errorf("ManagingEditor = %q, want it stripped", r.Channel.ManagingEditor)
+	}
+	for i, item := range r.Channel.Item {
+		if item.GUID != nil {
+			t.Errorf("Item[%d].GUID = %+v, want it stripped", i, item.GUID)
+		}
+	}
+
+	dst = filepath.Join(t.TempDir(), "out")
+	if err := Run(context.Background(), Options{Source: testFeed, Destination: dst, StripElements: []string{"title"}}); !errors.Is(err, rss.ErrRequiredElement) {
+		t.Errorf("Run() = %v, want ErrRequiredElement", err)
+	}
+	if _, err := os.Stat(dst); !os.IsNotExist(err) {
+		t.Errorf("destination exists after a failed mirror: %v", err)
+	}
+}
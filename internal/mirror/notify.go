@@ -0,0 +1,57 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package mirror
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/NickolasHKraus/archor/pkg/rss"
+)
+
+// Notification is the JSON payload posted to Options.NotifyURL after a
+// successful mirror that changed the feed (see rss.RSS.Equal).
+type Notification struct {
+	// Title is the title of the channel.
+	Title string `json:"title"`
+	// ItemCount is the number of items in the mirrored feed.
+	ItemCount int `json:"item_count"`
+	// NewItems are the keys (see rss.Item.Key) of the items that were not
+	// in the previously mirrored feed.
+	NewItems []string `json:"new_items"`
+}
+
+// notify posts a Notification describing the mirror of r, whose previously
+// mirrored version was old, to o.NotifyURL.
+func (o Options) notify(ctx context.Context, old, r *rss.RSS) error {
+	n := Notification{
+		Title:     string(r.Channel.Title),
+		ItemCount: len(r.Channel.Item),
+		NewItems:  []string{},
+	}
+	for _, item := range rss.Diff(old, r).Added {
+		n.NewItems = append(n.NewItems, item.Key())
+	}
+	b, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.NotifyURL, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := o.client().Do(req)
+	if err != nil {
+		return fmt.Errorf("notify: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("notify: POST %s: unexpected status %s", o.NotifyURL, resp.Status)
+	}
+	return nil
+}
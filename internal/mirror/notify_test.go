@@ -0,0 +1,83 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package mirror
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRunNotify(t *testing.T) {
+	var got []Notification
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("got %s request with Content-Type %q", r.Method, r.Header.Get("Content-Type"))
+		}
+		var n Notification
+		if err := json.NewDecoder(r.Body).Decode(&n); err != nil {
+			t.Error(err)
+		}
+		got = append(got, n)
+	}))
+	defer ts.Close()
+
+	dst := t.TempDir()
+	first := `<rss version="2.0"><channel><title>Liftoff News</title>
+<item><title>a</title><guid>1</guid></item>
+</channel></rss>`
+	second := `<rss version="2.0"><channel><title>Liftoff News</title>
+<item><title>b</title><guid>2</guid></item>
+<item><title>a</title><guid>1</guid></item>
+</channel></rss>`
+	for _, doc := range []string{first, second} {
+		o := Options{Source: "-", Destination: dst, Stdin: strings.NewReader(doc), NotifyURL: ts.URL}
+		if err := Run(context.Background(), o); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(got) != 2 {
+		t.Fatalf("received %d notifications, want 2", len(got))
+	}
+	want := []Notification{
+		{Title: "Liftoff News", ItemCount: 1, NewItems: []string{"1"}},
+		{Title: "Liftoff News", ItemCount: 2, NewItems: []string{"2"}},
+	}
+	for i := range want {
+		if got[i].Title != want[i].Title || got[i].ItemCount != want[i].ItemCount || strings.Join(got[i].NewItems, ",") != strings.Join(want[i].NewItems, ",") {
+			t.Errorf("notification %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestRunNotifyUnchanged(t *testing.T) {
+	var got int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got++
+	}))
+	defer ts.Close()
+
+	dst := t.TempDir()
+	for i := 0; i < 2; i++ {
+		if err := Run(context.Background(), Options{Source: testFeed, Destination: dst, NotifyURL: ts.URL}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got != 1 {
+		t.Errorf("received %d notifications, want 1", got)
+	}
+}
+
+func TestRunNotifyError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer ts.Close()
+	if err := Run(context.Background(), Options{Source: testFeed, Destination: t.TempDir(), NotifyURL: ts.URL}); err == nil {
+		t.Fatal("expected error for failed notification")
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package mirror
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// limiter is a token bucket limiting the aggregate rate at which the readers
+// sharing it are read.
+type limiter struct {
+	mu sync.Mutex
+	// rate is the number of tokens (bytes) added per second.
+	rate float64
+	// burst is the capacity of the bucket.
+	burst float64
+	// tokens is the number of tokens in the bucket. It is negative if reads
+	// are waiting for tokens.
+	tokens float64
+	last   time.Time
+}
+
+// newLimiter returns a limiter allowing bytesPerSec bytes per second, or nil
+// if bytesPerSec is not positive. Bursts are limited to a tenth of a second
+// of data.
+func newLimiter(bytesPerSec int64) *limiter {
+	if bytesPerSec <= 0 {
+		return nil
+	}
+	burst := max(float64(bytesPerSec)/10, 1)
+	return &limiter{rate: float64(bytesPerSec), burst: burst, tokens: burst, last: time.Now()}
+}
+
+// wait takes n tokens from the bucket, blocking until they are available or
+// ctx is done.
+func (l *limiter) wait(ctx context.Context, n int) error {
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens = min(l.tokens+now.Sub(l.last).Seconds()*l.rate, l.burst)
+	l.last = now
+	l.tokens -= float64(n)
+	deficit := -l.tokens
+	l.mu.Unlock()
+	if deficit <= 0 {
+		return nil
+	}
+	t := time.NewTimer(time.Duration(deficit / l.rate * float64(time.Second)))
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// reader returns a reader of r limited by l, or r if l is nil.
+func (l *limiter) reader(ctx context.Context, r io.Reader) io.Reader {
+	if l == nil {
+		return r
+	}
+	return &limitedReader{ctx: ctx, r: r, l: l}
+}
+
+type limitedReader struct {
+	ctx context.Context
+	r   io.Reader
+	l   *limiter
+}
+
+func (lr *limitedReader) Read(p []byte) (int, error) {
+	if len(p) > int(lr.l.burst) {
+		p = p[:int(lr.l.burst)]
+	}
+	n, err := lr.r.Read(p)
+	if n > 0 {
+		if werr := lr.l.wait(lr.ctx, n); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,78 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package mirror
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRunRateLimit(t *testing.T) {
+	const size = 20000
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/feed.xml" {
+			fmt.Fprintf(w, `<rss version="2.0"><channel><title>Podcast</title>
+<item><title>1</title><enclosure url="%[1]s/1.mp3" length="%[2]d" type="audio/mpeg"/></item>
+<item><title>2</title><enclosure url="%[1]s/2.mp3" length="%[2]d" type="audio/mpeg"/></item>
+</channel></rss>`, ts.URL, size)
+			return
+		}
+		w.Header().Set("Content-Type", "audio/mpeg")
+		w.Write(bytes.Repeat([]byte{'x'}, size))
+	}))
+	defer ts.Close()
+
+	// 40000 bytes at 100000 bytes per second, less the initial burst of
+	// 10000 bytes, take at least 0.3s.
+	start := time.Now()
+	o := Options{Source: ts.URL + "/feed.xml", Destination: t.TempDir(), Enclosures: true, RateLimit: 100000}
+	if err := Run(context.Background(), o); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 280*time.Millisecond || elapsed > 3*time.Second {
+		t.Errorf("download took %v, want about 0.3s", elapsed)
+	}
+}
+
+func TestLimiterAggregate(t *testing.T) {
+	l := newLimiter(100000)
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n, err := io.Copy(io.Discard, l.reader(context.Background(), bytes.NewReader(make([]byte, 10000))))
+			if n != 10000 || err != nil {
+				t.Errorf("Copy() = %d, %v", n, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if elapsed := time.Since(start); elapsed < 280*time.Millisecond || elapsed > 3*time.Second {
+		t.Errorf("reads took %v, want about 0.3s", elapsed)
+	}
+}
+
+func TestLimiterCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	l := newLimiter(10)
+	_, err := io.Copy(io.Discard, l.reader(ctx, bytes.NewReader(make([]byte, 100))))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Copy() = %v, want context.Canceled", err)
+	}
+	if newLimiter(0).reader(ctx, nil) != nil {
+		t.Error("reader of a nil limiter must be the underlying reader")
+	}
+}
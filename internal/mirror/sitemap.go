@@ -0,0 +1,67 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package mirror
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/NickolasHKraus/archor/pkg/rss"
+)
+
+// SitemapFilename is the name of the sitemap written to the destination (see
+// Options.Sitemap).
+const SitemapFilename = "sitemap.xml"
+
+// SitemapNamespace is the namespace of the sitemap protocol.
+//
+// See: https://www.sitemaps.org/protocol.html
+const SitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// urlset is the root element of a sitemap.
+type urlset struct {
+	XMLName xml.Name     `xml:"urlset"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	URL     []sitemapURL `xml:"url"`
+}
+
+// sitemapURL is a <url> element of a sitemap.
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	Lastmod string `xml:"lastmod,omitempty"`
+}
+
+// sitemap returns the sitemap of the items of r: the link of each item, in
+// order, with the publication date of the item, if it is valid, as the date
+// of last modification. Items without a link are omitted, as are links that
+// already appeared.
+func sitemap(r *rss.RSS) urlset {
+	s := urlset{Xmlns: SitemapNamespace}
+	seen := make(map[rss.Link]bool)
+	for _, item := range r.Channel.Item {
+		if item.Link == "" || seen[item.Link] {
+			continue
+		}
+		seen[item.Link] = true
+		u := sitemapURL{Loc: string(item.Link)}
+		if t, err := item.PubDate.Time(); err == nil {
+			u.Lastmod = t.Format(time.RFC3339)
+		}
+		s.URL = append(s.URL, u)
+	}
+	return s
+}
+
+// writeSitemap writes the sitemap of the items of r to SitemapFilename in the
+// destination.
+func (o Options) writeSitemap(r *rss.RSS) error {
+	b, err := xml.MarshalIndent(sitemap(r), "", "  ")
+	if err != nil {
+		return err
+	}
+	b = append([]byte(xml.Header), b...)
+	return os.WriteFile(filepath.Join(o.Destination, SitemapFilename), append(b, '\n'), 0o644)
+}
@@ -0,0 +1,59 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package mirror
+
+import (
+	"context"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRunSitemap(t *testing.T) {
+	const doc = `<rss version="2.0"><channel>
+<title>Site</title><link>http://example.com/</link><description>Example</description>
+<item><title>a</title><link>http://example.com/a</link><pubDate>Mon, 29 May 2023 10:00:00 +0000</pubDate></item>
+<item><title>b</title><link>http://example.com/b</link><pubDate>Fri, 30 Jun 2023 23:30:00 -0200</pubDate></item>
+<item><title>c</title><link>http://example.com/c</link><pubDate>yesterday</pubDate></item>
+<item><title>d</title><guid>4</guid></item>
+<item><title>a again</title><link>http://example.com/a</link></item>
+</channel></rss>`
+	dst := t.TempDir()
+	o := Options{Source: "-", Destination: dst, Stdin: strings.NewReader(doc), Sitemap: true}
+	if err := Run(context.Background(), o); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(filepath.Join(dst, SitemapFilename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got urlset
+	if err := xml.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.XMLName.Space != SitemapNamespace {
+		t.Errorf("namespace = %q, want %q", got.XMLName.Space, SitemapNamespace)
+	}
+	want := []sitemapURL{
+		{Loc: "http://example.com/a", Lastmod: "2023-05-29T10:00:00Z"},
+		{Loc: "http://example.com/b", Lastmod: "2023-06-30T23:30:00-02:00"},
+		{Loc: "http://example.com/c"},
+	}
+	if !reflect.DeepEqual(got.URL, want) {
+		t.Errorf("URL = %+v, want %+v", got.URL, want)
+	}
+}
+
+func TestRunNoSitemap(t *testing.T) {
+	dst := t.TempDir()
+	if err := Run(context.Background(), Options{Source: testFeed, Destination: dst}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, SitemapFilename)); !os.IsNotExist(err) {
+		t.Errorf("Stat(%s) error = %v, want not exist", SitemapFilename, err)
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package mirror
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/NickolasHKraus/archor/pkg/rss"
+)
+
+// ItemsDir is the directory, relative to the destination, to which the items
+// of the feed are written if Options.SplitItems is set.
+const ItemsDir = "items"
+
+// writeItems writes each item of r to its own file in the items directory of
+// o.Destination. Each file is an RSS document containing the channel of r with
+// only that item, named after the sanitized key (see rss.Item.Key) of the item.
+func (o Options) writeItems(r *rss.RSS) error {
+	dir := filepath.Join(o.Destination, ItemsDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	used := make(map[string]bool)
+	for n, item := range r.Channel.Item {
+		c := *r.Channel
+		c.Item = []*rss.Item{item}
+		doc := *r
+		doc.Channel = &c
+		b, err := rss.MarshalOptions{PreserveOrder: true}.Marshal(&doc)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, itemFilename(item, n, used)), b, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// itemFilename returns a unique name for the file of the nth item, recording
+// it in used.
+func itemFilename(item *rss.Item, n int, used map[string]bool) string {
+	stem := sanitizeFilename(item.Key())
+	if stem == "" || stem == "." || stem == ".." {
+		stem = fmt.Sprintf("item-%d", n)
+	}
+	name := stem + ".xml"
+	for i := 1; used[name]; i++ {
+		name = fmt.Sprintf("%s-%d.xml", stem, i)
+	}
+	used[name] = true
+	return name
+}
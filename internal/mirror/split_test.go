@@ -0,0 +1,52 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package mirror
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NickolasHKraus/archor/pkg/rss"
+)
+
+func TestRunSplitItems(t *testing.T) {
+	dst := t.TempDir()
+	if err := Run(context.Background(), Options{Source: testFeed, Destination: dst, SplitItems: true}); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := os.ReadDir(filepath.Join(dst, ItemsDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("len(items) = %d, want 4", len(entries))
+	}
+	r := readFeed(t, filepath.Join(dst, ItemsDir, "http-liftoff.msfc.nasa.gov-2003-06-03.html#item573.xml"))
+	if len(r.Channel.Item) != 1 || r.Channel.Item[0].Title != "Star City" {
+		t.Errorf("Channel.Item = %+v, want the Star City item", r.Channel.Item)
+	}
+	if r.Channel.Title != "Liftoff News" {
+		t.Errorf("Channel.Title = %q, want %q", r.Channel.Title, "Liftoff News")
+	}
+}
+
+func TestItemFilename(t *testing.T) {
+	used := make(map[string]bool)
+	tests := []struct {
+		item *rss.Item
+		want string
+	}{
+		{&rss.Item{GUID: &rss.GUID{Value: "urn:uuid:1234"}}, "urn-uuid-1234.xml"},
+		{&rss.Item{Link: "https://example.com/a/b"}, "https-example.com-a-b.xml"},
+		{&rss.Item{Link: "https://example.com/a/b"}, "https-example.com-a-b-1.xml"},
+		{&rss.Item{Description: "untitled"}, "item-3.xml"},
+	}
+	for n, test := range tests {
+		if got := itemFilename(test.item, n, used); got != test.want {
+			t.Errorf("itemFilename(%d) = %q, want %q", n, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package mirror
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/NickolasHKraus/archor/pkg/rss"
+)
+
+// Transform rewrites an item of a mirrored feed. It returns the rewritten
+// item, or nil to remove the item (see rss.Channel.TransformItems).
+type Transform func(*rss.Item) *rss.Item
+
+// ParseTransform returns the Transform described by rule, which has the form
+// "<kind>=<text>". The kinds are:
+//
+//	title-prefix        prepends text to the title of each item
+//	title-suffix        appends text to the title of each item
+//	description-prefix  prepends text to the description of each item
+func ParseTransform(rule string) (Transform, error) {
+	kind, text, ok := strings.Cut(rule, "=")
+	if !ok {
+		return nil, fmt.Errorf("invalid transform %q: want <kind>=<text>", rule)
+	}
+	switch kind {
+	case "title-prefix":
+		return func(item *rss.Item) *rss.Item {
+			item.Title = rss.Title(text) + item.Title
+			return item
+		}, nil
+	case "title-suffix":
+		return func(item *rss.Item) *rss.Item {
+			item.Title += rss.Title(text)
+			return item
+		}, nil
+	case "description-prefix":
+		return func(item *rss.Item) *rss.Item {
+			item.Description = rss.Description(text) + item.Description
+			return item
+		}, nil
+	}
+	return nil, fmt.Errorf("invalid transform %q: unknown kind %q", rule, kind)
+}
@@ -0,0 +1,55 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package mirror
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/NickolasHKraus/archor/pkg/rss"
+)
+
+func TestParseTransform(t *testing.T) {
+	tests := []struct {
+		rule    string
+		want    rss.Item
+		wantErr bool
+	}{
+		{rule: "title-prefix=[NASA] ", want: rss.Item{Title: "[NASA] Star City", Description: "How do Americans"}},
+		{rule: "title-suffix= (archived)", want: rss.Item{Title: "Star City (archived)", Description: "How do Americans"}},
+		{rule: "description-prefix=Mirrored: ", want: rss.Item{Title: "Star City", Description: "Mirrored: How do Americans"}},
+		{rule: "title-prefix", wantErr: true},
+		{rule: "title-upper=x", wantErr: true},
+	}
+	for _, tt := range tests {
+		fn, err := ParseTransform(tt.rule)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseTransform(%q) error = %v, wantErr %v", tt.rule, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		got := fn(&rss.Item{Title: "Star City", Description: "How do Americans"})
+		if got.Title != tt.want.Title || got.Description != tt.want.Description {
+			t.Errorf("ParseTransform(%q) = %q, %q; want %q, %q", tt.rule, got.Title, got.Description, tt.want.Title, tt.want.Description)
+		}
+	}
+}
+
+func TestRunTransforms(t *testing.T) {
+	prefix, err := ParseTransform("title-prefix=[NASA] ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst := t.TempDir()
+	if err := Run(context.Background(), Options{Source: testFeed, Destination: dst, Transforms: []Transform{prefix}}); err != nil {
+		t.Fatal(err)
+	}
+	r := readFeed(t, filepath.Join(dst, DefaultFilename))
+	if got := r.Channel.Item[0].Title; got != "[NASA] Star City" {
+		t.Errorf("Item[0].Title = %q, want %q", got, "[NASA] Star City")
+	}
+}
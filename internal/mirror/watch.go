@@ -0,0 +1,38 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package mirror
+
+import (
+	"context"
+	"time"
+)
+
+// Watch mirrors the feed at o.Source to o.Destination every interval until
+// ctx is done. If the channel has a ttl longer than interval, Watch waits for
+// the ttl instead, and refreshes that fall in the hours and days listed in
+// the skipHours and skipDays of the channel are deferred to the next allowed
+// hour. Failed mirrors are logged and do not stop Watch.
+func Watch(ctx context.Context, o Options, interval time.Duration) error {
+	for {
+		r, err := o.run(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			o.logf("error: %v\n", err)
+		}
+		next := time.Now().Add(interval)
+		if r != nil {
+			if ttl, err := r.Channel.TTL.Duration(); err == nil && ttl > interval {
+				next = time.Now().Add(ttl)
+			}
+			next = r.Channel.NextRefreshAt(next)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Until(next)):
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package mirror
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newCountingServer returns a server serving doc and the number of requests
+// it has received.
+func newCountingServer(t *testing.T, doc string) (*httptest.Server, *int32) {
+	t.Helper()
+	var n int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&n, 1)
+		fmt.Fprint(w, doc)
+	}))
+	t.Cleanup(ts.Close)
+	return ts, &n
+}
+
+func TestWatch(t *testing.T) {
+	ts, n := newCountingServer(t, `<rss version="2.0"><channel><title>Site</title></channel></rss>`)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		for atomic.LoadInt32(n) < 3 {
+			time.Sleep(time.Millisecond)
+		}
+		cancel()
+	}()
+	if err := Watch(ctx, Options{Source: ts.URL, Destination: t.TempDir()}, time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if got := atomic.LoadInt32(n); got < 3 {
+		t.Errorf("fetched the feed %d times, want at least 3", got)
+	}
+}
+
+func TestWatchTTL(t *testing.T) {
+	ts, n := newCountingServer(t, `<rss version="2.0"><channel><title>Site</title><ttl>60</ttl></channel></rss>`)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := Watch(ctx, Options{Source: ts.URL, Destination: t.TempDir()}, time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if got := atomic.LoadInt32(n); got != 1 {
+		t.Errorf("fetched the feed %d times, want 1 within the ttl", got)
+	}
+}
+
+func TestWatchSkipHours(t *testing.T) {
+	// Skip the current hour and the next, so that the next refresh is at
+	// least an hour away.
+	now := time.Now().UTC()
+	doc := fmt.Sprintf(`<rss version="2.0"><channel><title>Site</title><skipHours><hour>%d</hour><hour>%d</hour></skipHours></channel></rss>`,
+		now.Hour(), now.Add(time.Hour).Hour())
+	ts, n := newCountingServer(t, doc)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := Watch(ctx, Options{Source: ts.URL, Destination: t.TempDir()}, time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if got := atomic.LoadInt32(n); got != 1 {
+		t.Errorf("fetched the feed %d times, want 1 during skipped hours", got)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package report reports the result of validating a feed.
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/NickolasHKraus/archor/pkg/rss"
+)
+
+// Format is a format in which a report can be written.
+type Format string
+
+const (
+	// Text is one line per error, followed by a summary.
+	Text Format = "text"
+	// JSON is a JSON object (see Report).
+	JSON Format = "json"
+)
+
+// Report is the result of validating a feed.
+type Report struct {
+	// Valid reports whether the feed is valid.
+	Valid bool `json:"valid"`
+	// Errors describes each invalid element of the feed. It is empty, but
+	// not nil, if the feed is valid.
+	Errors []Error `json:"errors"`
+}
+
+// Error describes an invalid element of a feed.
+type Error struct {
+	// Path is the path of the element, as in rss.ValidationError.
+	Path string `json:"path"`
+	// Message describes why the element is invalid.
+	Message string `json:"message"`
+}
+
+// New validates r and returns the report of the result.
+func New(r *rss.RSS) Report {
+	rep := Report{Valid: true, Errors: []Error{}}
+	var errs rss.ValidationErrors
+	if err := r.Validate(); !errors.As(err, &errs) {
+		return rep
+	}
+	rep.Valid = false
+	for _, e := range errs {
+		rep.Errors = append(rep.Errors, Error{Path: e.Path, Message: e.Err.Error()})
+	}
+	return rep
+}
+
+// Write writes the report to w in the format f.
+func (rep Report) Write(w io.Writer, f Format) error {
+	switch f {
+	case Text:
+		return rep.writeText(w)
+	case JSON:
+		b, err := json.MarshalIndent(rep, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(append(b, '\n'))
+		return err
+	}
+	return fmt.Errorf("unsupported format %q", f)
+}
+
+func (rep Report) writeText(w io.Writer) error {
+	for _, e := range rep.Errors {
+		if _, err := fmt.Fprintf(w, "%s: %s\n", e.Path, e.Message); err != nil {
+			return err
+		}
+	}
+	if rep.Valid {
+		_, err := fmt.Fprintln(w, "valid")
+		return err
+	}
+	_, err := fmt.Fprintf(w, "invalid: %d errors\n", len(rep.Errors))
+	return err
+}
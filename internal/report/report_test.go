@@ -0,0 +1,111 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/NickolasHKraus/archor/pkg/rss"
+)
+
+func readFeed(t *testing.T, name string) *rss.RSS {
+	t.Helper()
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	r, err := rss.ParseAny(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func keys(m map[string]interface{}) []string {
+	var k []string
+	for key := range m {
+		k = append(k, key)
+	}
+	sort.Strings(k)
+	return k
+}
+
+func TestWriteJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		valid  bool
+		errors int
+	}{
+		{"../../test/data/rss-0.xml", true, 0},
+		{"../../test/data/rss-invalid.xml", false, 4},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := New(readFeed(t, tt.name)).Write(&buf, JSON); err != nil {
+			t.Fatal(err)
+		}
+		var rep map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &rep); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := keys(rep); len(got) != 2 || got[0] != "errors" || got[1] != "valid" {
+			t.Errorf("%s: report keys = %v, want [errors valid]", tt.name, got)
+		}
+		if valid, ok := rep["valid"].(bool); !ok || valid != tt.valid {
+			t.Errorf("%s: valid = %v, want %v", tt.name, rep["valid"], tt.valid)
+		}
+		errs, ok := rep["errors"].([]interface{})
+		if !ok || len(errs) != tt.errors {
+			t.Fatalf("%s: errors = %v, want an array of %d errors", tt.name, rep["errors"], tt.errors)
+		}
+		for _, e := range errs {
+			obj, ok := e.(map[string]interface{})
+			if !ok {
+				t.Errorf("%s: error = %v, want an object", tt.name, e)
+				continue
+			}
+			if got := keys(obj); len(got) != 2 || got[0] != "message" || got[1] != "path" {
+				t.Errorf("%s: error keys = %v, want [message path]", tt.name, got)
+			}
+			for _, k := range []string{"path", "message"} {
+				if s, ok := obj[k].(string); !ok || s == "" {
+					t.Errorf("%s: error %s = %v, want a non-empty string", tt.name, k, obj[k])
+				}
+			}
+		}
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	tests := []struct {
+		rep  Report
+		want string
+	}{
+		{Report{Valid: true, Errors: []Error{}}, "valid\n"},
+		{
+			Report{Errors: []Error{{"link", "missing required link"}, {"item[0].guid", `isPermaLink is not "true" or "false"`}}},
+			"link: missing required link\nitem[0].guid: isPermaLink is not \"true\" or \"false\"\ninvalid: 2 errors\n",
+		},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := tt.rep.Write(&buf, Text); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != tt.want {
+			t.Errorf("Write(%+v) = %q, want %q", tt.rep, buf.String(), tt.want)
+		}
+	}
+}
+
+func TestWriteUnsupportedFormat(t *testing.T) {
+	if err := (Report{}).Write(&bytes.Buffer{}, "yaml"); err == nil {
+		t.Error("Write(yaml): expected error")
+	}
+}
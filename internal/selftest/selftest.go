@@ -0,0 +1,93 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package selftest implements an end-to-end check of the feed handling of
+// archor, for diagnosing builds and installs.
+package selftest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/NickolasHKraus/archor/internal/feedtest"
+	"github.com/NickolasHKraus/archor/pkg/rss"
+)
+
+// Check is a step of the self-test.
+type Check struct {
+	// Name describes the step.
+	Name string
+	// Err is the failure of the step, or nil if it passed.
+	Err error
+}
+
+// Checks generates a feed in memory, marshals it, parses it again, validates
+// it and converts it to Atom and JSON Feed, and returns the result of each
+// step. Steps following a failed step are not run.
+func Checks() []Check {
+	var checks []Check
+	run := func(name string, fn func() error) bool {
+		err := fn()
+		checks = append(checks, Check{Name: name, Err: err})
+		return err == nil
+	}
+	r := feedtest.GenerateFeed(10, 1)
+	var b []byte
+	var parsed *rss.RSS
+	_ = run("marshal", func() (err error) {
+		b, err = rss.Marshal(r)
+		return err
+	}) && run("parse", func() (err error) {
+		parsed, err = rss.Parse(bytes.NewReader(b))
+		return err
+	}) && run("validate", func() error {
+		return parsed.Validate()
+	}) && run("round trip", func() error {
+		if !parsed.Equal(r) {
+			return errors.New("parsed feed differs from the generated feed")
+		}
+		return nil
+	}) && run("convert to Atom", func() error {
+		atom, err := parsed.ToAtom()
+		if err != nil {
+			return err
+		}
+		back, err := rss.ParseAny(bytes.NewReader(atom))
+		if err != nil {
+			return err
+		}
+		if n := len(back.Channel.Item); n != len(r.Channel.Item) {
+			return fmt.Errorf("Atom feed has %d entries, want %d", n, len(r.Channel.Item))
+		}
+		return nil
+	}) && run("convert to JSON Feed", func() error {
+		b, err := parsed.ToJSONFeed()
+		if err != nil {
+			return err
+		}
+		if !json.Valid(b) {
+			return errors.New("invalid JSON")
+		}
+		return nil
+	})
+	return checks
+}
+
+// Run runs the checks of the self-test, writing a line reporting the result
+// of each to w. It returns an error if a check failed.
+func Run(w io.Writer) error {
+	var failed error
+	for _, c := range Checks() {
+		if c.Err != nil {
+			fmt.Fprintf(w, "FAIL %s: %v\n", c.Name, c.Err)
+			failed = fmt.Errorf("self-test failed: %s: %w", c.Name, c.Err)
+			continue
+		}
+		fmt.Fprintf(w, "ok   %s\n", c.Name)
+	}
+	return failed
+}
@@ -0,0 +1,119 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package source opens the feed sources accepted by archor commands.
+package source
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Open returns a reader for the feed at source, dispatching on its scheme.
+// The source is an http(s) URL, which is fetched using c, a file:// URI, a
+// local path, or "-", in which case stdin is read. Requests are canceled
+// when ctx is done. A feed compressed with gzip, as detected by a .gz
+// extension or its magic number, is decompressed.
+func Open(ctx context.Context, c *http.Client, source string, stdin io.Reader) (io.ReadCloser, error) {
+	rc, err := open(ctx, c, source, stdin)
+	if err != nil {
+		return nil, err
+	}
+	return Decompress(rc, strings.HasSuffix(source, ".gz"))
+}
+
+func open(ctx context.Context, c *http.Client, source string, stdin io.Reader) (io.ReadCloser, error) {
+	if source == "-" {
+		return io.NopCloser(stdin), nil
+	}
+	u, err := url.Parse(source)
+	if err != nil {
+		// Not a URL, so treat it as a local path.
+		return os.Open(source)
+	}
+	switch u.Scheme {
+	case "http", "https":
+		return Get(ctx, c, source)
+	case "file":
+		return os.Open(u.Path)
+	case "":
+		return os.Open(source)
+	}
+	if len(u.Scheme) == 1 {
+		// A Windows path with a drive letter (e.g. C:\feed.xml).
+		return os.Open(source)
+	}
+	return nil, fmt.Errorf("unsupported source scheme %q", u.Scheme)
+}
+
+// IsHTTP reports whether source is an http(s) URL, which Open fetches.
+func IsHTTP(source string) bool {
+	u, err := url.Parse(source)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https")
+}
+
+// gzipMagic is the magic number at the start of gzip-compressed data.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// Decompress returns a reader for the content of rc, decompressed if gz is
+// set or the content starts with the gzip magic number. Closing the returned
+// reader closes rc.
+func Decompress(rc io.ReadCloser, gz bool) (io.ReadCloser, error) {
+	br := bufio.NewReader(rc)
+	if !gz {
+		magic, _ := br.Peek(len(gzipMagic))
+		gz = bytes.Equal(magic, gzipMagic)
+	}
+	if !gz {
+		return readCloser{br, rc}, nil
+	}
+	zr, err := gzip.NewReader(br)
+	if err != nil {
+		rc.Close()
+		return nil, err
+	}
+	return readCloser{zr, rc}, nil
+}
+
+// readCloser reads from a Reader wrapping the content of a Closer.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// Get issues a GET request for url using c and returns the response body. A
+// response status other than 200 OK is an error.
+func Get(ctx context.Context, c *http.Client, url string) (io.ReadCloser, error) {
+	resp, err := GetResponse(ctx, c, url)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// GetResponse is like Get, but returns the response. The caller must close
+// the response body.
+func GetResponse(ctx context.Context, c *http.Client, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("GET %s: unexpected status %s", url, resp.Status)
+	}
+	return resp, nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package source
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testFeed = "../../test/data/rss-0.xml"
+
+func readAll(t *testing.T, rc io.ReadCloser, err error) string {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	b, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestOpen(t *testing.T) {
+	want, err := os.ReadFile(testFeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, testFeed)
+	}))
+	defer ts.Close()
+	abs, err := filepath.Abs(testFeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, src := range []string{ts.URL, testFeed, "file://" + filepath.ToSlash(abs), "-"} {
+		rc, err := Open(context.Background(), http.DefaultClient, src, strings.NewReader(string(want)))
+		if got := readAll(t, rc, err); got != string(want) {
+			t.Errorf("Open(%q) read %d bytes, want %d", src, len(got), len(want))
+		}
+	}
+}
+
+func TestOpenErrors(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+	for _, src := range []string{ts.URL, "ftp://example.com/feed.xml", "does-not-exist.xml"} {
+		if _, err := Open(context.Background(), http.DefaultClient, src, nil); err == nil {
+			t.Errorf("Open(%q): expected error", src)
+		}
+	}
+}
+
+func TestOpenGzip(t *testing.T) {
+	want, err := os.ReadFile(testFeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz, err := os.ReadFile(testFeed + ".gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(gz)
+	}))
+	defer ts.Close()
+	abs, err := filepath.Abs(testFeed + ".gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, src := range []string{ts.URL, testFeed + ".gz", "file://" + filepath.ToSlash(abs), "-"} {
+		rc, err := Open(context.Background(), http.DefaultClient, src, bytes.NewReader(gz))
+		if got := readAll(t, rc, err); got != string(want) {
+			t.Errorf("Open(%q) read %d bytes, want %d", src, len(got), len(want))
+		}
+	}
+
+	// A .gz extension is trusted even without the magic number.
+	name := filepath.Join(t.TempDir(), "feed.xml.gz")
+	if err := os.WriteFile(name, want, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(context.Background(), http.DefaultClient, name, nil); err == nil {
+		t.Errorf("Open(%q) of uncompressed file: expected error", name)
+	}
+}
+
+func TestIsHTTP(t *testing.T) {
+	tests := []struct {
+		source string
+		want   bool
+	}{
+		{"http://example.com/feed.xml", true},
+		{"https://example.com/feed.xml", true},
+		{"file:///tmp/feed.xml", false},
+		{"feed.xml", false},
+		{"-", false},
+	}
+	for _, tt := range tests {
+		if got := IsHTTP(tt.source); got != tt.want {
+			t.Errorf("IsHTTP(%q) = %v, want %v", tt.source, got, tt.want)
+		}
+	}
+}
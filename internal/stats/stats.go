@@ -0,0 +1,123 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package stats computes statistics of an RSS feed.
+package stats
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/NickolasHKraus/archor/pkg/rss"
+)
+
+// Stats are statistics of an RSS feed.
+type Stats struct {
+	// Items is the number of items.
+	Items int
+	// Dated is the number of items with a valid publication date.
+	Dated int
+	// Oldest and Newest are the earliest and latest publication dates of
+	// the items, or zero if no item is dated.
+	Oldest, Newest time.Time
+	// ItemsPerDay is the average number of dated items published per day
+	// between Oldest and Newest, counting a span of less than a day as a
+	// day.
+	ItemsPerDay float64
+	// Categories maps each category to the number of items in it.
+	Categories map[string]int
+	// Enclosures is the number of items with an enclosure.
+	Enclosures int
+	// Image reports whether the channel has an image.
+	Image bool
+	// Links is the number of distinct URLs referenced by the feed (see
+	// rss.RSS.Links).
+	Links int
+}
+
+// Compute returns the statistics of r.
+func Compute(r *rss.RSS) Stats {
+	s := Stats{Categories: make(map[string]int), Links: len(r.Links())}
+	c := r.Channel
+	if c == nil {
+		return s
+	}
+	s.Items = len(c.Item)
+	s.Image = c.Image != nil
+	if t, err := c.LatestPubDate().Time(); err == nil {
+		s.Newest = t
+	}
+	for _, item := range c.Item {
+		if t, err := item.PubDate.Time(); err == nil {
+			s.Dated++
+			if s.Oldest.IsZero() || t.Before(s.Oldest) {
+				s.Oldest = t
+			}
+		}
+		if item.Enclosure != nil {
+			s.Enclosures++
+		}
+		// Count each category of an item once.
+		seen := make(map[string]bool)
+		for _, cat := range item.Category {
+			name := strings.TrimSpace(cat.Value)
+			if name != "" && !seen[name] {
+				seen[name] = true
+				s.Categories[name]++
+			}
+		}
+	}
+	if s.Dated > 0 {
+		s.ItemsPerDay = float64(s.Dated) / max(s.Newest.Sub(s.Oldest).Hours()/24, 1)
+	}
+	return s
+}
+
+// Write writes s to w as aligned text, listing categories from the most to
+// the least common.
+func (s Stats) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "items:\t%d\n", s.Items)
+	if s.Dated > 0 {
+		fmt.Fprintf(tw, "date range:\t%s to %s\n", s.Oldest.Format(time.DateOnly), s.Newest.Format(time.DateOnly))
+		fmt.Fprintf(tw, "items per day:\t%.2f\n", s.ItemsPerDay)
+	} else {
+		fmt.Fprintf(tw, "date range:\tnone\n")
+	}
+	if undated := s.Items - s.Dated; undated > 0 {
+		fmt.Fprintf(tw, "undated items:\t%d\n", undated)
+	}
+	fmt.Fprintf(tw, "enclosures:\t%d of %d items\n", s.Enclosures, s.Items)
+	fmt.Fprintf(tw, "image:\t%s\n", yesNo(s.Image))
+	fmt.Fprintf(tw, "links:\t%d\n", s.Links)
+	if len(s.Categories) > 0 {
+		fmt.Fprintf(tw, "categories:\n")
+		names := make([]string, 0, len(s.Categories))
+		for name := range s.Categories {
+			names = append(names, name)
+		}
+		sort.Slice(names, func(i, j int) bool {
+			ni, nj := s.Categories[names[i]], s.Categories[names[j]]
+			if ni != nj {
+				return ni > nj
+			}
+			return names[i] < names[j]
+		})
+		for _, name := range names {
+			fmt.Fprintf(tw, "  %s\t%d\n", name, s.Categories[name])
+		}
+	}
+	return tw.Flush()
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
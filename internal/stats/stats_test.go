@@ -0,0 +1,80 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package stats
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/NickolasHKraus/archor/pkg/rss"
+)
+
+func readFeed(t *testing.T, name string) *rss.RSS {
+	t.Helper()
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	r, err := rss.ParseAny(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestCompute(t *testing.T) {
+	s := Compute(readFeed(t, "../../test/data/rss-0.xml"))
+	want := Stats{
+		Items:       4,
+		Dated:       4,
+		Oldest:      time.Date(2003, time.May, 20, 8, 56, 2, 0, time.UTC),
+		Newest:      time.Date(2003, time.June, 3, 9, 39, 21, 0, time.UTC),
+		ItemsPerDay: s.ItemsPerDay,
+		Categories:  map[string]int{},
+		Links:       5,
+	}
+	if !s.Oldest.Equal(want.Oldest) || !s.Newest.Equal(want.Newest) {
+		t.Errorf("date range = %v to %v, want %v to %v", s.Oldest, s.Newest, want.Oldest, want.Newest)
+	}
+	s.Oldest, s.Newest = want.Oldest, want.Newest
+	if !reflect.DeepEqual(s, want) {
+		t.Errorf("Compute() = %+v, want %+v", s, want)
+	}
+	// 4 items over about 14 days.
+	if s.ItemsPerDay < 0.28 || s.ItemsPerDay > 0.29 {
+		t.Errorf("ItemsPerDay = %v, want about 0.29", s.ItemsPerDay)
+	}
+}
+
+func TestComputeEnclosures(t *testing.T) {
+	s := Compute(readFeed(t, "../../test/data/rss-media.xml"))
+	if s.Items != 2 || s.Enclosures != 2 {
+		t.Errorf("Compute() = %d enclosures of %d items, want 2 of 2", s.Enclosures, s.Items)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Compute(readFeed(t, "../../test/data/rss-shuffled.xml")).Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := `items:          2
+date range:     2003-06-03 to 2003-06-03
+items per day:  1.00
+undated items:  1
+enclosures:     0 of 2 items
+image:          no
+links:          3
+categories:
+  Russia  1
+  Space   1
+`
+	if buf.String() != want {
+		t.Errorf("Write() =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
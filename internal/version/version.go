@@ -0,0 +1,11 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package version records the version of archor.
+package version
+
+// Version is the version of archor. It is overridden at build time using:
+//
+//	go build -ldflags "-X github.com/NickolasHKraus/archor/internal/version.Version=<version>"
+var Version = "dev"
@@ -0,0 +1,355 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/xml"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// AtomNamespace is the namespace of the Atom Syndication Format.
+//
+// See: https://www.rfc-editor.org/rfc/rfc4287
+const AtomNamespace = "http://www.w3.org/2005/Atom"
+
+// atomFeed is an Atom feed document.
+type atomFeed struct {
+	XMLName   xml.Name     `xml:"http://www.w3.org/2005/Atom feed"`
+	Lang      string       `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+	Title     atomText     `xml:"http://www.w3.org/2005/Atom title"`
+	Subtitle  atomText     `xml:"http://www.w3.org/2005/Atom subtitle"`
+	Links     []atomLink   `xml:"http://www.w3.org/2005/Atom link"`
+	Rights    atomText     `xml:"http://www.w3.org/2005/Atom rights"`
+	Generator string       `xml:"http://www.w3.org/2005/Atom generator"`
+	Logo      string       `xml:"http://www.w3.org/2005/Atom logo"`
+	Updated   string       `xml:"http://www.w3.org/2005/Atom updated"`
+	Entries   []*atomEntry `xml:"http://www.w3.org/2005/Atom entry"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"http://www.w3.org/2005/Atom id"`
+	Title      atomText       `xml:"http://www.w3.org/2005/Atom title"`
+	Links      []atomLink     `xml:"http://www.w3.org/2005/Atom link"`
+	Summary    atomText       `xml:"http://www.w3.org/2005/Atom summary"`
+	Content    atomText       `xml:"http://www.w3.org/2005/Atom content"`
+	Authors    []atomPerson   `xml:"http://www.w3.org/2005/Atom author"`
+	Categories []atomCategory `xml:"http://www.w3.org/2005/Atom category"`
+	Published  string         `xml:"http://www.w3.org/2005/Atom published"`
+	Updated    string         `xml:"http://www.w3.org/2005/Atom updated"`
+}
+
+// atomText is an Atom text construct: plain text, escaped HTML or XHTML.
+type atomText struct {
+	Type     string `xml:"type,attr"`
+	Text     string `xml:",chardata"`
+	InnerXML string `xml:",innerxml"`
+}
+
+// String returns the content of the text construct, as HTML if its type is
+// "html" or "xhtml".
+func (t atomText) String() string {
+	if t.Type == "xhtml" {
+		return strings.TrimSpace(t.InnerXML)
+	}
+	return strings.TrimSpace(t.Text)
+}
+
+type atomLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr,omitempty"`
+	Type   string `xml:"type,attr,omitempty"`
+	Length string `xml:"length,attr,omitempty"`
+}
+
+type atomPerson struct {
+	Name  string `xml:"http://www.w3.org/2005/Atom name"`
+	Email string `xml:"http://www.w3.org/2005/Atom email"`
+}
+
+type atomCategory struct {
+	Term   string `xml:"term,attr"`
+	Scheme string `xml:"scheme,attr,omitempty"`
+}
+
+// ParseAtom reads an Atom feed document from r and converts it to RSS 2.0.
+// The id of each entry becomes its guid, its summary (or content, if it has
+// no summary) its description, its content its content:encoded, and an
+// enclosure link its enclosure.
+func ParseAtom(r io.Reader) (*RSS, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return parseAtom(b, 0)
+}
+
+// parseAtom decodes the Atom feed document in b, with at most maxItems
+// entries if it is positive.
+func parseAtom(b []byte, maxItems int) (*RSS, error) {
+	var f atomFeed
+	if err := decode(b, &f, itemLimit{name: "entry", depth: 2, max: maxItems}); err != nil {
+		return nil, err
+	}
+	c := &Channel{
+		XMLLang:       f.Lang,
+		Title:         Title(f.Title.String()),
+		Link:          Link(atomLinkHref(f.Links, "alternate")),
+		Description:   Description(f.Subtitle.String()),
+		Language:      Language(f.Lang),
+		Copyright:     Copyright(f.Rights.String()),
+		Generator:     Generator(strings.TrimSpace(f.Generator)),
+		LastBuildDate: LastBuildDate(f.Updated),
+	}
+	if f.Logo != "" {
+		c.Image = &Image{URL: URL(f.Logo), Title: c.Title, Link: c.Link}
+	}
+	for _, e := range f.Entries {
+		item := &Item{
+			Title:       Title(e.Title.String()),
+			Link:        Link(atomLinkHref(e.Links, "alternate")),
+			Description: Description(e.Summary.String()),
+			PubDate:     PubDate(e.Published),
+		}
+		if content := e.Content.String(); content != "" {
+			item.ContentEncoded = ContentEncoded(content)
+			if item.Description == "" {
+				item.Description = Description(content)
+			}
+		}
+		if item.PubDate == "" {
+			item.PubDate = PubDate(e.Updated)
+		}
+		if e.ID != "" {
+			item.GUID = &GUID{IsPermaLink: "false", Value: e.ID}
+		}
+		if len(e.Authors) > 0 && e.Authors[0].Email != "" {
+			a := e.Authors[0]
+			item.Author = Author(a.Email)
+			if a.Name != "" {
+				item.Author = Author(a.Email + " (" + a.Name + ")")
+			}
+		}
+		for _, cat := range e.Categories {
+			item.Category = append(item.Category, &Category{Domain: cat.Scheme, Value: cat.Term})
+		}
+		for _, l := range e.Links {
+			if l.Rel == "enclosure" {
+				item.Enclosure = &Enclosure{URL: URL(l.Href), Length: l.Length, Type: l.Type}
+				break
+			}
+		}
+		c.Item = append(c.Item, item)
+	}
+	return &RSS{Version: "2.0", Channel: c}, nil
+}
+
+// atomLinkHref returns the href of the first link with the relation rel. A
+// link without a relation is an alternate link.
+func atomLinkHref(links []atomLink, rel string) string {
+	for _, l := range links {
+		if l.Rel == rel || (l.Rel == "" && rel == "alternate") {
+			return l.Href
+		}
+	}
+	return ""
+}
+
+// SelfLink returns the URL of the feed given by the first <atom:link> element
+// of the channel with the relation "self", or "" if there is none.
+func (c *Channel) SelfLink() string {
+	for _, x := range c.Extensions {
+		if x.XMLName != (xml.Name{Space: AtomNamespace, Local: "link"}) {
+			continue
+		}
+		var rel, href string
+		for _, a := range x.Attrs {
+			switch a.Name.Local {
+			case "rel":
+				rel = a.Value
+			case "href":
+				href = a.Value
+			}
+		}
+		if rel == "self" && href != "" {
+			return href
+		}
+	}
+	return ""
+}
+
+// atomFeedOut is an Atom feed document written by ToAtom. Unlike atomFeed, its
+// elements are unqualified, so that the Atom namespace is only declared on the
+// root element.
+type atomFeedOut struct {
+	XMLName   xml.Name        `xml:"feed"`
+	Xmlns     string          `xml:"xmlns,attr"`
+	Lang      string          `xml:"http://www.w3.org/XML/1998/namespace lang,attr,omitempty"`
+	ID        string          `xml:"id"`
+	Title     string          `xml:"title"`
+	Subtitle  string          `xml:"subtitle,omitempty"`
+	Links     []atomLink      `xml:"link"`
+	Authors   []atomPersonOut `xml:"author"`
+	Rights    string          `xml:"rights,omitempty"`
+	Generator string          `xml:"generator,omitempty"`
+	Logo      string          `xml:"logo,omitempty"`
+	Updated   string          `xml:"updated"`
+	Entries   []*atomEntryOut `xml:"entry"`
+}
+
+type atomEntryOut struct {
+	ID         string          `xml:"id"`
+	Title      string          `xml:"title"`
+	Links      []atomLink      `xml:"link"`
+	Authors    []atomPersonOut `xml:"author"`
+	Categories []atomCategory  `xml:"category"`
+	Published  string          `xml:"published,omitempty"`
+	Updated    string          `xml:"updated"`
+	Summary    *atomTextOut    `xml:"summary"`
+	Content    *atomTextOut    `xml:"content"`
+}
+
+type atomPersonOut struct {
+	Name  string `xml:"name"`
+	Email string `xml:"email,omitempty"`
+}
+
+type atomTextOut struct {
+	Type string `xml:"type,attr,omitempty"`
+	Text string `xml:",chardata"`
+}
+
+// ToAtom returns the Atom 1.0 encoding of r. It is the inverse of ParseAtom:
+// the description of the channel becomes the subtitle of the feed, its
+// copyright the rights and its image the logo, and the description of each
+// item becomes the summary of its entry, its content:encoded the content, its
+// author the author and its enclosure an enclosure link. Elements required by
+// Atom are synthesized from the channel and its items:
+//
+//   - the id of the feed is the link of the channel
+//   - the id of each entry is its guid, or its link if it has no guid
+//   - the updated date of the feed is the last build date of the channel, or
+//     its publication date, or the date of its latest item
+//   - the updated date of each entry is its publication date, or the updated
+//     date of the feed
+//   - the author of the feed is the managing editor of the channel, if any
+//
+// Ids that are not absolute IRIs are replaced by "urn:sha1:" URNs derived from
+// them. Dates are written as RFC 3339 dates.
+func (r *RSS) ToAtom() ([]byte, error) {
+	c := r.Channel
+	if c == nil {
+		return nil, &ValidationError{Path: "channel", Err: ErrMissingChannel}
+	}
+	f := &atomFeedOut{
+		Xmlns:     AtomNamespace,
+		Lang:      string(c.Language),
+		ID:        atomID(string(c.Link), string(c.Title)),
+		Title:     string(c.Title),
+		Subtitle:  string(c.Description),
+		Rights:    string(c.Copyright),
+		Generator: string(c.Generator),
+		Updated:   atomUpdated(c),
+	}
+	if c.Image != nil {
+		f.Logo = string(c.Image.URL)
+	}
+	if c.ManagingEditor != "" {
+		f.Authors = append(f.Authors, atomAuthor(string(c.ManagingEditor)))
+	}
+	if c.Link != "" {
+		f.Links = append(f.Links, atomLink{Href: string(c.Link), Rel: "alternate"})
+	}
+	if self := c.SelfLink(); self != "" {
+		f.Links = append(f.Links, atomLink{Href: self, Rel: "self"})
+	}
+	for _, item := range c.Item {
+		e := &atomEntryOut{
+			ID:      atomID(item.Key(), string(item.Title)+"\x00"+string(item.Description)),
+			Title:   string(item.Title),
+			Updated: f.Updated,
+		}
+		if d, ok := atomDate(string(item.PubDate)); ok {
+			e.Published, e.Updated = d, d
+		}
+		if item.Link != "" {
+			e.Links = append(e.Links, atomLink{Href: string(item.Link), Rel: "alternate"})
+		}
+		if enc := item.Enclosure; enc != nil && enc.URL != "" {
+			e.Links = append(e.Links, atomLink{Href: string(enc.URL), Rel: "enclosure", Type: enc.Type, Length: enc.Length})
+		}
+		if item.Author != "" {
+			e.Authors = append(e.Authors, atomAuthor(string(item.Author)))
+		}
+		for _, cat := range item.Category {
+			e.Categories = append(e.Categories, atomCategory{Term: cat.Value, Scheme: cat.Domain})
+		}
+		if item.Description != "" {
+			e.Summary = &atomTextOut{Type: "html", Text: string(item.Description)}
+		}
+		if item.ContentEncoded != "" {
+			e.Content = &atomTextOut{Type: "html", Text: string(item.ContentEncoded)}
+		}
+		f.Entries = append(f.Entries, e)
+	}
+	b, err := xml.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(b, '\n')...), nil
+}
+
+// atomAuthor returns the Atom person for the RSS email address s, which has the
+// form "email (name)" or "email". If s has no name, the email address is used
+// as the name, as Atom requires one.
+func atomAuthor(s string) atomPersonOut {
+	email, name := s, ""
+	if i := strings.Index(s, " ("); i >= 0 && strings.HasSuffix(s, ")") {
+		email, name = s[:i], strings.TrimSpace(s[i+2:len(s)-1])
+	}
+	if name == "" {
+		name = email
+	}
+	return atomPersonOut{Name: name, Email: email}
+}
+
+// atomID returns id if it is an absolute IRI, or a "urn:sha1:" URN derived from
+// id, or from fallback if id is empty.
+func atomID(id, fallback string) string {
+	if u, err := url.Parse(id); err == nil && u.Scheme != "" {
+		return id
+	}
+	if id == "" {
+		id = fallback
+	}
+	sum := sha1.Sum([]byte(id))
+	return "urn:sha1:" + hex.EncodeToString(sum[:])
+}
+
+// atomUpdated returns the updated date of the Atom feed of c.
+func atomUpdated(c *Channel) string {
+	if d, ok := atomDate(string(c.LastBuildDate)); ok {
+		return d
+	}
+	if d, ok := atomDate(string(c.PubDate)); ok {
+		return d
+	}
+	if d, ok := atomDate(string(c.LatestPubDate())); ok {
+		return d
+	}
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+// atomDate returns the date s as an RFC 3339 date.
+func atomDate(s string) (string, bool) {
+	t, err := ParseDate(s)
+	if err != nil {
+		return "", false
+	}
+	return t.Format(time.RFC3339), true
+}
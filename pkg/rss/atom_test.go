@@ -0,0 +1,152 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"bytes"
+	"encoding/xml"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseAtom(t *testing.T) {
+	f, err := os.Open("../../test/data/atom-0.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	r, err := ParseAtom(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+	c := r.Channel
+	if c.Title != "Example Feed" || c.Link != "http://example.org/" || c.Description != "A subtitle." {
+		t.Errorf("Channel = %+v", c)
+	}
+	if c.Language != "en" || c.LastBuildDate != "2003-12-13T18:30:02Z" {
+		t.Errorf("Channel.Language = %q, Channel.LastBuildDate = %q", c.Language, c.LastBuildDate)
+	}
+	if len(c.Item) != 2 {
+		t.Fatalf("len(Channel.Item) = %d, want 2", len(c.Item))
+	}
+	first := c.Item[0]
+	if first.Link != "http://example.org/2003/12/13/atom03" || first.Description != "Some text." {
+		t.Errorf("Item[0] = %+v", first)
+	}
+	if first.GUID == nil || first.GUID.Value != "urn:uuid:1225c695-cfb8-4ebb-aaaa-80da344efa6a" {
+		t.Errorf("Item[0].GUID = %+v", first.GUID)
+	}
+	if first.PubDate != "2003-12-13T08:29:29-04:00" {
+		t.Errorf("Item[0].PubDate = %q, want the published date", first.PubDate)
+	}
+	if first.Author != "johndoe@example.com (John Doe)" {
+		t.Errorf("Item[0].Author = %q", first.Author)
+	}
+	if first.Enclosure == nil || first.Enclosure.Type != "audio/mpeg" || first.Enclosure.Length != "1337" {
+		t.Errorf("Item[0].Enclosure = %+v", first.Enclosure)
+	}
+	if len(first.Category) != 1 || first.Category[0].Value != "robots" {
+		t.Errorf("Item[0].Category = %+v", first.Category)
+	}
+	second := c.Item[1]
+	if second.Title != "Second <em>entry</em>" {
+		t.Errorf("Item[1].Title = %q", second.Title)
+	}
+	if !strings.Contains(string(second.Description), "<p>Full content.</p>") {
+		t.Errorf("Item[1].Description = %q, want the content", second.Description)
+	}
+	if second.ContentEncoded != ContentEncoded(second.Description) {
+		t.Errorf("Item[1].ContentEncoded = %q, want the content", second.ContentEncoded)
+	}
+	if second.PubDate != "2003-12-14T18:30:02Z" {
+		t.Errorf("Item[1].PubDate = %q, want the updated date", second.PubDate)
+	}
+}
+
+func TestParseAtomNotAtom(t *testing.T) {
+	if _, err := ParseAtom(strings.NewReader(`<rss version="2.0"><channel/></rss>`)); err == nil {
+		t.Error("expected error for non-Atom document")
+	}
+}
+
+func TestToAtomRoundTrip(t *testing.T) {
+	r := mustParseFile(t, "../../test/data/rss-0.xml")
+	r.Channel.Item[0].Author = "editor@liftoff.msfc.nasa.gov (Jane Doe)"
+	r.Channel.Item[0].Enclosure = &Enclosure{URL: "http://liftoff.msfc.nasa.gov/starcity.mp3", Length: "12216320", Type: "audio/mpeg"}
+	r.Channel.Item[0].Category = []*Category{{Value: "Space"}}
+	r.Channel.Item[0].ContentEncoded = "<p>How do Americans get ready to work with Russians aboard the International Space Station?</p>"
+	b, err := r.ToAtom()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ParseAtom(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, gc := r.Channel, got.Channel
+	if gc.Title != c.Title || gc.Link != c.Link || gc.Description != c.Description {
+		t.Errorf("channel = %q %q %q, want %q %q %q", gc.Title, gc.Link, gc.Description, c.Title, c.Link, c.Description)
+	}
+	if len(gc.Item) != len(c.Item) {
+		t.Fatalf("len(Item) = %d, want %d", len(gc.Item), len(c.Item))
+	}
+	for i, item := range c.Item {
+		g := gc.Item[i]
+		if g.Title != item.Title || g.Link != item.Link || g.Description != item.Description {
+			t.Errorf("Item[%d] = %q %q %q, want %q %q %q", i, g.Title, g.Link, g.Description, item.Title, item.Link, item.Description)
+		}
+		if g.ContentEncoded != item.ContentEncoded {
+			t.Errorf("Item[%d].ContentEncoded = %q, want %q", i, g.ContentEncoded, item.ContentEncoded)
+		}
+		if g.GUID == nil || g.GUID.Value != item.GUID.Value {
+			t.Errorf("Item[%d].GUID = %+v, want %q", i, g.GUID, item.GUID.Value)
+		}
+	}
+	first := gc.Item[0]
+	if first.Author != c.Item[0].Author {
+		t.Errorf("Item[0].Author = %q, want %q", first.Author, c.Item[0].Author)
+	}
+	if e := first.Enclosure; e == nil || *e != (Enclosure{URL: "http://liftoff.msfc.nasa.gov/starcity.mp3", Length: "12216320", Type: "audio/mpeg"}) {
+		t.Errorf("Item[0].Enclosure = %+v", e)
+	}
+	if len(first.Category) != 1 || first.Category[0].Value != "Space" {
+		t.Errorf("Item[0].Category = %+v", first.Category)
+	}
+}
+
+func TestToAtomSynthesizedFields(t *testing.T) {
+	r := &RSS{Version: "2.0", Channel: &Channel{
+		Title: "Untitled",
+		Item: []*Item{
+			{Title: "a", GUID: &GUID{IsPermaLink: "false", Value: "573"}, PubDate: "Tue, 03 Jun 2003 09:39:21 GMT"},
+			{Title: "b"},
+		},
+	}}
+	b, err := r.ToAtom()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var f atomFeed
+	if err := xml.Unmarshal(b, &f); err != nil {
+		t.Fatal(err)
+	}
+	if f.Updated != "2003-06-03T09:39:21Z" {
+		t.Errorf("updated = %q, want the date of the latest item", f.Updated)
+	}
+	if len(f.Entries) != 2 {
+		t.Fatalf("len(entries) = %d, want 2", len(f.Entries))
+	}
+	for i, e := range f.Entries {
+		if !strings.HasPrefix(e.ID, "urn:sha1:") {
+			t.Errorf("entry[%d].id = %q, want a urn:sha1: URN", i, e.ID)
+		}
+		if e.Updated == "" {
+			t.Errorf("entry[%d].updated is empty", i)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/NickolasHKraus/archor/internal/feedtest"
+)
+
+func BenchmarkValidate(b *testing.B) {
+	r := feedtest.GenerateFeed(1000, 1)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := r.Validate(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkValidateInvalid(b *testing.B) {
+	r := feedtest.GenerateFeed(1000, 1)
+	for _, item := range r.Channel.Item[:100] {
+		item.Title, item.Description = "", ""
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := r.Validate(); err == nil {
+			b.Fatal("expected error")
+		}
+	}
+}
+
+func BenchmarkWriteTo(b *testing.B) {
+	r := feedtest.GenerateFeed(1000, 1)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := r.WriteTo(io.Discard); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
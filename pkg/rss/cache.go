@@ -0,0 +1,96 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"io"
+	"sync"
+)
+
+// Cache is a cache of parsed feeds keyed by the SHA-256 hash of their
+// content. When full, the least recently used feed is evicted. A Cache is
+// safe for concurrent use.
+type Cache struct {
+	mu      sync.Mutex
+	size    int
+	lru     *list.List // of *cacheEntry, most recently used first
+	entries map[[sha256.Size]byte]*list.Element
+
+	// parse parses a feed on a cache miss.
+	parse func([]byte) (*RSS, error)
+}
+
+type cacheEntry struct {
+	key [sha256.Size]byte
+	rss *RSS
+}
+
+// NewCache returns a cache holding at most size feeds.
+func NewCache(size int) *Cache {
+	if size < 1 {
+		size = 1
+	}
+	return &Cache{
+		size:    size,
+		lru:     list.New(),
+		entries: make(map[[sha256.Size]byte]*list.Element),
+		parse:   func(b []byte) (*RSS, error) { return parse(b, 0) },
+	}
+}
+
+// Len returns the number of feeds in the cache.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+func (c *Cache) get(key [sha256.Size]byte) (*RSS, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(e)
+		return e.Value.(*cacheEntry).rss, true
+	}
+	return nil, false
+}
+
+func (c *Cache) add(key [sha256.Size]byte, rss *RSS) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(e)
+		e.Value.(*cacheEntry).rss = rss
+		return
+	}
+	c.entries[key] = c.lru.PushFront(&cacheEntry{key: key, rss: rss})
+	for c.lru.Len() > c.size {
+		e := c.lru.Back()
+		c.lru.Remove(e)
+		delete(c.entries, e.Value.(*cacheEntry).key)
+	}
+}
+
+// ParseCached reads an RSS document from r like Parse, but returns the feed
+// from c if a document with identical content was parsed before. The returned
+// feed is shared with other callers and must not be modified. As with Parse,
+// a document compressed with gzip is decompressed.
+func ParseCached(r io.Reader, c *Cache) (*RSS, error) {
+	b, err := readAll(r, -1)
+	if err != nil {
+		return nil, err
+	}
+	key := sha256.Sum256(b)
+	if rss, ok := c.get(key); ok {
+		return rss, nil
+	}
+	rss, err := c.parse(b)
+	if err != nil {
+		return nil, err
+	}
+	c.add(key, rss)
+	return rss, nil
+}
@@ -0,0 +1,125 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// countingCache returns a cache of the given size and a counter of the number
+// of times it parses a feed.
+func countingCache(size int) (*Cache, *int32) {
+	var n int32
+	c := NewCache(size)
+	c.parse = func(b []byte) (*RSS, error) {
+		atomic.AddInt32(&n, 1)
+		return parse(b, 0)
+	}
+	return c, &n
+}
+
+func feedDoc(title string) string {
+	return fmt.Sprintf(`<rss version="2.0"><channel><title>%s</title></channel></rss>`, title)
+}
+
+func TestParseCached(t *testing.T) {
+	c, n := countingCache(2)
+	first, err := ParseCached(strings.NewReader(feedDoc("a")), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := ParseCached(strings.NewReader(feedDoc("a")), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Error("expected cache hit to return the cached feed")
+	}
+	if got := atomic.LoadInt32(n); got != 1 {
+		t.Errorf("parsed %d times, want 1", got)
+	}
+}
+
+func TestParseCachedEviction(t *testing.T) {
+	c, n := countingCache(2)
+	for _, title := range []string{"a", "b", "a", "c", "a", "b"} {
+		if _, err := ParseCached(strings.NewReader(feedDoc(title)), c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// a, b and c are parsed; a hits twice as it is used more recently than
+	// b when c is added, evicting b, which is parsed again.
+	if got := atomic.LoadInt32(n); got != 4 {
+		t.Errorf("parsed %d times, want 4", got)
+	}
+	if got := c.Len(); got != 2 {
+		t.Errorf("Len() = %d, want 2", got)
+	}
+}
+
+func TestParseCachedGzip(t *testing.T) {
+	c := NewCache(2)
+	for _, name := range []string{"../../test/data/rss-0.xml", "../../test/data/rss-0.xml.gz"} {
+		f, err := os.Open(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r, err := ParseCached(f, c)
+		f.Close()
+		if err != nil {
+			t.Fatalf("ParseCached(%s): %v", name, err)
+		}
+		if want := mustParseFile(t, "../../test/data/rss-0.xml"); !r.Equal(want) {
+			t.Errorf("ParseCached(%s) = %+v, want %+v", name, r.Channel, want.Channel)
+		}
+	}
+	// The decompressed document is the same, so it is parsed once.
+	if got := c.Len(); got != 1 {
+		t.Errorf("Len() = %d, want 1", got)
+	}
+}
+
+func TestParseCachedError(t *testing.T) {
+	c, n := countingCache(2)
+	for i := 0; i < 2; i++ {
+		if _, err := ParseCached(strings.NewReader("<rss"), c); err == nil {
+			t.Fatal("expected error")
+		}
+	}
+	if got := atomic.LoadInt32(n); got != 2 {
+		t.Errorf("parsed %d times, want 2 (errors are not cached)", got)
+	}
+	if got := c.Len(); got != 0 {
+		t.Errorf("Len() = %d, want 0", got)
+	}
+}
+
+func TestParseCachedConcurrent(t *testing.T) {
+	c := NewCache(4)
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			title := fmt.Sprint(i % 8)
+			r, err := ParseCached(strings.NewReader(feedDoc(title)), c)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if string(r.Channel.Title) != title {
+				t.Errorf("Channel.Title = %q, want %q", r.Channel.Title, title)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if got := c.Len(); got > 4 {
+		t.Errorf("Len() = %d, want at most 4", got)
+	}
+}
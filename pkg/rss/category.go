@@ -0,0 +1,41 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import "strings"
+
+// AddCategory adds a category with the given name to the channel. The domain,
+// which identifies the taxonomy of the category, is optional, but must be an
+// absolute URL if it is given.
+func (c *Channel) AddCategory(name, domain string) error {
+	cat, err := newCategory(name, domain)
+	if err != nil {
+		return err
+	}
+	c.Category = append(c.Category, cat)
+	return nil
+}
+
+// AddCategory adds a category with the given name to the item. The domain,
+// which identifies the taxonomy of the category, is optional, but must be an
+// absolute URL if it is given.
+func (i *Item) AddCategory(name, domain string) error {
+	cat, err := newCategory(name, domain)
+	if err != nil {
+		return err
+	}
+	i.Category = append(i.Category, cat)
+	return nil
+}
+
+func newCategory(name, domain string) (*Category, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, &ValidationError{Path: "category", Err: ErrMissingCategory}
+	}
+	if domain != "" && !IsValidURL(domain) {
+		return nil, &ValidationError{Path: "category.domain", Err: ErrInvalidURL}
+	}
+	return &Category{Domain: domain, Value: name}, nil
+}
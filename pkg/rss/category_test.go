@@ -0,0 +1,76 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestAddCategory(t *testing.T) {
+	r := validFeed()
+	c := r.Channel
+	if err := c.AddCategory("Science", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AddCategory(" Space ", "http://www.dmoz.org/"); err != nil {
+		t.Fatal(err)
+	}
+	want := []Category{{Value: "Science"}, {Domain: "http://www.dmoz.org/", Value: "Space"}}
+	if len(c.Category) != len(want) {
+		t.Fatalf("Category = %+v, want %+v", c.Category, want)
+	}
+	for i := range want {
+		if c.Category[i].Domain != want[i].Domain || c.Category[i].Value != want[i].Value {
+			t.Errorf("Category[%d] = %+v, want %+v", i, c.Category[i], want[i])
+		}
+	}
+
+	item := &Item{Title: "a"}
+	c.Item = append(c.Item, item)
+	if err := item.AddCategory("Rockets", "http://example.com/taxonomy"); err != nil {
+		t.Fatal(err)
+	}
+	b, err := Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		`<category>Science</category>`,
+		`<category domain="http://www.dmoz.org/">Space</category>`,
+		`<category domain="http://example.com/taxonomy">Rockets</category>`,
+	} {
+		if !bytes.Contains(b, []byte(s)) {
+			t.Errorf("output does not contain %s:\n%s", s, b)
+		}
+	}
+}
+
+func TestAddCategoryErrors(t *testing.T) {
+	tests := []struct {
+		name, domain string
+		path         string
+		want         error
+	}{
+		{"", "", "category", ErrMissingCategory},
+		{"  ", "http://www.dmoz.org/", "category", ErrMissingCategory},
+		{"Science", "dmoz", "category.domain", ErrInvalidURL},
+	}
+	for _, tt := range tests {
+		var c Channel
+		err := c.AddCategory(tt.name, tt.domain)
+		var verr *ValidationError
+		if !errors.As(err, &verr) || verr.Path != tt.path || !errors.Is(err, tt.want) {
+			t.Errorf("AddCategory(%q, %q) = %v, want %s: %v", tt.name, tt.domain, err, tt.path, tt.want)
+		}
+		if len(c.Category) != 0 {
+			t.Errorf("AddCategory(%q, %q) added a category", tt.name, tt.domain)
+		}
+		var i Item
+		if err := i.AddCategory(tt.name, tt.domain); !errors.Is(err, tt.want) {
+			t.Errorf("Item.AddCategory(%q, %q) = %v, want %v", tt.name, tt.domain, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// imageExtensions are the file extensions of GIF, JPEG and PNG images.
+var imageExtensions = map[string]bool{
+	".gif":  true,
+	".jpg":  true,
+	".jpeg": true,
+	".png":  true,
+}
+
+// SetImage sets the image of the channel. The URL must be an absolute URL of
+// a GIF, JPEG or PNG image, as indicated by its extension, and the link must
+// be an absolute URL. If title or link is empty, the title or link of the
+// channel is used, as recommended by the specification.
+func (c *Channel) SetImage(imageURL, title, link string) error {
+	img := &Image{URL: URL(imageURL), Title: Title(title), Link: Link(link)}
+	if img.Title == "" {
+		img.Title = c.Title
+	}
+	if img.Link == "" {
+		img.Link = c.Link
+	}
+	if !img.URL.IsValid() {
+		return &ValidationError{Path: "image.url", Err: ErrInvalidURL}
+	}
+	u, _ := url.Parse(imageURL)
+	if !imageExtensions[strings.ToLower(path.Ext(u.Path))] {
+		return &ValidationError{Path: "image.url", Err: ErrInvalidImageType}
+	}
+	if img.Title == "" {
+		return &ValidationError{Path: "image.title", Err: ErrMissingTitle}
+	}
+	if !img.Link.IsValid() {
+		return &ValidationError{Path: "image.link", Err: ErrInvalidURL}
+	}
+	c.Image = img
+	return nil
+}
+
+// SetLanguage sets the language of the channel to code in its canonical
+// form: lowercase, with subtags separated by hyphens (e.g. "EN_US" becomes
+// "en-us"). If the code is not valid (see Language.IsValid), the language is
+// unchanged and a ValidationError wrapping ErrInvalidLanguage is returned. An
+// empty code removes the language.
+func (c *Channel) SetLanguage(code string) error {
+	lang := Language(strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "_", "-")))
+	if !lang.IsValid() {
+		return &ValidationError{Path: "language", Err: fmt.Errorf("%w: %q", ErrInvalidLanguage, code)}
+	}
+	c.Language = lang
+	return nil
+}
+
+// SetCopyright sets the copyright notice of the channel to
+// "Copyright © <year> <holder>", using the current year.
+func (c *Channel) SetCopyright(holder string) {
+	notice := fmt.Sprintf("Copyright © %d", time.Now().Year())
+	if holder = strings.TrimSpace(holder); holder != "" {
+		notice += " " + holder
+	}
+	c.Copyright = Copyright(notice)
+}
+
+// PruneBefore removes the items of the channel published before t and
+// returns the number of items removed. Items whose publication date is
+// missing or cannot be parsed are kept, unless dropUndated is true.
+func (c *Channel) PruneBefore(t time.Time, dropUndated bool) int {
+	items := c.Item[:0]
+	for _, item := range c.Item {
+		pt, err := item.PubDate.Time()
+		if err != nil {
+			if !dropUndated {
+				items = append(items, item)
+			}
+			continue
+		}
+		if !pt.Before(t) {
+			items = append(items, item)
+		}
+	}
+	n := len(c.Item) - len(items)
+	for i := len(items); i < len(c.Item); i++ {
+		c.Item[i] = nil
+	}
+	c.Item = items
+	return n
+}
+
+// SortByDate sorts the items of the channel by publication date, newest
+// first. Items whose publication date is missing or cannot be parsed follow
+// the dated items. The order of items with equal dates is preserved.
+func (c *Channel) SortByDate() {
+	times := make(map[*Item]time.Time, len(c.Item))
+	for _, item := range c.Item {
+		if t, err := item.PubDate.Time(); err == nil {
+			times[item] = t
+		}
+	}
+	sort.SliceStable(c.Item, func(i, j int) bool {
+		ti, iok := times[c.Item[i]]
+		tj, jok := times[c.Item[j]]
+		if iok != jok {
+			return iok
+		}
+		return ti.After(tj)
+	})
+}
+
+// TransformItems replaces each item of the channel with the result of calling
+// fn with it. If fn returns nil, the item is removed.
+func (c *Channel) TransformItems(fn func(*Item) *Item) {
+	items := c.Item[:0]
+	for _, item := range c.Item {
+		if item = fn(item); item != nil {
+			items = append(items, item)
+		}
+	}
+	for i := len(items); i < len(c.Item); i++ {
+		c.Item[i] = nil
+	}
+	c.Item = items
+}
+
+// ItemByGUID returns the first item of the channel whose guid is guid, and
+// whether there is one.
+func (c *Channel) ItemByGUID(guid string) (*Item, bool) {
+	if guid == "" {
+		return nil, false
+	}
+	for _, item := range c.Item {
+		if item.GUID != nil && item.GUID.Value == guid {
+			return item, true
+		}
+	}
+	return nil, false
+}
+
+// LatestPubDate returns the publication date of the most recently published
+// item of the channel, as written in the item, or "" if no item has a valid
+// publication date.
+func (c *Channel) LatestPubDate() PubDate {
+	var latest PubDate
+	var lt time.Time
+	for _, item := range c.Item {
+		if t, err := item.PubDate.Time(); err == nil && (latest == "" || t.After(lt)) {
+			latest, lt = item.PubDate, t
+		}
+	}
+	return latest
+}
+
+// KeepLatest removes all but the n most recent items of the channel and
+// returns the number of items removed. If the channel has more than n items,
+// they are sorted by publication date (see SortByDate); otherwise they are
+// left in order.
+func (c *Channel) KeepLatest(n int) int {
+	if n < 0 || len(c.Item) <= n {
+		return 0
+	}
+	c.SortByDate()
+	removed := len(c.Item) - n
+	for i := n; i < len(c.Item); i++ {
+		c.Item[i] = nil
+	}
+	c.Item = c.Item[:n]
+	return removed
+}
@@ -0,0 +1,264 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestPruneBefore(t *testing.T) {
+	since := time.Date(2003, time.May, 28, 0, 0, 0, 0, time.UTC)
+	items := func() []*Item {
+		return []*Item{
+			{Title: "a", PubDate: "Tue, 03 Jun 2003 09:39:21 GMT"},
+			{Title: "b", PubDate: "Tue, 20 May 2003 08:56:02 GMT"},
+			{Title: "c"},
+			{Title: "d", PubDate: "2003-05-30T11:06:42Z"},
+			{Title: "e", PubDate: "sometime"},
+			{Title: "f", PubDate: "Tue, 27 May 2003 08:37:32 GMT"},
+		}
+	}
+	tests := []struct {
+		dropUndated bool
+		want        []Title
+	}{
+		{false, []Title{"a", "c", "d", "e"}},
+		{true, []Title{"a", "d"}},
+	}
+	for _, tt := range tests {
+		c := &Channel{Item: items()}
+		n := c.PruneBefore(since, tt.dropUndated)
+		var got []Title
+		for _, item := range c.Item {
+			got = append(got, item.Title)
+		}
+		if len(got) != len(tt.want) || n != 6-len(tt.want) {
+			t.Fatalf("dropUndated=%v: PruneBefore() = %d, items %v, want %v", tt.dropUndated, n, got, tt.want)
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("dropUndated=%v: items = %v, want %v", tt.dropUndated, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestSortByDate(t *testing.T) {
+	c := &Channel{Item: []*Item{
+		{Title: "a", PubDate: "Tue, 20 May 2003 08:56:02 GMT"},
+		{Title: "b"},
+		{Title: "c", PubDate: "Tue, 03 Jun 2003 09:39:21 GMT"},
+		{Title: "d", PubDate: "2003-05-30T11:06:42Z"},
+		{Title: "e", PubDate: "sometime"},
+	}}
+	c.SortByDate()
+	var got []string
+	for _, item := range c.Item {
+		got = append(got, string(item.Title))
+	}
+	if want := []string{"c", "d", "a", "b", "e"}; !equalStrings(got, want) {
+		t.Errorf("items = %v, want %v", got, want)
+	}
+}
+
+func TestKeepLatest(t *testing.T) {
+	tests := []struct {
+		n       int
+		want    []string
+		removed int
+	}{
+		{2, []string{"c", "a"}, 1},
+		{3, []string{"a", "b", "c"}, 0},
+		{5, []string{"a", "b", "c"}, 0},
+		{0, nil, 3},
+	}
+	for _, tt := range tests {
+		c := &Channel{Item: []*Item{
+			{Title: "a", PubDate: "Tue, 20 May 2003 08:56:02 GMT"},
+			{Title: "b", PubDate: "Tue, 13 May 2003 08:56:02 GMT"},
+			{Title: "c", PubDate: "Tue, 03 Jun 2003 09:39:21 GMT"},
+		}}
+		removed := c.KeepLatest(tt.n)
+		var got []string
+		for _, item := range c.Item {
+			got = append(got, string(item.Title))
+		}
+		if !equalStrings(got, tt.want) || removed != tt.removed {
+			t.Errorf("KeepLatest(%d) = %d, items %v; want %d, %v", tt.n, removed, got, tt.removed, tt.want)
+		}
+	}
+}
+
+func TestTransformItems(t *testing.T) {
+	c := &Channel{Item: []*Item{{Title: "Star City"}, {Title: "Sky watchers"}, {Description: "untitled"}}}
+	c.TransformItems(func(item *Item) *Item {
+		if item.Title == "" {
+			return nil
+		}
+		item.Title = "[NASA] " + item.Title
+		return item
+	})
+	var got []string
+	for _, item := range c.Item {
+		got = append(got, string(item.Title))
+	}
+	if want := []string{"[NASA] Star City", "[NASA] Sky watchers"}; !equalStrings(got, want) {
+		t.Errorf("items = %q, want %q", got, want)
+	}
+}
+
+func TestItemByGUID(t *testing.T) {
+	c := &Channel{Item: []*Item{
+		{Title: "a", GUID: &GUID{Value: "1"}},
+		{Title: "b"},
+		{Title: "c", GUID: &GUID{Value: "2"}},
+		{Title: "d", GUID: &GUID{Value: "2"}},
+	}}
+	tests := []struct {
+		guid string
+		want Title
+		ok   bool
+	}{
+		{"1", "a", true},
+		{"2", "c", true},
+		{"3", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		item, ok := c.ItemByGUID(tt.guid)
+		if ok != tt.ok || (ok && item.Title != tt.want) || (!ok && item != nil) {
+			t.Errorf("ItemByGUID(%q) = %+v, %v, want %q, %v", tt.guid, item, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestLatestPubDate(t *testing.T) {
+	c := &Channel{Item: []*Item{
+		{Title: "a", PubDate: "Tue, 20 May 2003 08:56:02 GMT"},
+		{Title: "b", PubDate: "sometime"},
+		{Title: "c", PubDate: "2003-06-03T09:39:21Z"},
+		{Title: "d"},
+	}}
+	if got, want := c.LatestPubDate(), PubDate("2003-06-03T09:39:21Z"); got != want {
+		t.Errorf("LatestPubDate() = %q, want %q", got, want)
+	}
+	if got := (&Channel{Item: []*Item{{Title: "d"}}}).LatestPubDate(); got != "" {
+		t.Errorf("LatestPubDate() = %q, want empty", got)
+	}
+}
+
+func TestSetLanguage(t *testing.T) {
+	tests := []struct {
+		code string
+		want Language
+	}{
+		{"EN-US", "en-us"},
+		{"en", "en"},
+		{" fr_CA ", "fr-ca"},
+		{"zh-Hant-TW", "zh-hant-tw"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		c := &Channel{Language: "de"}
+		if err := c.SetLanguage(tt.code); err != nil {
+			t.Errorf("SetLanguage(%q) = %v", tt.code, err)
+		}
+		if c.Language != tt.want {
+			t.Errorf("SetLanguage(%q): Language = %q, want %q", tt.code, c.Language, tt.want)
+		}
+	}
+}
+
+func TestSetLanguageErrors(t *testing.T) {
+	for _, code := range []string{"xx", "english", "en-", "e", "en-us-!"} {
+		c := &Channel{Language: "de"}
+		err := c.SetLanguage(code)
+		if !errors.Is(err, ErrInvalidLanguage) {
+			t.Errorf("SetLanguage(%q) = %v, want ErrInvalidLanguage", code, err)
+		}
+		if c.Language != "de" {
+			t.Errorf("SetLanguage(%q): Language = %q, want unchanged", code, c.Language)
+		}
+	}
+}
+
+func TestSetCopyright(t *testing.T) {
+	year := time.Now().Year()
+	tests := []struct {
+		holder string
+		want   Copyright
+	}{
+		{"Nickolas Kraus", Copyright(fmt.Sprintf("Copyright © %d Nickolas Kraus", year))},
+		{" NASA ", Copyright(fmt.Sprintf("Copyright © %d NASA", year))},
+		{"", Copyright(fmt.Sprintf("Copyright © %d", year))},
+	}
+	for _, tt := range tests {
+		var c Channel
+		c.SetCopyright(tt.holder)
+		if c.Copyright != tt.want {
+			t.Errorf("SetCopyright(%q) = %q, want %q", tt.holder, c.Copyright, tt.want)
+		}
+		if !c.Copyright.IsValid() {
+			t.Errorf("Copyright(%q).IsValid() = false", c.Copyright)
+		}
+	}
+}
+
+func TestCopyrightIsValid(t *testing.T) {
+	tests := []struct {
+		in   Copyright
+		want bool
+	}{
+		{"Copyright 2002, Spartanburg Herald-Journal", true},
+		{"(c) NASA", true},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := tt.in.IsValid(); got != tt.want {
+			t.Errorf("Copyright(%q).IsValid() = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSetImage(t *testing.T) {
+	c := validFeed().Channel
+	if err := c.SetImage("http://liftoff.msfc.nasa.gov/logo.PNG", "Liftoff", "http://liftoff.msfc.nasa.gov/news/"); err != nil {
+		t.Fatal(err)
+	}
+	want := Image{URL: "http://liftoff.msfc.nasa.gov/logo.PNG", Title: "Liftoff", Link: "http://liftoff.msfc.nasa.gov/news/"}
+	if *c.Image != want {
+		t.Errorf("Image = %+v, want %+v", *c.Image, want)
+	}
+	if err := c.SetImage("http://liftoff.msfc.nasa.gov/logo.gif?v=2", "", ""); err != nil {
+		t.Fatal(err)
+	}
+	if c.Image.Title != c.Title || c.Image.Link != c.Link {
+		t.Errorf("Image = %+v, want title and link of channel", *c.Image)
+	}
+}
+
+func TestSetImageErrors(t *testing.T) {
+	tests := []struct {
+		url, title, link string
+		want             error
+	}{
+		{"http://liftoff.msfc.nasa.gov/press-kit.pdf", "", "", ErrInvalidImageType},
+		{"http://liftoff.msfc.nasa.gov/logo", "", "", ErrInvalidImageType},
+		{"/logo.png", "", "", ErrInvalidURL},
+		{"http://liftoff.msfc.nasa.gov/logo.png", "", "/news", ErrInvalidURL},
+	}
+	for _, tt := range tests {
+		c := validFeed().Channel
+		if err := c.SetImage(tt.url, tt.title, tt.link); !errors.Is(err, tt.want) {
+			t.Errorf("SetImage(%q, %q, %q) = %v, want error matching %v", tt.url, tt.title, tt.link, err, tt.want)
+		}
+		if c.Image != nil {
+			t.Errorf("SetImage(%q, %q, %q) set Image on error", tt.url, tt.title, tt.link)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// utf8BOM is the byte order mark of UTF-8.
+var utf8BOM = []byte{0xef, 0xbb, 0xbf}
+
+// encodingDecl matches the encoding declared in the XML declaration.
+var encodingDecl = regexp.MustCompile(`^\s*<\?xml[^>]*\sencoding\s*=\s*["']([^"']+)["']`)
+
+// charsetReader returns a reader decoding input, in the encoding named by
+// label (e.g. "ISO-8859-1"), to UTF-8. It is the CharsetReader of the
+// decoders of this package, which otherwise only read UTF-8.
+func charsetReader(label string, input io.Reader) (io.Reader, error) {
+	enc, err := htmlindex.Get(label)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported encoding %q", label)
+	}
+	return enc.NewDecoder().Reader(input), nil
+}
+
+// docEncoding records the character encoding of a parsed document.
+type docEncoding struct {
+	// declared is the encoding declared in the XML declaration, or "" if
+	// there is none.
+	declared string
+	// detected is the encoding detected from the content: "UTF-8" if the
+	// document starts with a UTF-8 byte order mark or has non-ASCII
+	// characters that are valid UTF-8, and "" otherwise.
+	detected string
+}
+
+// sniffEncoding returns the declared and detected encodings of the document
+// b.
+func sniffEncoding(b []byte) docEncoding {
+	var e docEncoding
+	bom := bytes.HasPrefix(b, utf8BOM)
+	if m := encodingDecl.FindSubmatch(bytes.TrimPrefix(b, utf8BOM)); m != nil {
+		e.declared = string(m[1])
+	}
+	if bom || (!isASCII(b) && utf8.Valid(b)) {
+		e.detected = "UTF-8"
+	}
+	return e
+}
+
+// mismatch reports whether the detected encoding differs from the declared
+// one. A document without a declared encoding is UTF-8.
+func (e docEncoding) mismatch() bool {
+	if e.detected == "" {
+		return false
+	}
+	return canonicalEncoding(e.declared) != canonicalEncoding(e.detected)
+}
+
+// canonicalEncoding returns the canonical name of the encoding label, so that
+// aliases (e.g. "latin1" and "ISO-8859-1") compare equal.
+func canonicalEncoding(label string) string {
+	if label == "" {
+		label = "UTF-8"
+	}
+	enc, err := htmlindex.Get(label)
+	if err != nil {
+		return label
+	}
+	name, err := htmlindex.Name(enc)
+	if err != nil {
+		return label
+	}
+	return name
+}
+
+func isASCII(b []byte) bool {
+	for _, c := range b {
+		if c >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,13 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+// ContentNamespace is the namespace of the RSS 1.0 Content module.
+//
+// See: https://web.resource.org/rss/1.0/modules/content/
+const ContentNamespace = "http://purl.org/rss/1.0/modules/content/"
+
+// ContentEncoded is the full content of an item (<content:encoded>), usually
+// HTML.
+type ContentEncoded string
@@ -0,0 +1,263 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"strings"
+	"time"
+)
+
+// dateLayouts are the layouts accepted when parsing a date. RSS 2.0 requires
+// dates to conform to RFC 822, but RFC 3339 (ISO 8601) dates are common in
+// practice, particularly in feeds that derive dates from Dublin Core.
+var dateLayouts = append(rfc822Layouts, rfc3339Layouts...)
+
+// rfc822Layouts are the RFC 822 layouts accepted when parsing a date.
+var rfc822Layouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"Mon, 2 Jan 2006 15:04 -0700",
+	"Mon, 2 Jan 2006 15:04 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04 -0700",
+	"2 Jan 2006 15:04 MST",
+	time.RFC822Z,
+	time.RFC822,
+	"Mon, 2 Jan 06 15:04:05 -0700",
+	"Mon, 2 Jan 06 15:04:05 MST",
+}
+
+// rfc3339Layouts are the RFC 3339 (ISO 8601) layouts accepted when parsing a
+// date.
+var rfc3339Layouts = []string{
+	time.RFC3339,
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05-0700",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// rfc822Zones are the offsets, in seconds east of UTC, of the time zone
+// abbreviations defined by RFC 822. time.Parse only knows the offset of an
+// abbreviation if it is used by the local time zone.
+var rfc822Zones = map[string]int{
+	"UT":  0,
+	"GMT": 0,
+	"Z":   0,
+	"EST": -5 * 60 * 60,
+	"EDT": -4 * 60 * 60,
+	"CST": -6 * 60 * 60,
+	"CDT": -5 * 60 * 60,
+	"MST": -7 * 60 * 60,
+	"MDT": -6 * 60 * 60,
+	"PST": -8 * 60 * 60,
+	"PDT": -7 * 60 * 60,
+}
+
+// ParseDate parses s as an RFC 822 date or an RFC 3339 (ISO 8601) date. If s
+// is not a valid date, ParseDate returns a *DateError.
+func ParseDate(s string) (time.Time, error) {
+	return parseDate(s, dateLayouts)
+}
+
+// isRFC822 returns true if s is an RFC 822 date.
+func isRFC822(s string) bool {
+	_, err := parseDate(s, rfc822Layouts)
+	return err == nil
+}
+
+func parseDate(s string, layouts []string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range layouts {
+		t, err := time.Parse(layout, s)
+		if err != nil {
+			continue
+		}
+		if name, offset := t.Zone(); offset == 0 {
+			if o, ok := rfc822Zones[name]; ok && o != 0 {
+				t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.FixedZone(name, o))
+			}
+		}
+		return t, nil
+	}
+	return time.Time{}, newDateError(s)
+}
+
+// newDateError returns the error describing the date s, which cannot be
+// parsed. If s has a localized month or day name, the error names it.
+func newDateError(s string) *DateError {
+	e := &DateError{Value: s}
+	if name, n := findLocalizedName(s); n != nil {
+		e.Name, e.Kind, e.Languages = name, n.kind, n.languages
+	}
+	return e
+}
+
+// formatDate formats t as an RFC 1123 date with a numeric time zone.
+func formatDate(t time.Time) string {
+	return t.Format(time.RFC1123Z)
+}
+
+// DateFormat is a format in which dates are written.
+type DateFormat string
+
+const (
+	// DateFormatRFC822 formats dates as RFC 822 dates with a numeric time
+	// zone, e.g. "03 Jun 03 09:39 +0000". RFC 822 dates have a two-digit
+	// year and no seconds.
+	DateFormatRFC822 DateFormat = "rfc822"
+	// DateFormatRFC1123Z formats dates as RFC 1123 dates with a numeric time
+	// zone, e.g. "Tue, 03 Jun 2003 09:39:21 +0000". This is the format
+	// recommended by the RSS Advisory Board.
+	DateFormatRFC1123Z DateFormat = "rfc1123z"
+	// DateFormatRFC3339 formats dates as RFC 3339 dates, e.g.
+	// "2003-06-03T09:39:21Z". RFC 3339 dates are not valid RSS 2.0 dates,
+	// but are accepted by most readers.
+	DateFormatRFC3339 DateFormat = "rfc3339"
+)
+
+var dateFormatLayouts = map[DateFormat]string{
+	DateFormatRFC822:   time.RFC822Z,
+	DateFormatRFC1123Z: time.RFC1123Z,
+	DateFormatRFC3339:  time.RFC3339,
+}
+
+// IsValid returns true if the date format is one of the DateFormat constants.
+func (f DateFormat) IsValid() bool {
+	_, ok := dateFormatLayouts[f]
+	return ok
+}
+
+// Format returns t formatted in the date format. If the date format is not
+// valid, t is formatted as by DateFormatRFC1123Z.
+func (f DateFormat) Format(t time.Time) string {
+	layout, ok := dateFormatLayouts[f]
+	if !ok {
+		layout = time.RFC1123Z
+	}
+	return t.Format(layout)
+}
+
+// IsValid returns true if the publication date is empty or a valid date.
+func (r PubDate) IsValid() bool {
+	if r == "" {
+		return true
+	}
+	_, err := ParseDate(string(r))
+	return err == nil
+}
+
+// validate returns a *DateError if the publication date is not empty and
+// not a valid date.
+func (r PubDate) validate() error {
+	if r == "" {
+		return nil
+	}
+	_, err := ParseDate(string(r))
+	return err
+}
+
+// Time returns the publication date as a time.Time.
+func (r PubDate) Time() (time.Time, error) {
+	return ParseDate(string(r))
+}
+
+// Normalize returns the publication date formatted as an RFC 1123 date with a
+// numeric time zone (RFC 1123Z).
+func (r PubDate) Normalize() (PubDate, error) {
+	return r.NormalizeFormat(DateFormatRFC1123Z)
+}
+
+// NormalizeFormat returns the publication date formatted in the date format
+// f.
+func (r PubDate) NormalizeFormat(f DateFormat) (PubDate, error) {
+	t, err := r.Time()
+	if err != nil {
+		return r, err
+	}
+	return PubDate(f.Format(t)), nil
+}
+
+// UTC returns the publication date converted to UTC and formatted as by
+// Normalize, e.g. "Tue, 03 Jun 2003 09:39:21 +0000".
+func (r PubDate) UTC() (PubDate, error) {
+	t, err := r.Time()
+	if err != nil {
+		return r, err
+	}
+	return PubDate(formatDate(t.UTC())), nil
+}
+
+// IsValid returns true if the last build date is empty or a valid date.
+func (r LastBuildDate) IsValid() bool {
+	return PubDate(r).IsValid()
+}
+
+// Time returns the last build date as a time.Time.
+func (r LastBuildDate) Time() (time.Time, error) {
+	return ParseDate(string(r))
+}
+
+// Normalize returns the last build date formatted as an RFC 1123 date with a
+// numeric time zone (RFC 1123Z).
+func (r LastBuildDate) Normalize() (LastBuildDate, error) {
+	d, err := PubDate(r).Normalize()
+	return LastBuildDate(d), err
+}
+
+// NormalizeFormat returns the last build date formatted in the date format
+// f.
+func (r LastBuildDate) NormalizeFormat(f DateFormat) (LastBuildDate, error) {
+	d, err := PubDate(r).NormalizeFormat(f)
+	return LastBuildDate(d), err
+}
+
+// UTC returns the last build date converted to UTC and formatted as by
+// Normalize, e.g. "Tue, 03 Jun 2003 09:39:21 +0000".
+func (r LastBuildDate) UTC() (LastBuildDate, error) {
+	d, err := PubDate(r).UTC()
+	return LastBuildDate(d), err
+}
+
+// DatesToUTC converts the publication and last build dates of the channel
+// and the publication dates of its items to UTC (see PubDate.UTC). Dates
+// that are empty or cannot be parsed are left unchanged.
+func (r *RSS) DatesToUTC() {
+	r.mapDates(PubDate.UTC)
+}
+
+// FormatDates formats the publication and last build dates of the channel
+// and the publication dates of its items in the date format f (see
+// PubDate.NormalizeFormat). Dates that are empty or cannot be parsed are left
+// unchanged.
+func (r *RSS) FormatDates(f DateFormat) {
+	r.mapDates(func(d PubDate) (PubDate, error) {
+		return d.NormalizeFormat(f)
+	})
+}
+
+// mapDates replaces the dates of the channel and its items with the result of
+// applying fn to them, unless fn returns an error.
+func (r *RSS) mapDates(fn func(PubDate) (PubDate, error)) {
+	c := r.Channel
+	if c == nil {
+		return
+	}
+	if d, err := fn(c.PubDate); err == nil {
+		c.PubDate = d
+	}
+	if d, err := fn(PubDate(c.LastBuildDate)); err == nil {
+		c.LastBuildDate = LastBuildDate(d)
+	}
+	for _, item := range c.Item {
+		if d, err := fn(item.PubDate); err == nil {
+			item.PubDate = d
+		}
+	}
+}
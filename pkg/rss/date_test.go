@@ -0,0 +1,214 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPubDateNormalize(t *testing.T) {
+	tests := []struct {
+		in   PubDate
+		want PubDate
+	}{
+		// RFC 822
+		{"Tue, 10 Jun 2003 04:00:00 GMT", "Tue, 10 Jun 2003 04:00:00 +0000"},
+		{"Tue, 10 Jun 2003 04:00:00 -0400", "Tue, 10 Jun 2003 04:00:00 -0400"},
+		{"Tue, 3 Jun 2003 09:39:21 EST", "Tue, 03 Jun 2003 09:39:21 -0500"},
+		{"10 Jun 2003 04:00 PDT", "Tue, 10 Jun 2003 04:00:00 -0700"},
+		{"10 Jun 03 04:00 GMT", "Tue, 10 Jun 2003 04:00:00 +0000"},
+		// RFC 3339
+		{"2003-06-10T04:00:00Z", "Tue, 10 Jun 2003 04:00:00 +0000"},
+		{"2003-06-10T04:00:00.123-05:00", "Tue, 10 Jun 2003 04:00:00 -0500"},
+		{"2003-06-10T04:00:00+0200", "Tue, 10 Jun 2003 04:00:00 +0200"},
+		{"2003-06-10T04:00Z", "Tue, 10 Jun 2003 04:00:00 +0000"},
+		{"2003-06-10", "Tue, 10 Jun 2003 00:00:00 +0000"},
+	}
+	for _, tt := range tests {
+		got, err := tt.in.Normalize()
+		if err != nil {
+			t.Errorf("Normalize(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPubDateNormalizeInvalid(t *testing.T) {
+	in := PubDate("yesterday")
+	got, err := in.Normalize()
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if got != in {
+		t.Errorf("Normalize() = %q, want input unchanged", got)
+	}
+}
+
+func TestPubDateNormalizeLocalized(t *testing.T) {
+	tests := []struct {
+		in   PubDate
+		want DateError
+	}{
+		{"mar., 12 déc. 2023 10:00:00 +0100", DateError{Name: "déc", Kind: "month", Languages: []string{"French"}}},
+		{"12 Dezember 2023 10:00:00 +0100", DateError{Name: "Dezember", Kind: "month", Languages: []string{"German"}}},
+		{"Lunes, 12 dic 2023 10:00:00 +0100", DateError{Name: "Lunes", Kind: "day", Languages: []string{"Spanish"}}},
+		{"12 mai 2023 10:00:00 +0100", DateError{Name: "mai", Kind: "month", Languages: []string{"French", "German", "Portuguese"}}},
+		{"yesterday", DateError{}},
+		// English names are not localized, even if the date is invalid.
+		{"Tue, 12 March 2023", DateError{}},
+	}
+	for _, tt := range tests {
+		got, err := tt.in.Normalize()
+		if got != tt.in {
+			t.Errorf("Normalize(%q) = %q, want input unchanged", tt.in, got)
+		}
+		var de *DateError
+		if !errors.As(err, &de) || !errors.Is(err, ErrInvalidDate) {
+			t.Errorf("Normalize(%q) error = %v, want a DateError wrapping ErrInvalidDate", tt.in, err)
+			continue
+		}
+		if de.Value != string(tt.in) {
+			t.Errorf("Normalize(%q): Value = %q, want the raw value", tt.in, de.Value)
+		}
+		if de.Name != tt.want.Name || de.Kind != tt.want.Kind || strings.Join(de.Languages, ",") != strings.Join(tt.want.Languages, ",") {
+			t.Errorf("Normalize(%q): name = %q (%s, %v), want %q (%s, %v)", tt.in, de.Name, de.Kind, de.Languages, tt.want.Name, tt.want.Kind, tt.want.Languages)
+		}
+	}
+
+	_, err := PubDate("mar., 12 déc. 2023 10:00:00 +0100").Normalize()
+	want := `invalid date "mar., 12 déc. 2023 10:00:00 +0100": "déc" is a French month name, but RFC 822 requires English names`
+	if err == nil || err.Error() != want {
+		t.Errorf("Normalize() error = %v, want %s", err, want)
+	}
+}
+
+func TestPubDateIsValid(t *testing.T) {
+	tests := []struct {
+		in   PubDate
+		want bool
+	}{
+		{"", true},
+		{"Tue, 10 Jun 2003 04:00:00 GMT", true},
+		{"2003-06-10T04:00:00Z", true},
+		{"10/06/2003", false},
+	}
+	for _, tt := range tests {
+		if got := tt.in.IsValid(); got != tt.want {
+			t.Errorf("PubDate(%q).IsValid() = %v, want %v", tt.in, got, tt.want)
+		}
+		if got := LastBuildDate(tt.in).IsValid(); got != tt.want {
+			t.Errorf("LastBuildDate(%q).IsValid() = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLastBuildDateNormalize(t *testing.T) {
+	got, err := LastBuildDate("2003-06-10T09:41:01Z").Normalize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := LastBuildDate("Tue, 10 Jun 2003 09:41:01 +0000"); got != want {
+		t.Errorf("Normalize() = %q, want %q", got, want)
+	}
+}
+
+func TestPubDateUTC(t *testing.T) {
+	tests := []struct {
+		in   PubDate
+		want PubDate
+	}{
+		{"Tue, 03 Jun 2003 04:39:21 EST", "Tue, 03 Jun 2003 09:39:21 +0000"},
+		{"Tue, 03 Jun 2003 09:39:21 GMT", "Tue, 03 Jun 2003 09:39:21 +0000"},
+		{"Tue, 03 Jun 2003 09:39:21 +0000", "Tue, 03 Jun 2003 09:39:21 +0000"},
+		{"2003-06-03T23:39:21-04:00", "Wed, 04 Jun 2003 03:39:21 +0000"},
+	}
+	for _, tt := range tests {
+		got, err := tt.in.UTC()
+		if err != nil {
+			t.Errorf("PubDate(%q).UTC(): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("PubDate(%q).UTC() = %q, want %q", tt.in, got, tt.want)
+		}
+		if again, _ := got.UTC(); again != got {
+			t.Errorf("PubDate(%q).UTC() = %q, not idempotent", got, again)
+		}
+	}
+	if _, err := PubDate("10/06/2003").UTC(); err == nil {
+		t.Error("expected error for invalid date")
+	}
+}
+
+func TestDatesToUTC(t *testing.T) {
+	r := validFeed()
+	r.Channel.PubDate = "Tue, 10 Jun 2003 04:00:00 EST"
+	r.Channel.LastBuildDate = "Tue, 10 Jun 2003 09:41:01 GMT"
+	r.Channel.Item = []*Item{
+		{Title: "a", PubDate: "Tue, 03 Jun 2003 09:39:21 PDT"},
+		{Title: "b", PubDate: "sometime"},
+		{Title: "c"},
+	}
+	r.DatesToUTC()
+	if want := PubDate("Tue, 10 Jun 2003 09:00:00 +0000"); r.Channel.PubDate != want {
+		t.Errorf("Channel.PubDate = %q, want %q", r.Channel.PubDate, want)
+	}
+	if want := LastBuildDate("Tue, 10 Jun 2003 09:41:01 +0000"); r.Channel.LastBuildDate != want {
+		t.Errorf("Channel.LastBuildDate = %q, want %q", r.Channel.LastBuildDate, want)
+	}
+	want := []PubDate{"Tue, 03 Jun 2003 16:39:21 +0000", "sometime", ""}
+	for i, item := range r.Channel.Item {
+		if item.PubDate != want[i] {
+			t.Errorf("Item[%d].PubDate = %q, want %q", i, item.PubDate, want[i])
+		}
+	}
+}
+
+func TestFormatDates(t *testing.T) {
+	tests := []struct {
+		format DateFormat
+		want   PubDate
+	}{
+		{DateFormatRFC822, "03 Jun 03 09:39 -0700"},
+		{DateFormatRFC1123Z, "Tue, 03 Jun 2003 09:39:21 -0700"},
+		{DateFormatRFC3339, "2003-06-03T09:39:21-07:00"},
+	}
+	for _, tt := range tests {
+		r := validFeed()
+		r.Channel.LastBuildDate = "2003-06-03T09:39:21-07:00"
+		r.Channel.Item = []*Item{
+			{Title: "a", PubDate: "Tue, 03 Jun 2003 09:39:21 PDT"},
+			{Title: "b", PubDate: "sometime"},
+		}
+		r.FormatDates(tt.format)
+		if got := r.Channel.LastBuildDate; got != LastBuildDate(tt.want) {
+			t.Errorf("%s: Channel.LastBuildDate = %q, want %q", tt.format, got, tt.want)
+		}
+		if got := r.Channel.Item[0].PubDate; got != tt.want {
+			t.Errorf("%s: Item[0].PubDate = %q, want %q", tt.format, got, tt.want)
+		}
+		if got := r.Channel.Item[1].PubDate; got != "sometime" {
+			t.Errorf("%s: Item[1].PubDate = %q, want it unchanged", tt.format, got)
+		}
+		if !r.Channel.Item[0].PubDate.IsValid() {
+			t.Errorf("%s: formatted date %q cannot be parsed", tt.format, r.Channel.Item[0].PubDate)
+		}
+	}
+}
+
+func TestDateFormatIsValid(t *testing.T) {
+	for _, f := range []DateFormat{DateFormatRFC822, DateFormatRFC1123Z, DateFormatRFC3339} {
+		if !f.IsValid() {
+			t.Errorf("DateFormat(%q).IsValid() = false, want true", f)
+		}
+	}
+	if DateFormat("iso").IsValid() {
+		t.Error(`DateFormat("iso").IsValid() = true, want false`)
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// entityRef matches a named or numeric character reference, e.g. "&amp;" or
+// "&#8217;".
+var entityRef = regexp.MustCompile(`&(#[0-9]+|#[xX][0-9a-fA-F]+|[a-zA-Z][a-zA-Z0-9]*);`)
+
+// IsHTML returns true if the description appears to be HTML rather than plain
+// text: if it contains a tag of a known HTML element (e.g. <p> or <br/>) or a
+// character reference (e.g. &amp;). Text that merely contains "<" or ">",
+// such as "a < b", is plain text.
+func (r Description) IsHTML() bool {
+	s := string(r)
+	if !strings.ContainsAny(s, "<&") {
+		return false
+	}
+	if entityRef.MatchString(s) {
+		return true
+	}
+	z := html.NewTokenizer(strings.NewReader(s))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return false
+		case html.StartTagToken, html.EndTagToken, html.SelfClosingTagToken:
+			name, _ := z.TagName()
+			if atom.Lookup(name) != 0 {
+				return true
+			}
+		}
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import "testing"
+
+func TestDescriptionIsHTML(t *testing.T) {
+	tests := []struct {
+		description Description
+		want        bool
+	}{
+		{"", false},
+		{"Sky watchers in Europe, Asia, and parts of Alaska and Canada.", false},
+		{"If x < y and y > z, nothing can be said of x and z.", false},
+		{"Ham & eggs <3", false},
+		{"<user@example.com> wrote", false},
+		{`<p>How do Americans get ready to work with Russians aboard the <a href="http://howe.iki.rssi.ru/GCTC/gctc_e.htm">Star City</a>?</p>`, true},
+		{"First line<br/>second line", true},
+		{"Fish &amp; chips", true},
+		{"It&#8217;s here", true},
+	}
+	for _, tt := range tests {
+		if got := tt.description.IsHTML(); got != tt.want {
+			t.Errorf("Description(%q).IsHTML() = %v, want %v", tt.description, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+// ItemDiff describes how the items of a feed changed.
+type ItemDiff struct {
+	// Added are the items of the new feed that are not in the old feed.
+	Added []*Item
+	// Removed are the items of the old feed that are not in the new feed.
+	Removed []*Item
+}
+
+// Diff compares the items of old and new, identifying items by their key (see
+// Item.Key). Either feed may be nil, in which case it has no items.
+func Diff(old, new *RSS) ItemDiff {
+	var d ItemDiff
+	oldKeys := itemKeys(old)
+	newKeys := itemKeys(new)
+	for _, item := range items(new) {
+		if !oldKeys[item.Key()] {
+			d.Added = append(d.Added, item)
+		}
+	}
+	for _, item := range items(old) {
+		if !newKeys[item.Key()] {
+			d.Removed = append(d.Removed, item)
+		}
+	}
+	return d
+}
+
+// Key returns a string identifying the item: its guid, or its link if it has
+// no guid, or its title if it has neither.
+func (i *Item) Key() string {
+	switch {
+	case i.GUID != nil && i.GUID.Value != "":
+		return i.GUID.Value
+	case i.Link != "":
+		return string(i.Link)
+	}
+	return string(i.Title)
+}
+
+func items(r *RSS) []*Item {
+	if r == nil || r.Channel == nil {
+		return nil
+	}
+	return r.Channel.Item
+}
+
+func itemKeys(r *RSS) map[string]bool {
+	keys := make(map[string]bool)
+	for _, item := range items(r) {
+		keys[item.Key()] = true
+	}
+	return keys
+}
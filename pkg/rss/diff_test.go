@@ -0,0 +1,54 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import "testing"
+
+func TestDiff(t *testing.T) {
+	old := validFeed()
+	old.Channel.Item = []*Item{
+		{Title: "a", GUID: &GUID{Value: "1"}},
+		{Title: "b", Link: "http://a.com/b"},
+		{Title: "c"},
+	}
+	new := validFeed()
+	new.Channel.Item = []*Item{
+		{Title: "d", GUID: &GUID{Value: "4"}},
+		{Title: "a (updated)", GUID: &GUID{Value: "1"}},
+		{Title: "b", Link: "http://a.com/b"},
+	}
+	d := Diff(old, new)
+	if len(d.Added) != 1 || d.Added[0].Title != "d" {
+		t.Errorf("Added = %v, want [d]", d.Added)
+	}
+	if len(d.Removed) != 1 || d.Removed[0].Title != "c" {
+		t.Errorf("Removed = %v, want [c]", d.Removed)
+	}
+}
+
+func TestDiffNil(t *testing.T) {
+	r := mustParseFile(t, "../../test/data/rss-0.xml")
+	if d := Diff(nil, r); len(d.Added) != 4 || len(d.Removed) != 0 {
+		t.Errorf("Diff(nil, r) = %d added, %d removed; want 4, 0", len(d.Added), len(d.Removed))
+	}
+	if d := Diff(r, nil); len(d.Added) != 0 || len(d.Removed) != 4 {
+		t.Errorf("Diff(r, nil) = %d added, %d removed; want 0, 4", len(d.Added), len(d.Removed))
+	}
+}
+
+func TestItemKey(t *testing.T) {
+	tests := []struct {
+		item *Item
+		want string
+	}{
+		{&Item{Title: "t", Link: "http://a.com/l", GUID: &GUID{Value: "g"}}, "g"},
+		{&Item{Title: "t", Link: "http://a.com/l", GUID: &GUID{}}, "http://a.com/l"},
+		{&Item{Title: "t"}, "t"},
+	}
+	for _, tt := range tests {
+		if got := tt.item.Key(); got != tt.want {
+			t.Errorf("Key() = %q, want %q", got, tt.want)
+		}
+	}
+}
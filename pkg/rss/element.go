@@ -0,0 +1,104 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"sort"
+	"strings"
+)
+
+// elementValidators maps the names of elements to functions validating their
+// values, which return the sentinel error describing an invalid value. They
+// are used both by ValidateElement and by Validate, so that both accept the
+// same values.
+var elementValidators = map[string]func(string) error{
+	"version": func(s string) error {
+		if s == "" {
+			return ErrMissingVersion
+		}
+		return invalidUnless(Version(s).IsValid(), ErrUnsupportedVersion)
+	},
+	"title": func(s string) error {
+		return invalidUnless(Title(s).IsValid(), ErrMissingTitle)
+	},
+	"link": func(s string) error {
+		if s == "" {
+			return ErrMissingLink
+		}
+		return invalidUnless(Link(s).IsValid(), ErrInvalidURL)
+	},
+	"description": func(s string) error {
+		return invalidUnless(strings.TrimSpace(s) != "", ErrMissingDescription)
+	},
+	"language": func(s string) error {
+		return invalidUnless(Language(s).IsValid(), ErrInvalidLanguage)
+	},
+	"pubDate": func(s string) error {
+		return PubDate(s).validate()
+	},
+	"lastBuildDate": func(s string) error {
+		return PubDate(s).validate()
+	},
+	"rating": func(s string) error {
+		return invalidUnless(Rating(s).IsValid(), ErrInvalidRating)
+	},
+	"url": func(s string) error {
+		if s == "" {
+			return ErrMissingURL
+		}
+		return invalidUnless(URL(s).IsValid(), ErrInvalidURL)
+	},
+	"name": func(s string) error {
+		return invalidUnless(strings.TrimSpace(s) != "", ErrMissingName)
+	},
+	"width": func(s string) error {
+		return Width(s).validate()
+	},
+	"height": func(s string) error {
+		return Height(s).validate()
+	},
+	"hour": func(s string) error {
+		return invalidUnless(Hour(s).IsValid(), ErrInvalidSkipHours)
+	},
+	"comments": func(s string) error {
+		return invalidUnless(Comments(s).IsValid(), ErrInvalidURL)
+	},
+}
+
+// invalidUnless returns err if ok is false, and nil otherwise.
+func invalidUnless(ok bool, err error) error {
+	if ok {
+		return nil
+	}
+	return err
+}
+
+// ValidateElement validates value as the text of the element name (e.g.
+// "pubDate", "link" or "language"), so that snippets can be validated without
+// a full document. The name is that of the element in the specification,
+// without the path of its parent; the version attribute is named "version".
+// If value is invalid, ValidateElement returns a ValidationError whose path
+// is name. If name is not one of ValidatedElements, it returns a
+// ValidationError wrapping ErrUnknownElement.
+func ValidateElement(name, value string) error {
+	fn, ok := elementValidators[name]
+	if !ok {
+		return &ValidationError{Path: name, Err: ErrUnknownElement}
+	}
+	if err := fn(value); err != nil {
+		return &ValidationError{Path: name, Err: err}
+	}
+	return nil
+}
+
+// ValidatedElements returns the names of the elements accepted by
+// ValidateElement, in sorted order.
+func ValidatedElements() []string {
+	names := make([]string, 0, len(elementValidators))
+	for name := range elementValidators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
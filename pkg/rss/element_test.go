@@ -0,0 +1,59 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateElement(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  error
+	}{
+		{"pubDate", "Sat, 07 Sep 2002 09:42:31 GMT", nil},
+		{"pubDate", "yesterday", ErrInvalidDate},
+		{"lastBuildDate", "2002-09-07T09:42:31Z", nil},
+		{"link", "http://liftoff.msfc.nasa.gov/", nil},
+		{"link", "liftoff.msfc.nasa.gov", ErrInvalidURL},
+		{"link", "", ErrMissingLink},
+		{"language", "en-us", nil},
+		{"language", "xx-yy", ErrInvalidLanguage},
+		{"title", " ", ErrMissingTitle},
+		{"description", "Liftoff to Space Exploration.", nil},
+		{"version", "0.91", ErrUnsupportedVersion},
+		{"width", "145", ErrExceedsMaximum},
+		{"height", "tall", ErrNotANumber},
+		{"hour", "24", ErrInvalidSkipHours},
+		{"url", "", ErrMissingURL},
+		{"url", "logo.png", ErrInvalidURL},
+		{"name", " ", ErrMissingName},
+		{"rating", `(PICS-1.1 "http://www.rsac.org/ratingsv01.html" l r (n 0 s 0 v 0 l 0))`, nil},
+		{"titel", "Liftoff News", ErrUnknownElement},
+	}
+	for _, tt := range tests {
+		err := ValidateElement(tt.name, tt.value)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("ValidateElement(%q, %q) = %v, want %v", tt.name, tt.value, err, tt.want)
+		}
+		var ve *ValidationError
+		if err != nil && (!errors.As(err, &ve) || ve.Path != tt.name) {
+			t.Errorf("ValidateElement(%q, %q) = %v, want a ValidationError with path %q", tt.name, tt.value, err, tt.name)
+		}
+	}
+}
+
+func TestValidatedElements(t *testing.T) {
+	names := ValidatedElements()
+	for i, name := range names {
+		if i > 0 && names[i-1] >= name {
+			t.Errorf("ValidatedElements() = %v, want sorted", names)
+		}
+		if errors.Is(ValidateElement(name, ""), ErrUnknownElement) {
+			t.Errorf("ValidateElement(%q) reports an unknown element", name)
+		}
+	}
+}
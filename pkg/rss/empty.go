@@ -0,0 +1,155 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// RemoveEmptyOptionalElements removes the optional child elements of the
+// channel and of its items that are empty or contain only whitespace, such as
+// <language></language>, <image></image> or <category/>, and returns their
+// paths. Without
+// this, MarshalOptions.PreserveOrder re-emits them. Required elements (the
+// title, link and description of the channel) are kept even if empty, so that
+// Validate reports them.
+func (c *Channel) RemoveEmptyOptionalElements() []string {
+	removed := removeEmptyElements(reflect.ValueOf(c).Elem(), &c.order, "")
+	for i, item := range c.Item {
+		removed = append(removed, removeEmptyElements(reflect.ValueOf(item).Elem(), &item.order, fmt.Sprintf("item[%d].", i))...)
+	}
+	return removed
+}
+
+// removeEmptyElements clears the empty optional string and struct pointer
+// fields of the struct v, drops the empty entries of its optional slice
+// fields, such as an empty <category/>, removes them from order and returns
+// their names, prefixed with prefix and followed by their index for slice
+// entries: first those in order, in document order, then those set in code,
+// in field order. Items are never removed, even if empty.
+func removeEmptyElements(v reflect.Value, order *[]xml.Name, prefix string) []string {
+	name, fields := structFields(v.Type())
+	// empty records the indices in fields of the empty optional fields, and
+	// whether they were cleared.
+	empty := make(map[int]bool)
+	// entries records the indices in fields of the slice fields with empty
+	// entries, and the indices of those entries in the slice.
+	entries := make(map[int][]int)
+	for j, f := range fields {
+		if f.attr || f.any || !f.omitempty {
+			continue
+		}
+		fv := v.Field(f.index)
+		switch {
+		case fv.Kind() == reflect.String && strings.TrimSpace(fv.String()) == "":
+			empty[j] = fv.String() != ""
+		case fv.Kind() == reflect.Ptr && fv.IsNil():
+			empty[j] = false
+		case fv.Kind() == reflect.Ptr && fv.Elem().Kind() == reflect.Struct && isEmptyElement(fv.Elem()):
+			empty[j] = true
+		case fv.Kind() == reflect.Slice && fv.Type().Elem() != itemType:
+			entries[j] = removeEmptyEntries(fv)
+			continue
+		default:
+			continue
+		}
+		fv.Set(reflect.Zero(fv.Type()))
+	}
+	var removed []string
+	var kept []xml.Name
+	reported := make(map[int]bool)
+	// seen counts the entries of each slice field found in order.
+	seen := make(map[int]int)
+	for _, n := range *order {
+		j, ok := match(fields, name.Space, n)
+		if !ok {
+			kept = append(kept, n)
+			continue
+		}
+		if idx, isSlice := entries[j]; isSlice {
+			k := seen[j]
+			seen[j]++
+			if len(idx) > 0 && idx[0] == k {
+				entries[j] = idx[1:]
+				removed = append(removed, fmt.Sprintf("%s%s[%d]", prefix, n.Local, k))
+				continue
+			}
+			kept = append(kept, n)
+			continue
+		}
+		if _, isEmpty := empty[j]; !isEmpty {
+			kept = append(kept, n)
+			continue
+		}
+		removed = append(removed, prefix+n.Local)
+		reported[j] = true
+	}
+	for j, f := range fields {
+		if empty[j] && !reported[j] {
+			removed = append(removed, prefix+f.name.Local)
+		}
+		for _, k := range entries[j] {
+			removed = append(removed, fmt.Sprintf("%s%s[%d]", prefix, f.name.Local, k))
+		}
+	}
+	*order = kept
+	return removed
+}
+
+// itemType is the type of the entries of Channel.Item.
+var itemType = reflect.TypeOf((*Item)(nil))
+
+// removeEmptyEntries drops the nil and empty (see isEmptyElement) struct
+// pointer entries of the slice v and returns their indices.
+func removeEmptyEntries(v reflect.Value) []int {
+	if v.Type().Elem().Kind() != reflect.Ptr || v.Type().Elem().Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	var idx []int
+	kept := reflect.MakeSlice(v.Type(), 0, v.Len())
+	for k := 0; k < v.Len(); k++ {
+		e := v.Index(k)
+		if e.IsNil() || isEmptyElement(e.Elem()) {
+			idx = append(idx, k)
+			continue
+		}
+		kept = reflect.Append(kept, e)
+	}
+	if len(idx) > 0 {
+		v.Set(kept)
+	}
+	return idx
+}
+
+// isEmptyElement returns true if the struct v has no attributes or content:
+// if its string fields contain only whitespace and its slice and pointer
+// fields are empty.
+func isEmptyElement(v reflect.Value) bool {
+	_, fields := structFields(v.Type())
+	for _, f := range fields {
+		fv := v.Field(f.index)
+		switch fv.Kind() {
+		case reflect.String:
+			if strings.TrimSpace(fv.String()) != "" {
+				return false
+			}
+		case reflect.Slice:
+			if fv.Len() > 0 {
+				return false
+			}
+		case reflect.Ptr:
+			if !fv.IsNil() && !isEmptyElement(fv.Elem()) {
+				return false
+			}
+		default:
+			if !fv.IsZero() {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,83 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRemoveEmptyOptionalElements(t *testing.T) {
+	r := mustParse(t, `<rss version="2.0"><channel><title></title>
+<link>http://liftoff.msfc.nasa.gov/</link>
+<description>Liftoff to Space Exploration.</description>
+<language></language><copyright>  </copyright><image></image>
+<item><title>Star City</title><category/><category>Space</category><guid>http://liftoff.msfc.nasa.gov/2003/06/03.html#item573</guid><comments/><author></author><category> </category></item>
+</channel></rss>`)
+	log := r.Repair()
+	want := []string{
+		"language: removed empty element",
+		"copyright: removed empty element",
+		"image: removed empty element",
+		"item[0].category[0]: removed empty element",
+		"item[0].comments: removed empty element",
+		"item[0].author: removed empty element",
+		"item[0].category[2]: removed empty element",
+	}
+	if !equalStrings(log, want) {
+		t.Errorf("Repair() =\n%s\nwant\n%s", strings.Join(log, "\n"), strings.Join(want, "\n"))
+	}
+	if r.Channel.Image != nil {
+		t.Errorf("Image = %+v, want nil", r.Channel.Image)
+	}
+	if cats := r.Channel.Item[0].Category; len(cats) != 1 || cats[0].Value != "Space" {
+		t.Errorf("Item[0].Category = %+v, want only Space", cats)
+	}
+	out, err := MarshalOptions{PreserveOrder: true}.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tag := range []string{"<language", "<copyright", "<image", "<comments", "<author", "<category/>", "<category></category>"} {
+		if strings.Contains(string(out), tag) {
+			t.Errorf("output contains %s:\n%s", tag, out)
+		}
+	}
+	if !strings.Contains(string(out), "<title></title>") {
+		t.Errorf("output is missing the empty required title:\n%s", out)
+	}
+	if err := r.Validate(); !errors.Is(err, ErrMissingTitle) {
+		t.Errorf("Validate() = %v, want %v", err, ErrMissingTitle)
+	}
+}
+
+func TestRemoveEmptyOptionalElementsBuiltInCode(t *testing.T) {
+	c := &Channel{
+		Title:       "Liftoff News",
+		Link:        "http://liftoff.msfc.nasa.gov/",
+		Description: "Liftoff to Space Exploration.",
+		Language:    "  ",
+		Copyright:   "Copyright 2003",
+		Image:       &Image{},
+		Category:    []*Category{{Value: "Space"}, {}},
+		Item:        []*Item{{Title: "Star City", Author: "\n", Category: []*Category{nil, {Value: " "}, {Domain: "http://www.fool.com/cusips", Value: "MSFT"}}}},
+	}
+	removed := c.RemoveEmptyOptionalElements()
+	want := []string{"language", "category[1]", "image", "item[0].author", "item[0].category[0]", "item[0].category[1]"}
+	if !equalStrings(removed, want) {
+		t.Errorf("RemoveEmptyOptionalElements() = %v, want %v", removed, want)
+	}
+	if c.Language != "" || c.Image != nil || c.Item[0].Author != "" {
+		t.Errorf("empty elements not cleared: %q %+v %q", c.Language, c.Image, c.Item[0].Author)
+	}
+	if len(c.Category) != 1 || len(c.Item[0].Category) != 1 || c.Item[0].Category[0].Value != "MSFT" {
+		t.Errorf("categories = %+v %+v, want the non-empty ones", c.Category, c.Item[0].Category)
+	}
+	if c.Copyright != "Copyright 2003" || c.Item[0].Title != "Star City" {
+		t.Errorf("non-empty elements changed: %q %q", c.Copyright, c.Item[0].Title)
+	}
+	if removed := c.RemoveEmptyOptionalElements(); len(removed) != 0 {
+		t.Errorf("RemoveEmptyOptionalElements() again = %v, want none", removed)
+	}
+}
@@ -0,0 +1,270 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// MarshalOptions configures the encoding of an RSS document.
+type MarshalOptions struct {
+	// PreserveOrder emits the child elements of the channel and of each item
+	// in the order in which they appeared in the source document, rather than
+	// in struct field order. Elements that were not present in the source
+	// document follow in struct field order.
+	PreserveOrder bool
+}
+
+// Marshal returns the XML encoding of r.
+func Marshal(r *RSS) ([]byte, error) {
+	return MarshalOptions{}.Marshal(r)
+}
+
+// Marshal returns the XML encoding of r using the options in o.
+func (o MarshalOptions) Marshal(r *RSS) ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := o.Encode(&buf, r); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteTo writes the XML encoding of r to w and returns the number of bytes
+// written. It implements io.WriterTo.
+func (r *RSS) WriteTo(w io.Writer) (int64, error) {
+	return MarshalOptions{}.Encode(w, r)
+}
+
+// Encode writes the XML encoding of r to w using the options in o and returns
+// the number of bytes written. Unlike Marshal, it does not build the document
+// in memory: the channel header, each item and the footer are flushed to w
+// as they are encoded. The XMLName fields of r are first set by r.normalize.
+func (o MarshalOptions) Encode(w io.Writer, r *RSS) (int64, error) {
+	r.normalize()
+	cw := &countWriter{w: w}
+	if _, err := io.WriteString(cw, xml.Header); err != nil {
+		return cw.n, err
+	}
+	e := xml.NewEncoder(cw)
+	e.Indent("", "  ")
+	enc := &encoder{e: e, preserveOrder: o.PreserveOrder}
+	if err := enc.rss(r); err != nil {
+		return cw.n, err
+	}
+	if err := e.Close(); err != nil {
+		return cw.n, err
+	}
+	_, err := io.WriteString(cw, "\n")
+	return cw.n, err
+}
+
+// normalize sets the XMLName field of r, and of each struct it contains, to
+// the name in the struct tag of the field, so that documents built by hand
+// rather than parsed carry the same names as parsed ones. A name is only
+// replaced if it differs from the tag: the namespace of a parsed element
+// without one in its tag is kept.
+func (r *RSS) normalize() {
+	if r != nil {
+		setNames(reflect.ValueOf(r).Elem())
+	}
+}
+
+var nameType = reflect.TypeOf(xml.Name{})
+
+// setNames sets the XMLName field of the struct v, and of the structs,
+// pointers to structs and slices of them in its tagged fields.
+func setNames(v reflect.Value) {
+	t := v.Type()
+	if f, ok := t.FieldByName("XMLName"); ok && f.Type == nameType {
+		name := parseName(strings.Split(f.Tag.Get("xml"), ",")[0])
+		fv := v.FieldByIndex(f.Index)
+		cur := fv.Interface().(xml.Name)
+		if name.Local != "" && (cur.Local != name.Local || (name.Space != "" && cur.Space != name.Space)) {
+			fv.Set(reflect.ValueOf(name))
+		}
+	}
+	_, fields := structFields(t)
+	for _, f := range fields {
+		setFieldNames(v.Field(f.index))
+	}
+}
+
+func setFieldNames(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() && v.Elem().Kind() == reflect.Struct {
+			setNames(v.Elem())
+		}
+	case reflect.Struct:
+		setNames(v)
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			setFieldNames(v.Index(i))
+		}
+	}
+}
+
+// countWriter counts the bytes written to w.
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// MarshalXML implements xml.Marshaler. It emits the namespace declarations in
+// r.Namespaces on the <rss> element.
+func (r *RSS) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	return (&encoder{e: e}).rss(r)
+}
+
+// encoder encodes an RSS document. Elements in a namespace declared on the
+// <rss> element are emitted with the declared prefix (e.g. <itunes:author>).
+type encoder struct {
+	e             *xml.Encoder
+	preserveOrder bool
+	// prefixes maps namespaces to their declared prefixes.
+	prefixes map[string]string
+}
+
+func (enc *encoder) rss(r *RSS) error {
+	enc.prefixes = make(map[string]string)
+	for _, ns := range r.Namespaces {
+		if strings.HasPrefix(ns.Name.Local, "xmlns:") {
+			enc.prefixes[ns.Value] = strings.TrimPrefix(ns.Name.Local, "xmlns:")
+		}
+	}
+	start := xml.StartElement{
+		Name: xml.Name{Local: "rss"},
+		Attr: append(attrs(reflect.ValueOf(r).Elem()), r.Namespaces...),
+	}
+	if err := enc.e.EncodeToken(start); err != nil {
+		return err
+	}
+	if r.Channel != nil {
+		if err := enc.element(reflect.ValueOf(r.Channel).Elem(), r.Channel.order); err != nil {
+			return err
+		}
+	}
+	return enc.e.EncodeToken(start.End())
+}
+
+// name returns the name of an element in namespace space, using the declared
+// prefix of the namespace if there is one.
+func (enc *encoder) name(n xml.Name) xml.Name {
+	if prefix, ok := enc.prefixes[n.Space]; ok && n.Space != "" {
+		return xml.Name{Local: prefix + ":" + n.Local}
+	}
+	return n
+}
+
+// element writes the struct v (a Channel or an Item). If enc.preserveOrder is
+// set, child elements named in order are emitted first, in that order,
+// followed by any remaining non-empty child elements in struct field order.
+func (enc *encoder) element(v reflect.Value, order []xml.Name) error {
+	name, fields := structFields(v.Type())
+	start := xml.StartElement{Name: name, Attr: attrs(v)}
+	if err := enc.e.EncodeToken(start); err != nil {
+		return err
+	}
+	// emitted records the number of values emitted for each field.
+	emitted := make([]int, len(fields))
+	emit := func(j int) error {
+		f := fields[j]
+		fv := v.Field(f.index)
+		if fv.Kind() == reflect.Slice {
+			if emitted[j] >= fv.Len() {
+				return nil
+			}
+			fv = fv.Index(emitted[j])
+		} else if emitted[j] > 0 {
+			return nil
+		}
+		emitted[j]++
+		if fv.Kind() == reflect.Ptr && fv.IsNil() {
+			return nil
+		}
+		switch x := fv.Interface().(type) {
+		case *Item:
+			if err := enc.element(fv.Elem(), x.order); err != nil {
+				return err
+			}
+			return enc.e.Flush()
+		case *Extension:
+			return enc.extension(x)
+		}
+		return enc.e.EncodeElement(fv.Interface(), xml.StartElement{Name: enc.name(f.name)})
+	}
+	if enc.preserveOrder {
+		for _, n := range order {
+			if j, ok := match(fields, name.Space, n); ok {
+				if err := emit(j); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	for j, f := range fields {
+		if f.attr {
+			continue
+		}
+		fv := v.Field(f.index)
+		if fv.Kind() == reflect.Slice {
+			for emitted[j] < fv.Len() {
+				if err := emit(j); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if emitted[j] > 0 || (f.omitempty && fv.IsZero()) {
+			continue
+		}
+		if err := emit(j); err != nil {
+			return err
+		}
+	}
+	return enc.e.EncodeToken(start.End())
+}
+
+// extension writes the extension element x.
+func (enc *encoder) extension(x *Extension) error {
+	start := xml.StartElement{Name: enc.name(x.XMLName)}
+	for _, a := range x.Attrs {
+		switch {
+		case a.Name.Space == "xmlns":
+			a.Name = xml.Name{Local: "xmlns:" + a.Name.Local}
+		case a.Name.Space != "":
+			a.Name = enc.name(a.Name)
+		}
+		start.Attr = append(start.Attr, a)
+	}
+	v := struct {
+		InnerXML string `xml:",innerxml"`
+	}{x.InnerXML}
+	return enc.e.EncodeElement(v, start)
+}
+
+// attrs returns the attributes of the struct v, omitting empty attributes
+// tagged omitempty.
+func attrs(v reflect.Value) []xml.Attr {
+	var a []xml.Attr
+	_, fields := structFields(v.Type())
+	for _, f := range fields {
+		fv := v.Field(f.index)
+		if !f.attr || (f.omitempty && fv.IsZero()) {
+			continue
+		}
+		a = append(a, xml.Attr{Name: f.name, Value: fv.String()})
+	}
+	return a
+}
@@ -0,0 +1,255 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// childOrder returns the local names of the child elements of the channel and
+// of each item in the XML document b, in document order.
+func childOrder(t *testing.T, b []byte) []string {
+	t.Helper()
+	var (
+		names []string
+		depth int
+	)
+	d := xml.NewDecoder(bytes.NewReader(b))
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return names
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			depth++
+			if depth == 3 || depth == 4 {
+				names = append(names, tok.Name.Local)
+			}
+		case xml.EndElement:
+			depth--
+		}
+	}
+}
+
+func localNames(names []xml.Name) []string {
+	s := make([]string, len(names))
+	for i, n := range names {
+		s[i] = n.Local
+	}
+	return s
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMarshalPreserveOrder(t *testing.T) {
+	src, err := os.ReadFile("../../test/data/rss-shuffled.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := Parse(bytes.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := MarshalOptions{PreserveOrder: true}
+	out, err := opts.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := childOrder(t, out), childOrder(t, src); !equalStrings(got, want) {
+		t.Errorf("element order = %v, want %v", got, want)
+	}
+	// Mirroring the output again must be idempotent at the byte level.
+	r, err = Parse(bytes.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := opts.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out, again) {
+		t.Errorf("round trip is not idempotent:\n%s\n---\n%s", out, again)
+	}
+}
+
+func TestMarshalStructOrder(t *testing.T) {
+	r := mustParseFile(t, "../../test/data/rss-shuffled.xml")
+	out, err := Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"title", "link", "description", "language", "generator", "docs",
+		"item", "title", "link", "category", "category", "guid", "pubDate",
+		"item", "description", "guid",
+	}
+	if got := childOrder(t, out); !equalStrings(got, want) {
+		t.Errorf("element order = %v, want %v", got, want)
+	}
+}
+
+func TestMarshalPreserveOrderAppendsNewElements(t *testing.T) {
+	r := mustParseFile(t, "../../test/data/rss-shuffled.xml")
+	r.Channel.Copyright = "Copyright 2003"
+	out, err := MarshalOptions{PreserveOrder: true}.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(out, []byte("<copyright>Copyright 2003</copyright>\n  </channel>")) {
+		t.Errorf("expected copyright to be appended to channel:\n%s", out)
+	}
+}
+
+func TestMarshalNamespaces(t *testing.T) {
+	const doc = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd" xmlns:content="http://purl.org/rss/1.0/modules/content/" xmlns:atom="http://www.w3.org/2005/Atom">
+  <channel>
+    <title>Example Podcast</title>
+    <itunes:author>John Doe</itunes:author>
+    <item>
+      <title>Episode 1</title>
+      <content:encoded><![CDATA[<p>Show notes</p>]]></content:encoded>
+      <itunes:duration>12:34</itunes:duration>
+    </item>
+  </channel>
+</rss>`
+	r, err := Parse(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []xml.Attr{
+		{Name: xml.Name{Local: "xmlns:itunes"}, Value: "http://www.itunes.com/dtds/podcast-1.0.dtd"},
+		{Name: xml.Name{Local: "xmlns:content"}, Value: "http://purl.org/rss/1.0/modules/content/"},
+		{Name: xml.Name{Local: "xmlns:atom"}, Value: "http://www.w3.org/2005/Atom"},
+	}
+	if len(r.Namespaces) != len(want) {
+		t.Fatalf("Namespaces = %v, want %v", r.Namespaces, want)
+	}
+	for i := range want {
+		if r.Namespaces[i] != want[i] {
+			t.Errorf("Namespaces[%d] = %v, want %v", i, r.Namespaces[i], want[i])
+		}
+	}
+	const root = `<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd" xmlns:content="http://purl.org/rss/1.0/modules/content/" xmlns:atom="http://www.w3.org/2005/Atom">`
+	for _, opts := range []MarshalOptions{{}, {PreserveOrder: true}} {
+		b, err := opts.Marshal(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range []string{root, "<itunes:author>John Doe</itunes:author>", "<itunes:duration>12:34</itunes:duration>", "<content:encoded>&lt;p&gt;Show notes&lt;/p&gt;</content:encoded>"} {
+			if !bytes.Contains(b, []byte(want)) {
+				t.Errorf("%+v: output does not contain %s:\n%s", opts, want, b)
+			}
+		}
+		again, err := Parse(bytes.NewReader(b))
+		if err != nil {
+			t.Fatalf("%+v: %v", opts, err)
+		}
+		if len(again.Namespaces) != 3 {
+			t.Errorf("%+v: round trip Namespaces = %v, want 3 declarations", opts, again.Namespaces)
+		}
+		if item := again.Channel.Item[0]; len(item.Extensions) != 1 || item.ContentEncoded != "<p>Show notes</p>" {
+			t.Errorf("%+v: round trip lost item content or extensions:\n%s", opts, b)
+		}
+	}
+}
+
+// chunkWriter records the size of each write.
+type chunkWriter struct {
+	bytes.Buffer
+	writes int
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return w.Buffer.Write(p)
+}
+
+func TestMarshalWithoutXMLName(t *testing.T) {
+	r := &RSS{
+		Version: "2.0",
+		Channel: &Channel{
+			Title:       "Liftoff News",
+			Link:        "http://liftoff.msfc.nasa.gov/",
+			Description: "Liftoff to Space Exploration.",
+			Category:    []*Category{{XMLName: xml.Name{Local: "tag"}, Value: "Space"}},
+			Image:       &Image{URL: "http://liftoff.msfc.nasa.gov/news.gif"},
+			Item: []*Item{{
+				XMLName: xml.Name{Local: "entry"},
+				Title:   "Star City",
+				GUID:    &GUID{Value: "http://liftoff.msfc.nasa.gov/2003/06/03.html#item573"},
+			}},
+		},
+	}
+	b, err := Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"title", "link", "description", "category", "image", "url", "title", "link", "item", "title", "guid"}
+	if got := childOrder(t, b); !equalStrings(got, want) {
+		t.Errorf("child elements = %v, want %v", got, want)
+	}
+	tests := []struct {
+		name string
+		got  xml.Name
+		want string
+	}{
+		{"RSS", r.XMLName, "rss"},
+		{"Channel", r.Channel.XMLName, "channel"},
+		{"Category", r.Channel.Category[0].XMLName, "category"},
+		{"Image", r.Channel.Image.XMLName, "image"},
+		{"Item", r.Channel.Item[0].XMLName, "item"},
+		{"GUID", r.Channel.Item[0].GUID.XMLName, "guid"},
+	}
+	for _, tt := range tests {
+		if tt.got != (xml.Name{Local: tt.want}) {
+			t.Errorf("%s.XMLName = %v, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestWriteTo(t *testing.T) {
+	for _, name := range []string{"rss-0.xml", "rss-media.xml", "rss-shuffled.xml"} {
+		t.Run(name, func(t *testing.T) {
+			r := mustParseFile(t, "../../test/data/"+name)
+			want, err := Marshal(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var w chunkWriter
+			n, err := r.WriteTo(&w)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(w.Bytes(), want) {
+				t.Errorf("WriteTo() wrote\n%s\nwant\n%s", w.Bytes(), want)
+			}
+			if n != int64(len(want)) {
+				t.Errorf("WriteTo() = %d, want %d", n, len(want))
+			}
+			if items := len(r.Channel.Item); w.writes <= items {
+				t.Errorf("WriteTo() made %d writes for %d items, want one per item", w.writes, items)
+			}
+		})
+	}
+}
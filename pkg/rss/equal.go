@@ -0,0 +1,112 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"bytes"
+	"encoding/xml"
+	"reflect"
+	"sort"
+)
+
+// Equal reports whether r and other are semantically equal: whether they have
+// the same version, channel metadata and items. Differences that do not
+// change the meaning of a feed are ignored:
+//
+//   - the order of the items of the channel
+//   - the order of the child elements of the channel and of each item
+//   - leading and trailing whitespace, and the length of runs of whitespace,
+//     in text and attribute values
+//   - the prefixes used for namespaces
+//
+// Either document may be nil, in which case it is only equal to nil.
+func (r *RSS) Equal(other *RSS) bool {
+	if r == nil || other == nil {
+		return r == other
+	}
+	if collapseSpace(string(r.Version)) != collapseSpace(string(other.Version)) {
+		return false
+	}
+	a, err := canonicalChannel(r.Channel)
+	if err != nil {
+		return false
+	}
+	b, err := canonicalChannel(other.Channel)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// canonicalChannel returns the canonical encodings of the channel c, without
+// its items, followed by those of its items in sorted order.
+func canonicalChannel(c *Channel) ([]string, error) {
+	if c == nil {
+		return nil, nil
+	}
+	meta := *c
+	meta.Item = nil
+	s, err := canonicalElement(&meta)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]string, len(c.Item))
+	for i, item := range c.Item {
+		if items[i], err = canonicalElement(item); err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(items)
+	return append([]string{s}, items...), nil
+}
+
+// canonicalElement returns the XML encoding of a copy of the channel or item
+// x with whitespace collapsed, its child elements in struct field order and
+// no namespace prefixes.
+func canonicalElement(x interface{}) (string, error) {
+	v := reflect.New(reflect.TypeOf(x).Elem()).Elem()
+	v.Set(reflect.ValueOf(x).Elem())
+	collapseValue(v)
+	var buf bytes.Buffer
+	enc := &encoder{e: xml.NewEncoder(&buf)}
+	if err := enc.element(v, nil); err != nil {
+		return "", err
+	}
+	if err := enc.e.Flush(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// collapseValue collapses the whitespace of the strings in the settable value
+// v (see collapseSpace), recursing into structs, pointers and slices. Values
+// referenced by pointers and slices are copied rather than modified.
+func collapseValue(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(collapseSpace(v.String()))
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if f := v.Field(i); f.CanSet() {
+				collapseValue(f)
+			}
+		}
+	case reflect.Ptr:
+		if !v.IsNil() {
+			c := reflect.New(v.Type().Elem())
+			c.Elem().Set(v.Elem())
+			collapseValue(c.Elem())
+			v.Set(c)
+		}
+	case reflect.Slice:
+		if !v.IsNil() {
+			c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+			reflect.Copy(c, v)
+			for i := 0; i < c.Len(); i++ {
+				collapseValue(c.Index(i))
+			}
+			v.Set(c)
+		}
+	}
+}
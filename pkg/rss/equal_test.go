@@ -0,0 +1,57 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEqual(t *testing.T) {
+	const feed = `<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd"><channel>
+<title>Liftoff News</title><link>http://liftoff.msfc.nasa.gov/</link>
+<itunes:author>NASA</itunes:author>
+<item><title>Star City</title><guid>1</guid><pubDate>Tue, 03 Jun 2003 09:39:21 GMT</pubDate></item>
+<item><title>Sky watchers</title><guid>2</guid></item>
+</channel></rss>`
+	tests := []struct {
+		name string
+		doc  string
+		want bool
+	}{
+		{"identical", feed, true},
+		{
+			"reordered",
+			`<rss version="2.0" xmlns:i="http://www.itunes.com/dtds/podcast-1.0.dtd"><channel>
+  <i:author>NASA</i:author>
+  <link>http://liftoff.msfc.nasa.gov/</link>
+  <title>
+    Liftoff   News
+  </title>
+  <item><guid>2</guid><title>Sky watchers</title></item>
+  <item><pubDate>Tue, 03 Jun 2003 09:39:21 GMT</pubDate><guid> 1 </guid><title>Star City</title></item>
+</channel></rss>`,
+			true,
+		},
+		{"changed title", strings.Replace(feed, "<title>Star City</title>", "<title>Star City!</title>", 1), false},
+		{"changed extension", strings.Replace(feed, ">NASA<", ">ESA<", 1), false},
+		{"removed item", strings.Replace(feed, "<item><title>Sky watchers</title><guid>2</guid></item>", "", 1), false},
+	}
+	a := mustParse(t, feed)
+	for _, tt := range tests {
+		b := mustParse(t, tt.doc)
+		if got := a.Equal(b); got != tt.want {
+			t.Errorf("%s: Equal() = %v, want %v", tt.name, got, tt.want)
+		}
+		if got := b.Equal(a); got != tt.want {
+			t.Errorf("%s: reversed Equal() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if a.Equal(nil) {
+		t.Error("Equal(nil) = true, want false")
+	}
+	if a.Channel.Title != "Liftoff News" {
+		t.Errorf("Equal modified its receiver: Title = %q", a.Channel.Title)
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrFeedTooLarge is returned by ParseLimit when the feed exceeds the
+	// size limit.
+	ErrFeedTooLarge = errors.New("feed exceeds size limit")
+	// ErrUnsupportedFormat is returned by ParseAny when the document is not
+	// an RSS 2.0, Atom or RSS 1.0 (RDF) document.
+	ErrUnsupportedFormat = errors.New("unsupported feed format")
+	// ErrTooManyItems is returned by ParseOptions when the document has
+	// more items than ParseOptions.MaxItems.
+	ErrTooManyItems = errors.New("feed exceeds item limit")
+
+	// ErrMissingChannel indicates that the document has no <channel>.
+	ErrMissingChannel = errors.New("missing required channel")
+	// ErrMissingVersion indicates that the version attribute is missing or
+	// empty.
+	ErrMissingVersion = errors.New("missing required version")
+	// ErrUnsupportedVersion indicates that the version attribute is present
+	// but is not "2.0".
+	ErrUnsupportedVersion = errors.New("unsupported version")
+	// ErrMissingTitle indicates that a required <title> is missing or empty.
+	ErrMissingTitle = errors.New("missing required title")
+	// ErrMissingLink indicates that a required <link> is missing or empty.
+	ErrMissingLink = errors.New("missing required link")
+	// ErrMissingDescription indicates that a required <description> is
+	// missing or empty.
+	ErrMissingDescription = errors.New("missing required description")
+	// ErrMissingName indicates that a required <name> is missing or empty.
+	ErrMissingName = errors.New("missing required name")
+	// ErrMissingURL indicates that a required url attribute is missing or
+	// empty.
+	ErrMissingURL = errors.New("missing required url")
+	// ErrRequiredElement indicates an attempt to remove a required element.
+	ErrRequiredElement = errors.New("required element")
+	// ErrMissingTitleOrDescription indicates that an item has neither a
+	// <title> nor a <description>.
+	ErrMissingTitleOrDescription = errors.New("missing title or description")
+	// ErrInvalidURL indicates that an element that must be an absolute URL
+	// is not.
+	ErrInvalidURL = errors.New("invalid URL")
+	// ErrMissingCategory indicates that a category has no name.
+	ErrMissingCategory = errors.New("missing category name")
+	// ErrInvalidImageType indicates that an image URL does not have the
+	// extension of a GIF, JPEG or PNG image.
+	ErrInvalidImageType = errors.New("not a GIF, JPEG or PNG image")
+	// ErrNotANumber indicates that an element that must be a number is not.
+	ErrNotANumber = errors.New("is not a number")
+	// ErrExceedsMaximum indicates that a number exceeds the maximum allowed
+	// by the specification.
+	ErrExceedsMaximum = errors.New("exceeds maximum")
+	// ErrInvalidDate indicates that a date is not a valid RFC 822 or
+	// RFC 3339 date.
+	ErrInvalidDate = errors.New("invalid date")
+	// ErrInvalidSkipHours indicates that <skipHours> lists more than 24
+	// hours, an hour outside 0-23, or the same hour more than once.
+	ErrInvalidSkipHours = errors.New("invalid hours")
+	// ErrInvalidPermaLink indicates that the isPermaLink attribute of a guid
+	// is neither "true" nor "false".
+	ErrInvalidPermaLink = errors.New(`isPermaLink is not "true" or "false"`)
+	// ErrInvalidLanguage indicates that a language is not an ISO 639
+	// language code, optionally followed by a region.
+	ErrInvalidLanguage = errors.New("invalid language code")
+	// ErrInvalidRating indicates that a <rating> is not a PICS label.
+	ErrInvalidRating = errors.New("invalid PICS rating")
+	// ErrInvalidElement indicates that an extension element is not valid
+	// according to the RSSElement registered for it (see RegisterElement).
+	ErrInvalidElement = errors.New("invalid element")
+	// ErrDuplicateGUID indicates that an item has the same guid as an
+	// earlier item of the channel. It is reported by Lint.
+	ErrDuplicateGUID = errors.New("duplicate guid")
+	// ErrDuplicateEnclosure indicates that an item has the same enclosure
+	// URL as an earlier item of the channel. It is reported by Lint.
+	ErrDuplicateEnclosure = errors.New("duplicate enclosure URL")
+	// ErrFutureDate indicates that an item is dated further in the future
+	// than allowed, which is likely a bug of the feed. It is reported by
+	// Lint.
+	ErrFutureDate = errors.New("date in the future")
+	// ErrEncodingMismatch indicates that the content of a document is not in
+	// the encoding declared in its XML declaration. It is reported by Lint.
+	ErrEncodingMismatch = errors.New("encoding mismatch")
+	// ErrMissingGUID indicates that an item has no guid. It is reported by
+	// Lint.
+	ErrMissingGUID = errors.New("missing recommended guid")
+	// ErrPermalinkNotURL indicates that the guid of an item is declared a
+	// permalink (isPermaLink="true"), but is not an absolute URL. It is
+	// reported by Lint.
+	ErrPermalinkNotURL = errors.New("permalink guid is not a URL")
+	// ErrPermalinkMismatch indicates that the guid of an item is declared a
+	// permalink, but is on a different host than the link of the item. It
+	// is reported by Lint.
+	ErrPermalinkMismatch = errors.New("permalink guid does not match link")
+	// ErrDescriptionTooLong indicates that a description is longer than
+	// MaxDescriptionLength. It is reported by Lint.
+	ErrDescriptionTooLong = errors.New("description too long")
+	// ErrMissingEnclosure indicates that an item of a podcast has no
+	// enclosure. It is reported by Lint.
+	ErrMissingEnclosure = errors.New("missing enclosure")
+	// ErrInvalidEnclosure indicates that an enclosure does not have an
+	// absolute url, a numeric length and a type. It is reported by Lint.
+	ErrInvalidEnclosure = errors.New("invalid enclosure")
+	// ErrUnknownElement indicates that the channel or an item has a child
+	// element that is not defined by the specification and is not in the
+	// namespace of a module. It is reported by Lint and ParseStrict.
+	ErrUnknownElement = errors.New("unknown element")
+)
+
+// ParseError describes a document that could not be decoded, such as
+// malformed XML, and where in the document decoding failed.
+type ParseError struct {
+	// Line is the line of the document, starting at 1, at which decoding
+	// failed.
+	Line int
+	// Offset is the byte offset in the document at which decoding failed.
+	Offset int64
+	// Err is the decode error, usually an *xml.SyntaxError.
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	msg := e.Err.Error()
+	var se *xml.SyntaxError
+	if errors.As(e.Err, &se) {
+		msg = se.Msg
+	}
+	return fmt.Sprintf("line %d (byte %d): %s", e.Line, e.Offset, msg)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// DateError describes a date that cannot be parsed. It wraps ErrInvalidDate.
+type DateError struct {
+	// Value is the date as written in the document.
+	Value string
+	// Name, if set, is a month or day name in Value that is not English,
+	// such as "déc." in a French date. RFC 822 only allows English names.
+	Name string
+	// Kind is "month" or "day" if Name is set.
+	Kind string
+	// Languages are the languages using Name, e.g. ["French"].
+	Languages []string
+}
+
+func (e *DateError) Error() string {
+	if e.Name == "" {
+		return fmt.Sprintf("%s %q", ErrInvalidDate, e.Value)
+	}
+	return fmt.Sprintf("%s %q: %q is a %s %s name, but RFC 822 requires English names",
+		ErrInvalidDate, e.Value, e.Name, strings.Join(e.Languages, " or "), e.Kind)
+}
+
+func (e *DateError) Unwrap() error {
+	return ErrInvalidDate
+}
+
+// ValidationError describes an invalid element of an RSS document.
+type ValidationError struct {
+	// Path is the path of the element relative to the channel, e.g.
+	// "image.url" or "item[2].title". Elements of the <rss> element itself
+	// (the version attribute and the channel) are named without a prefix.
+	Path string
+	// Err is the reason the element is invalid. It wraps one of the
+	// sentinel errors of this package.
+	Err error
+	// Rule is the ID of the lint rule reporting the error (e.g.
+	// RuleMissingGUID), or empty for errors not reported by Lint.
+	Rule string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Path + ": " + e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationErrors is a list of validation errors.
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	s := make([]string, len(e))
+	for i, err := range e {
+		s[i] = err.Error()
+	}
+	return strings.Join(s, "; ")
+}
+
+// Is reports whether any error in e matches target.
+func (e ValidationErrors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import "github.com/NickolasHKraus/archor/internal/version"
+
+// DefaultGenerator returns the generator of feeds built or mirrored by
+// archor: "archor/<version>".
+func DefaultGenerator() Generator {
+	return Generator("archor/" + version.Version)
+}
+
+// NewFeed returns an RSS 2.0 document with a channel having the required
+// title, link and description, and the generator DefaultGenerator(). Its
+// XMLName fields are set.
+func NewFeed(title Title, link Link, description Description) *RSS {
+	r := &RSS{
+		Version: "2.0",
+		Channel: &Channel{
+			Title:       title,
+			Link:        link,
+			Description: description,
+			Generator:   DefaultGenerator(),
+		},
+	}
+	r.normalize()
+	return r
+}
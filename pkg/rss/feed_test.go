@@ -0,0 +1,23 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"testing"
+
+	"github.com/NickolasHKraus/archor/internal/version"
+)
+
+func TestNewFeed(t *testing.T) {
+	r := NewFeed("Liftoff News", "http://liftoff.msfc.nasa.gov/", "Liftoff to Space Exploration.")
+	if err := r.Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+	if want := Generator("archor/" + version.Version); r.Channel.Generator != want {
+		t.Errorf("Generator = %q, want %q", r.Channel.Generator, want)
+	}
+	if r.XMLName.Local != "rss" || r.Channel.XMLName.Local != "channel" {
+		t.Errorf("XMLName = %v, %v, want rss, channel", r.XMLName, r.Channel.XMLName)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"encoding/xml"
+	"reflect"
+	"strings"
+)
+
+// field describes a field of a struct that is encoded as an XML attribute or
+// child element.
+type field struct {
+	index     int
+	name      xml.Name
+	attr      bool
+	omitempty bool
+	// any is true for the field that holds elements not matched by any
+	// other field.
+	any bool
+}
+
+// structFields returns the name of the element encoding the struct type t,
+// taken from its XMLName field, and the fields encoding its attributes and
+// child elements, in field order.
+func structFields(t reflect.Type) (xml.Name, []field) {
+	var (
+		name   xml.Name
+		fields []field
+	)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, ok := f.Tag.Lookup("xml")
+		if !ok || tag == "-" {
+			continue
+		}
+		opts := strings.Split(tag, ",")
+		if f.Name == "XMLName" {
+			name = parseName(opts[0])
+			continue
+		}
+		fields = append(fields, field{
+			index:     i,
+			name:      parseName(opts[0]),
+			attr:      hasOption(opts, "attr"),
+			omitempty: hasOption(opts, "omitempty"),
+			any:       opts[0] == "" && hasOption(opts, "any"),
+		})
+	}
+	return name, fields
+}
+
+// match returns the index in fields of the field encoding the child element
+// name of an element in namespace space. A field without a namespace matches
+// elements in the namespace of its parent, so that elements from namespace
+// modules (e.g. <atom:link>) are not mistaken for RSS elements of the same
+// name. If no field matches, the field holding unmatched elements is
+// returned, if any.
+func match(fields []field, space string, name xml.Name) (int, bool) {
+	any := -1
+	for i, f := range fields {
+		switch {
+		case f.attr:
+		case f.any:
+			any = i
+		case f.name.Local == name.Local:
+			if (f.name.Space == "" && name.Space == space) || (f.name.Space != "" && f.name.Space == name.Space) {
+				return i, true
+			}
+		}
+	}
+	return any, any >= 0
+}
+
+func hasOption(opts []string, opt string) bool {
+	for _, o := range opts[1:] {
+		if o == opt {
+			return true
+		}
+	}
+	return false
+}
+
+// parseName parses the name in a struct tag, which is either "local" or
+// "namespace local".
+func parseName(s string) xml.Name {
+	if space, local, ok := strings.Cut(s, " "); ok {
+		return xml.Name{Space: space, Local: local}
+	}
+	return xml.Name{Local: s}
+}
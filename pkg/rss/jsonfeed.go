@@ -0,0 +1,140 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JSONFeedVersion is the version of JSON Feed written by ToJSONFeed.
+//
+// See: https://www.jsonfeed.org/version/1.1/
+const JSONFeedVersion = "https://jsonfeed.org/version/1.1"
+
+type jsonFeed struct {
+	Version     string          `json:"version"`
+	Title       string          `json:"title"`
+	HomePageURL string          `json:"home_page_url,omitempty"`
+	FeedURL     string          `json:"feed_url,omitempty"`
+	Description string          `json:"description,omitempty"`
+	Icon        string          `json:"icon,omitempty"`
+	Language    string          `json:"language,omitempty"`
+	Items       []*jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string               `json:"id"`
+	URL           string               `json:"url,omitempty"`
+	Title         string               `json:"title,omitempty"`
+	ContentHTML   string               `json:"content_html,omitempty"`
+	ContentText   *string              `json:"content_text,omitempty"`
+	Summary       string               `json:"summary,omitempty"`
+	DatePublished string               `json:"date_published,omitempty"`
+	Authors       []jsonFeedAuthor     `json:"authors,omitempty"`
+	Tags          []string             `json:"tags,omitempty"`
+	Attachments   []jsonFeedAttachment `json:"attachments,omitempty"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonFeedAttachment struct {
+	URL         string `json:"url"`
+	MIMEType    string `json:"mime_type"`
+	SizeInBytes int64  `json:"size_in_bytes,omitempty"`
+}
+
+// JSONFeedOptions configures the JSON Feed encoding of an RSS document.
+type JSONFeedOptions struct {
+	// Indent, if set, is the indentation of each nesting level of the JSON
+	// object, e.g. two spaces. By default, the encoding is compact.
+	Indent string
+}
+
+// ToJSONFeed returns the JSON Feed encoding of r using the default options
+// (see JSONFeedOptions.Marshal).
+func (r *RSS) ToJSONFeed() ([]byte, error) {
+	return JSONFeedOptions{}.Marshal(r)
+}
+
+// Marshal returns the JSON Feed encoding of r using the options in o. The
+// home_page_url of the feed is the link of the channel, and its feed_url is
+// the self link of the channel (see Channel.SelfLink). The content of each
+// item is its content:encoded, or its description, as content_html if it is
+// HTML (see Description.IsHTML) and as content_text otherwise. The id of each
+// item is its key (see Item.Key).
+func (o JSONFeedOptions) Marshal(r *RSS) ([]byte, error) {
+	c := r.Channel
+	if c == nil {
+		return nil, &ValidationError{Path: "channel", Err: ErrMissingChannel}
+	}
+	f := &jsonFeed{
+		Version:     JSONFeedVersion,
+		Title:       string(c.Title),
+		HomePageURL: string(c.Link),
+		FeedURL:     c.SelfLink(),
+		Description: string(c.Description),
+		Language:    string(c.Language),
+		Items:       []*jsonFeedItem{},
+	}
+	if c.Image != nil {
+		f.Icon = string(c.Image.URL)
+	}
+	for _, item := range c.Item {
+		f.Items = append(f.Items, jsonItem(item))
+	}
+	if o.Indent != "" {
+		return json.MarshalIndent(f, "", o.Indent)
+	}
+	return json.Marshal(f)
+}
+
+func jsonItem(item *Item) *jsonFeedItem {
+	ji := &jsonFeedItem{
+		ID:    item.Key(),
+		URL:   string(item.Link),
+		Title: string(item.Title),
+	}
+	switch {
+	case item.ContentEncoded != "":
+		ji.ContentHTML = string(item.ContentEncoded)
+		ji.Summary = string(item.Description)
+	case item.Description.IsHTML():
+		ji.ContentHTML = string(item.Description)
+	default:
+		// An item requires content_html or content_text, which may be
+		// empty (e.g. for an item with only a title).
+		text := string(item.Description)
+		ji.ContentText = &text
+	}
+	if t, err := item.PubDate.Time(); err == nil {
+		ji.DatePublished = t.Format(time.RFC3339)
+	}
+	if item.Author != "" {
+		ji.Authors = []jsonFeedAuthor{{Name: authorName(string(item.Author))}}
+	}
+	for _, cat := range item.Category {
+		ji.Tags = append(ji.Tags, cat.Value)
+	}
+	if e := item.Enclosure; e != nil && e.URL != "" {
+		size, _ := strconv.ParseInt(strings.TrimSpace(e.Length), 10, 64)
+		ji.Attachments = []jsonFeedAttachment{{URL: string(e.URL), MIMEType: e.Type, SizeInBytes: size}}
+	}
+	return ji
+}
+
+// authorName returns the name in the RSS author s, which has the form
+// "email (name)", or s if it has no name.
+func authorName(s string) string {
+	if i := strings.Index(s, "("); i >= 0 && strings.HasSuffix(s, ")") {
+		if name := strings.TrimSpace(s[i+1 : len(s)-1]); name != "" {
+			return name
+		}
+	}
+	return s
+}
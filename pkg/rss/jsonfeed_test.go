@@ -0,0 +1,124 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestToJSONFeed(t *testing.T) {
+	r := validFeed()
+	r.Channel.Item = []*Item{
+		{
+			Title:       "Star City",
+			Link:        "http://liftoff.msfc.nasa.gov/news/2003/news-starcity.asp",
+			Description: `<p>How do Americans get ready to work with Russians?</p>`,
+			PubDate:     "Tue, 03 Jun 2003 09:39:21 GMT",
+			Author:      "editor@liftoff.msfc.nasa.gov (Jane Doe)",
+			Category:    []*Category{{Value: "Space"}},
+			Enclosure:   &Enclosure{URL: "http://liftoff.msfc.nasa.gov/starcity.mp3", Length: "12216320", Type: "audio/mpeg"},
+		},
+		{Description: "Sky watchers in Europe.", GUID: &GUID{Value: "urn:uuid:1"}},
+	}
+	b, err := r.ToJSONFeed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	items := got["items"].([]interface{})
+	first := items[0].(map[string]interface{})
+	if first["id"] != "http://liftoff.msfc.nasa.gov/news/2003/news-starcity.asp" ||
+		first["content_html"] != "<p>How do Americans get ready to work with Russians?</p>" ||
+		first["date_published"] != "2003-06-03T09:39:21Z" {
+		t.Errorf("items[0] = %v", first)
+	}
+	if a := first["authors"].([]interface{})[0].(map[string]interface{}); a["name"] != "Jane Doe" {
+		t.Errorf("items[0].authors = %v, want Jane Doe", first["authors"])
+	}
+	if a := first["attachments"].([]interface{})[0].(map[string]interface{}); a["size_in_bytes"] != 12216320.0 {
+		t.Errorf("items[0].attachments = %v", first["attachments"])
+	}
+	second := items[1].(map[string]interface{})
+	if second["id"] != "urn:uuid:1" || second["content_text"] != "Sky watchers in Europe." {
+		t.Errorf("items[1] = %v", second)
+	}
+}
+
+func TestToJSONFeedRequiredFields(t *testing.T) {
+	r := mustParse(t, `<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom"><channel>
+<title>Liftoff News</title><link>http://liftoff.msfc.nasa.gov/</link><description>Liftoff to Space Exploration.</description>
+<atom:link href="http://liftoff.msfc.nasa.gov/feed.xml" rel="self" type="application/rss+xml"/>
+<item><title>Star City</title><link>http://liftoff.msfc.nasa.gov/news/2003/news-starcity.asp</link></item>
+<item><description>Sky watchers in Europe.</description><guid isPermaLink="false">item572</guid></item>
+</channel></rss>`)
+	for _, indent := range []string{"", "  "} {
+		b, err := JSONFeedOptions{Indent: indent}.Marshal(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Contains(string(b), "\n  \"version\""); got != (indent != "") {
+			t.Errorf("Indent = %q: output indented = %v:\n%s", indent, got, b)
+		}
+		var feed map[string]interface{}
+		if err := json.Unmarshal(b, &feed); err != nil {
+			t.Fatal(err)
+		}
+		// version, title and items are required; home_page_url and
+		// feed_url are optional, but must be absolute URLs.
+		want := map[string]interface{}{
+			"version":       JSONFeedVersion,
+			"title":         "Liftoff News",
+			"home_page_url": "http://liftoff.msfc.nasa.gov/",
+			"feed_url":      "http://liftoff.msfc.nasa.gov/feed.xml",
+		}
+		for k, v := range want {
+			if feed[k] != v {
+				t.Errorf("%s = %v, want %v", k, feed[k], v)
+			}
+		}
+		items, ok := feed["items"].([]interface{})
+		if !ok || len(items) != 2 {
+			t.Fatalf("items = %v, want 2 items", feed["items"])
+		}
+		// Each item requires a non-empty string id, and content_html or
+		// content_text, even if it has only a title.
+		for i, item := range items {
+			m := item.(map[string]interface{})
+			if id, _ := m["id"].(string); id == "" {
+				t.Errorf("items[%d].id = %v, want a non-empty string", i, m["id"])
+			}
+			_, html := m["content_html"].(string)
+			_, text := m["content_text"].(string)
+			if !html && !text {
+				t.Errorf("items[%d] = %v, want content_html or content_text", i, m)
+			}
+		}
+		if text, ok := items[0].(map[string]interface{})["content_text"]; !ok || text != "" {
+			t.Errorf("items[0].content_text = %v, want an empty string for an item with only a title", text)
+		}
+	}
+}
+
+func TestSelfLink(t *testing.T) {
+	tests := []struct {
+		doc  string
+		want string
+	}{
+		{`<atom:link href="http://example.com/feed.xml" rel="self"/>`, "http://example.com/feed.xml"},
+		{`<atom:link href="http://example.com/" rel="alternate"/><atom:link rel="self" href="http://example.com/rss"/>`, "http://example.com/rss"},
+		{`<atom:link href="http://example.com/hub" rel="hub"/>`, ""},
+		{``, ""},
+	}
+	for _, tt := range tests {
+		r := mustParse(t, `<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom"><channel><title>a</title>`+tt.doc+`</channel></rss>`)
+		if got := r.Channel.SelfLink(); got != tt.want {
+			t.Errorf("SelfLink() of %s = %q, want %q", tt.doc, got, tt.want)
+		}
+	}
+}
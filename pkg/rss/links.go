@@ -0,0 +1,165 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// LinkRef is a URL referenced by an element of a feed.
+type LinkRef struct {
+	// Path is the path of the element, as in ValidationError.
+	Path string
+	URL  string
+}
+
+// Links returns every URL referenced by the feed, deduplicated and in
+// document order: the channel link, docs, image URL and link, text input
+// link, and for each item its link, comments, enclosure, source, and
+// media:content and media:thumbnail URLs.
+func (r *RSS) Links() []string {
+	var links []string
+	seen := make(map[string]bool)
+	for _, ref := range r.LinkRefs() {
+		if !seen[ref.URL] {
+			seen[ref.URL] = true
+			links = append(links, ref.URL)
+		}
+	}
+	return links
+}
+
+// LinkRefs returns every URL referenced by the feed, with the path of the
+// element referencing it, in document order. Unlike Links, URLs referenced by
+// more than one element are repeated.
+func (r *RSS) LinkRefs() []LinkRef {
+	var refs []LinkRef
+	add := func(path, url string) {
+		if url != "" {
+			refs = append(refs, LinkRef{Path: path, URL: url})
+		}
+	}
+	c := r.Channel
+	if c == nil {
+		return nil
+	}
+	add("link", string(c.Link))
+	add("docs", string(c.Docs))
+	if c.Image != nil {
+		add("image.url", string(c.Image.URL))
+		add("image.link", string(c.Image.Link))
+	}
+	if c.TextInput != nil {
+		add("textInput.link", string(c.TextInput.Link))
+	}
+	for i, item := range c.Item {
+		path := fmt.Sprintf("item[%d]", i)
+		add(path+".link", string(item.Link))
+		add(path+".comments", string(item.Comments))
+		if item.Enclosure != nil {
+			add(path+".enclosure", string(item.Enclosure.URL))
+		}
+		if item.Source != nil {
+			add(path+".source", string(item.Source.URL))
+		}
+		for j, mc := range item.MediaContent {
+			add(fmt.Sprintf("%s.media:content[%d]", path, j), string(mc.URL))
+		}
+		for j, mt := range item.MediaThumbnail {
+			add(fmt.Sprintf("%s.media:thumbnail[%d]", path, j), string(mt.URL))
+		}
+	}
+	return refs
+}
+
+// DefaultTrackingParams are the query parameters removed by
+// StripQueryParams when no parameters are given: Google Analytics (UTM)
+// campaign parameters and the Facebook click identifier.
+var DefaultTrackingParams = []string{
+	"utm_source",
+	"utm_medium",
+	"utm_campaign",
+	"utm_term",
+	"utm_content",
+	"fbclid",
+}
+
+// ResolveLinks resolves the relative URLs referenced by the feed (see Links)
+// against base. Absolute URLs and URLs that cannot be parsed are left
+// unchanged.
+func (r *RSS) ResolveLinks(base *url.URL) {
+	r.rewriteLinks(func(s string) string {
+		u, err := url.Parse(s)
+		if err != nil || u.IsAbs() {
+			return s
+		}
+		return base.ResolveReference(u).String()
+	})
+}
+
+// StripQueryParams removes the query parameters named in params, or in
+// DefaultTrackingParams if params is empty, from the URLs referenced by the
+// feed (see Links). Other query parameters are kept.
+func (r *RSS) StripQueryParams(params []string) {
+	if len(params) == 0 {
+		params = DefaultTrackingParams
+	}
+	r.rewriteLinks(func(s string) string {
+		u, err := url.Parse(s)
+		if err != nil || u.RawQuery == "" {
+			return s
+		}
+		q := u.Query()
+		n := len(q)
+		for _, p := range params {
+			q.Del(p)
+		}
+		if len(q) == n {
+			return s
+		}
+		u.RawQuery = q.Encode()
+		return u.String()
+	})
+}
+
+// rewriteLinks replaces each non-empty URL referenced by the feed (see Links)
+// with the result of calling f on it.
+func (r *RSS) rewriteLinks(f func(string) string) {
+	rewrite := func(s string) string {
+		if s == "" {
+			return s
+		}
+		return f(s)
+	}
+	c := r.Channel
+	if c == nil {
+		return
+	}
+	c.Link = Link(rewrite(string(c.Link)))
+	c.Docs = Docs(rewrite(string(c.Docs)))
+	if c.Image != nil {
+		c.Image.URL = URL(rewrite(string(c.Image.URL)))
+		c.Image.Link = Link(rewrite(string(c.Image.Link)))
+	}
+	if c.TextInput != nil {
+		c.TextInput.Link = Link(rewrite(string(c.TextInput.Link)))
+	}
+	for _, item := range c.Item {
+		item.Link = Link(rewrite(string(item.Link)))
+		item.Comments = Comments(rewrite(string(item.Comments)))
+		if item.Enclosure != nil {
+			item.Enclosure.URL = URL(rewrite(string(item.Enclosure.URL)))
+		}
+		if item.Source != nil {
+			item.Source.URL = URL(rewrite(string(item.Source.URL)))
+		}
+		for _, mc := range item.MediaContent {
+			mc.URL = URL(rewrite(string(mc.URL)))
+		}
+		for _, mt := range item.MediaThumbnail {
+			mt.URL = URL(rewrite(string(mt.URL)))
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestLinks(t *testing.T) {
+	r := mustParseFile(t, "../../test/data/rss-0.xml")
+	want := []string{
+		"http://liftoff.msfc.nasa.gov/",
+		"http://blogs.law.harvard.edu/tech/rss",
+		"http://liftoff.msfc.nasa.gov/news/2003/news-starcity.asp",
+		"http://liftoff.msfc.nasa.gov/news/2003/news-laundry.asp",
+		"http://liftoff.msfc.nasa.gov/news/2003/news-VASIMR.asp",
+	}
+	if got := r.Links(); !equalStrings(got, want) {
+		t.Errorf("Links() = %v, want %v", got, want)
+	}
+}
+
+func TestLinksDeduplicated(t *testing.T) {
+	r := validFeed()
+	r.Channel.Image = &Image{URL: "http://a.com/logo.png", Link: "http://liftoff.msfc.nasa.gov/"}
+	r.Channel.Item = []*Item{
+		{
+			Link:           "http://a.com/1",
+			Enclosure:      &Enclosure{URL: "http://a.com/1.mp3"},
+			Source:         &Source{URL: "http://b.com/rss"},
+			MediaContent:   []*MediaContent{{URL: "http://a.com/1.jpg"}},
+			MediaThumbnail: []*MediaThumbnail{{URL: "http://a.com/1-thumb.jpg"}},
+		},
+		{
+			Link:      "http://a.com/2",
+			Comments:  "http://a.com/2#comments",
+			Enclosure: &Enclosure{URL: "http://a.com/1.mp3"},
+			Source:    &Source{URL: "http://b.com/rss"},
+		},
+	}
+	want := []string{
+		"http://liftoff.msfc.nasa.gov/",
+		"http://a.com/logo.png",
+		"http://a.com/1",
+		"http://a.com/1.mp3",
+		"http://b.com/rss",
+		"http://a.com/1.jpg",
+		"http://a.com/1-thumb.jpg",
+		"http://a.com/2",
+		"http://a.com/2#comments",
+	}
+	if got := r.Links(); !equalStrings(got, want) {
+		t.Errorf("Links() = %v, want %v", got, want)
+	}
+}
+
+func TestResolveLinks(t *testing.T) {
+	r := validFeed()
+	r.Channel.Link = "/"
+	r.Channel.Image = &Image{URL: "images/logo.png", Link: "https://other.com/"}
+	r.Channel.Item = []*Item{
+		{
+			Link:         "/article/1",
+			Comments:     "/article/1#comments",
+			Enclosure:    &Enclosure{URL: "//cdn.site.com/1.mp3"},
+			MediaContent: []*MediaContent{{URL: "1.jpg"}},
+		},
+	}
+	base, err := url.Parse("https://site.com/blog/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.ResolveLinks(base)
+	want := []string{
+		"https://site.com/",
+		"https://site.com/blog/images/logo.png",
+		"https://other.com/",
+		"https://site.com/article/1",
+		"https://site.com/article/1#comments",
+		"https://cdn.site.com/1.mp3",
+		"https://site.com/blog/1.jpg",
+	}
+	if got := r.Links(); !equalStrings(got, want) {
+		t.Errorf("Links() = %v, want %v", got, want)
+	}
+}
+
+func TestStripQueryParams(t *testing.T) {
+	r := validFeed()
+	r.Channel.Link = "http://liftoff.msfc.nasa.gov/?utm_source=rss&utm_medium=feed"
+	r.Channel.Image = &Image{URL: "http://a.com/logo.png?fbclid=abc&size=large", Link: "http://a.com/"}
+	r.Channel.Item = []*Item{
+		{
+			Link:      "http://a.com/article?id=1&utm_campaign=spring#comments",
+			Enclosure: &Enclosure{URL: "http://a.com/1.mp3?token=xyz"},
+		},
+	}
+	r.StripQueryParams(nil)
+	want := []string{
+		"http://liftoff.msfc.nasa.gov/",
+		"http://a.com/logo.png?size=large",
+		"http://a.com/",
+		"http://a.com/article?id=1#comments",
+		"http://a.com/1.mp3?token=xyz",
+	}
+	if got := r.Links(); !equalStrings(got, want) {
+		t.Errorf("Links() = %v, want %v", got, want)
+	}
+
+	r.StripQueryParams([]string{"token"})
+	if got := r.Channel.Item[0].Enclosure.URL; got != "http://a.com/1.mp3" {
+		t.Errorf("Enclosure.URL = %q, want %q", got, "http://a.com/1.mp3")
+	}
+}
@@ -0,0 +1,273 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// DefaultFutureDateThreshold is the default of LintOptions.FutureDateThreshold.
+const DefaultFutureDateThreshold = 24 * time.Hour
+
+// MaxDescriptionLength is the length, in characters, above which a
+// description is reported by the RuleDescriptionLength lint rule.
+const MaxDescriptionLength = 4000
+
+// The IDs of the lint rules. The rules missing-guid and description-length
+// are a subset of the recommendations of the W3C Feed Validation Service.
+const (
+	// RuleUnknownElement reports elements not defined by the specification
+	// (ErrUnknownElement).
+	RuleUnknownElement = "unknown-element"
+	// RuleDuplicateGUID reports items with the same guid as an earlier item
+	// (ErrDuplicateGUID).
+	RuleDuplicateGUID = "duplicate-guid"
+	// RuleDuplicateEnclosure reports items with the same enclosure URL as
+	// an earlier item (ErrDuplicateEnclosure).
+	RuleDuplicateEnclosure = "duplicate-enclosure"
+	// RuleFutureDate reports items dated in the future (ErrFutureDate).
+	RuleFutureDate = "future-date"
+	// RuleEncodingMismatch reports documents whose content is in a
+	// different encoding than the one declared in their XML declaration
+	// (ErrEncodingMismatch).
+	RuleEncodingMismatch = "encoding-mismatch"
+	// RuleMissingGUID reports items without a guid (ErrMissingGUID).
+	RuleMissingGUID = "missing-guid"
+	// RulePermalinkGUID reports items whose guid is declared a permalink
+	// with isPermaLink="true", but is not an absolute URL
+	// (ErrPermalinkNotURL) or is on a different host than the link of the
+	// item (ErrPermalinkMismatch).
+	RulePermalinkGUID = "permalink-guid"
+	// RuleMissingEnclosure reports, if LintOptions.Podcast is set, items
+	// without a valid enclosure (ErrMissingEnclosure or ErrInvalidEnclosure).
+	RuleMissingEnclosure = "missing-enclosure"
+	// RuleDescriptionLength reports descriptions longer than
+	// MaxDescriptionLength (ErrDescriptionTooLong).
+	RuleDescriptionLength = "description-length"
+)
+
+// LintRules returns the IDs of the lint rules, in the order in which they are
+// checked.
+func LintRules() []string {
+	return []string{RuleUnknownElement, RuleDuplicateGUID, RuleDuplicateEnclosure, RuleFutureDate, RuleEncodingMismatch, RuleMissingGUID, RulePermalinkGUID, RuleMissingEnclosure, RuleDescriptionLength}
+}
+
+// IsLintRule reports whether id is the ID of a lint rule.
+func IsLintRule(id string) bool {
+	for _, rule := range LintRules() {
+		if rule == id {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRecommendation reports whether id is the ID of a lint rule checking a
+// recommendation rather than a likely problem: RuleMissingGUID and
+// RuleDescriptionLength, which are W3C Feed Validation Service
+// recommendations. Strict consumers of Lint should not treat their warnings
+// as errors.
+func IsRecommendation(id string) bool {
+	return id == RuleMissingGUID || id == RuleDescriptionLength
+}
+
+// LintOptions configures the checks of Lint.
+type LintOptions struct {
+	// FutureDateThreshold is how far in the future an item may be dated
+	// before it is reported with ErrFutureDate. If zero,
+	// DefaultFutureDateThreshold is used. A negative value disables the
+	// check.
+	FutureDateThreshold time.Duration
+	// DisableRules are the IDs of the lint rules (e.g. RuleMissingGUID) that
+	// are not checked.
+	DisableRules []string
+	// Podcast also checks the RuleMissingEnclosure rule, as each item of a
+	// podcast is expected to have an episode attached.
+	Podcast bool
+}
+
+// Lint lints r using the default options (see LintOptions.Lint).
+func (r *RSS) Lint() ValidationErrors {
+	return LintOptions{}.Lint(r)
+}
+
+// Lint checks r for problems that do not make it invalid, but that are likely
+// to cause problems in feed readers. Each warning wraps one of the sentinel
+// errors of this package, its path is as in ValidationError and its Rule is
+// the ID of the rule reporting it.
+func (o LintOptions) Lint(r *RSS) ValidationErrors {
+	var v validator
+	if r.Channel == nil {
+		return nil
+	}
+	disabled := make(map[string]bool)
+	for _, id := range o.DisableRules {
+		disabled[id] = true
+	}
+	check := func(rule string, fn func(*Channel)) {
+		if disabled[rule] {
+			return
+		}
+		n := len(v.errs)
+		fn(r.Channel)
+		for _, e := range v.errs[n:] {
+			e.Rule = rule
+		}
+	}
+	check(RuleUnknownElement, v.unknownElements)
+	check(RuleDuplicateGUID, v.duplicateGUIDs)
+	check(RuleDuplicateEnclosure, v.duplicateEnclosures)
+	threshold := o.FutureDateThreshold
+	if threshold == 0 {
+		threshold = DefaultFutureDateThreshold
+	}
+	if threshold > 0 {
+		limit := time.Now().Add(threshold)
+		check(RuleFutureDate, func(c *Channel) { v.futureDates(c, limit) })
+	}
+	check(RuleEncodingMismatch, func(*Channel) { v.encodingMismatch(r.encoding) })
+	check(RuleMissingGUID, v.missingGUIDs)
+	check(RulePermalinkGUID, v.permalinkGUIDs)
+	if o.Podcast {
+		check(RuleMissingEnclosure, v.missingEnclosures)
+	}
+	check(RuleDescriptionLength, v.descriptionLengths)
+	return v.errs
+}
+
+// unknownElements reports the child elements of the channel and its items
+// that are in the namespace of their parent, and so are not module elements,
+// but are not defined by the specification (e.g. a misspelled <titel>).
+func (v *validator) unknownElements(c *Channel) {
+	for _, x := range c.Extensions {
+		if x.XMLName.Space == c.XMLName.Space {
+			v.add(x.XMLName.Local, ErrUnknownElement)
+		}
+	}
+	for i, item := range c.Item {
+		for _, x := range item.Extensions {
+			if x.XMLName.Space == item.XMLName.Space {
+				v.add(fmt.Sprintf("item[%d].%s", i, x.XMLName.Local), ErrUnknownElement)
+			}
+		}
+	}
+}
+
+// duplicateGUIDs reports every item with the same guid as an earlier item.
+func (v *validator) duplicateGUIDs(c *Channel) {
+	first := make(map[string]int)
+	for i, item := range c.Item {
+		if item.GUID == nil || item.GUID.Value == "" {
+			continue
+		}
+		if j, ok := first[item.GUID.Value]; ok {
+			v.add(fmt.Sprintf("item[%d].guid", i), fmt.Errorf("%w (same as item[%d])", ErrDuplicateGUID, j))
+			continue
+		}
+		first[item.GUID.Value] = i
+	}
+}
+
+// duplicateEnclosures reports every item with the same enclosure URL as an
+// earlier item, which is often a copy-paste error in the feed.
+func (v *validator) duplicateEnclosures(c *Channel) {
+	first := make(map[URL]int)
+	for i, item := range c.Item {
+		if item.Enclosure == nil || item.Enclosure.URL == "" {
+			continue
+		}
+		if j, ok := first[item.Enclosure.URL]; ok {
+			v.add(fmt.Sprintf("item[%d].enclosure.url", i), fmt.Errorf("%w (same as item[%d])", ErrDuplicateEnclosure, j))
+			continue
+		}
+		first[item.Enclosure.URL] = i
+	}
+}
+
+// futureDates reports every item published after limit.
+func (v *validator) futureDates(c *Channel, limit time.Time) {
+	for i, item := range c.Item {
+		if t, err := item.PubDate.Time(); err == nil && t.After(limit) {
+			v.add(fmt.Sprintf("item[%d].pubDate", i), fmt.Errorf("%w: %s", ErrFutureDate, item.PubDate))
+		}
+	}
+}
+
+// encodingMismatch reports a document, as parsed, whose detected encoding
+// differs from its declared encoding: e.g. a document declaring ISO-8859-1
+// whose content is UTF-8, which is decoded into mojibake.
+func (v *validator) encodingMismatch(e docEncoding) {
+	if !e.mismatch() {
+		return
+	}
+	v.add("encoding", fmt.Errorf("%w: declared %s, detected %s", ErrEncodingMismatch, e.declared, e.detected))
+}
+
+// missingGUIDs reports every item without a guid, which feed readers need to
+// tell whether an item is new.
+func (v *validator) missingGUIDs(c *Channel) {
+	for i, item := range c.Item {
+		if item.GUID == nil || item.GUID.Value == "" {
+			v.add(fmt.Sprintf("item[%d]", i), ErrMissingGUID)
+		}
+	}
+}
+
+// permalinkGUIDs reports every item whose guid is explicitly declared a
+// permalink, but is not an absolute URL, or is on a different host than the
+// link of the item, ignoring a "www." prefix. A guid without an isPermaLink
+// attribute is also a permalink, but is not reported, as many feeds omit the
+// attribute from guids that are not URLs.
+func (v *validator) permalinkGUIDs(c *Channel) {
+	for i, item := range c.Item {
+		g := item.GUID
+		if g == nil || g.Value == "" || !strings.EqualFold(strings.TrimSpace(g.IsPermaLink), "true") {
+			continue
+		}
+		path := fmt.Sprintf("item[%d].guid", i)
+		if !IsValidURL(g.Value) {
+			v.add(path, fmt.Errorf("%w: %q", ErrPermalinkNotURL, g.Value))
+			continue
+		}
+		if !item.Link.IsValid() {
+			continue
+		}
+		if gh, lh := permalinkHost(g.Value), permalinkHost(string(item.Link)); gh != lh {
+			v.add(path, fmt.Errorf("%w: host %s, link host %s", ErrPermalinkMismatch, gh, lh))
+		}
+	}
+}
+
+// permalinkHost returns the host of the absolute URL s, in lower case and
+// without a "www." prefix.
+func permalinkHost(s string) string {
+	u, err := url.Parse(s)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+// descriptionLengths reports the descriptions of the channel and its items
+// that are longer than MaxDescriptionLength.
+func (v *validator) descriptionLengths(c *Channel) {
+	tooLong := func(d Description) error {
+		if n := utf8.RuneCountInString(string(d)); n > MaxDescriptionLength {
+			return fmt.Errorf("%w: %d characters", ErrDescriptionTooLong, n)
+		}
+		return nil
+	}
+	if err := tooLong(c.Description); err != nil {
+		v.add("description", err)
+	}
+	for i, item := range c.Item {
+		if err := tooLong(item.Description); err != nil {
+			v.add(fmt.Sprintf("item[%d].description", i), err)
+		}
+	}
+}
@@ -0,0 +1,268 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLint(t *testing.T) {
+	r := mustParseFile(t, "../../test/data/rss-0.xml")
+	if w := r.Lint(); len(w) != 0 {
+		t.Errorf("Lint() = %v, want no warnings", w)
+	}
+}
+
+func TestLintUnknownElements(t *testing.T) {
+	const doc = `<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom"><channel>
+<title>Example</title><descripton>typo</descripton>
+<atom:link href="http://example.com/feed.xml" rel="self"/>
+<item><title>a</title><guid>1</guid><auther>typo</auther></item>
+</channel></rss>`
+	r, err := Parse(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := r.Lint()
+	var got []string
+	for _, e := range w {
+		if !errors.Is(e, ErrUnknownElement) {
+			t.Errorf("%v: want ErrUnknownElement", e)
+		}
+		got = append(got, e.Path)
+	}
+	if want := []string{"descripton", "item[0].auther"}; !equalStrings(got, want) {
+		t.Errorf("Lint() paths = %v, want %v", got, want)
+	}
+}
+
+func TestLintDuplicateGUIDs(t *testing.T) {
+	r := validFeed()
+	r.Channel.Item = []*Item{
+		{Title: "a", GUID: &GUID{Value: "1"}},
+		{Title: "b", GUID: &GUID{Value: "2"}},
+		{Title: "c", GUID: &GUID{Value: "1"}},
+		{Title: "d", GUID: &GUID{Value: "4"}},
+		{Title: "e", GUID: &GUID{Value: "5"}},
+	}
+	w := r.Lint()
+	if len(w) != 1 {
+		t.Fatalf("Lint() = %v, want one warning", w)
+	}
+	if !errors.Is(w[0], ErrDuplicateGUID) {
+		t.Errorf("Lint()[0] = %v, want ErrDuplicateGUID", w[0])
+	}
+	if want := "item[2].guid: duplicate guid (same as item[0])"; w[0].Error() != want {
+		t.Errorf("Lint()[0] = %q, want %q", w[0].Error(), want)
+	}
+	if err := r.Validate(); err != nil {
+		t.Errorf("Validate() = %v, want duplicate guids to be valid", err)
+	}
+}
+
+func TestLintDuplicateEnclosures(t *testing.T) {
+	const mp3 = "http://example.com/episode.mp3"
+	r := validFeed()
+	r.Channel.Item = []*Item{
+		{Title: "a", GUID: &GUID{Value: "1"}, Enclosure: &Enclosure{URL: mp3, Length: "1", Type: "audio/mpeg"}},
+		{Title: "b", GUID: &GUID{Value: "2"}, Enclosure: &Enclosure{URL: "http://example.com/other.mp3", Length: "1", Type: "audio/mpeg"}},
+		{Title: "c", GUID: &GUID{Value: "3"}, Enclosure: &Enclosure{URL: mp3, Length: "1", Type: "audio/mpeg"}},
+		{Title: "d", GUID: &GUID{Value: "4"}},
+	}
+	w := r.Lint()
+	if len(w) != 1 {
+		t.Fatalf("Lint() = %v, want one warning", w)
+	}
+	if !errors.Is(w[0], ErrDuplicateEnclosure) || w[0].Rule != RuleDuplicateEnclosure {
+		t.Errorf("Lint()[0] = %v (rule %q), want ErrDuplicateEnclosure", w[0], w[0].Rule)
+	}
+	if want := "item[2].enclosure.url: duplicate enclosure URL (same as item[0])"; w[0].Error() != want {
+		t.Errorf("Lint()[0] = %q, want %q", w[0].Error(), want)
+	}
+	if w := (LintOptions{DisableRules: []string{RuleDuplicateEnclosure}}).Lint(r); len(w) != 0 {
+		t.Errorf("Lint() with %s disabled = %v, want no warnings", RuleDuplicateEnclosure, w)
+	}
+}
+
+func TestLintPermalinkGUIDs(t *testing.T) {
+	const link = "http://example.com/posts/1"
+	tests := []struct {
+		name string
+		link Link
+		guid GUID
+		want error
+		msg  string
+	}{
+		{"matching", link, GUID{IsPermaLink: "true", Value: link}, nil, ""},
+		{"same host", link, GUID{IsPermaLink: "true", Value: "https://www.Example.com/?p=1"}, nil, ""},
+		{"no link", "", GUID{IsPermaLink: "true", Value: link}, nil, ""},
+		{"not a permalink", link, GUID{IsPermaLink: "false", Value: "post-1"}, nil, ""},
+		{"implicit permalink", link, GUID{Value: "post-1"}, nil, ""},
+		{"not a URL", link, GUID{IsPermaLink: "TRUE", Value: "post-1"}, ErrPermalinkNotURL,
+			`item[0].guid: permalink guid is not a URL: "post-1"`},
+		{"relative URL", link, GUID{IsPermaLink: "true", Value: "/posts/1"}, ErrPermalinkNotURL,
+			`item[0].guid: permalink guid is not a URL: "/posts/1"`},
+		{"different host", link, GUID{IsPermaLink: "true", Value: "http://feeds.example.net/1"}, ErrPermalinkMismatch,
+			"item[0].guid: permalink guid does not match link: host feeds.example.net, link host example.com"},
+	}
+	for _, tt := range tests {
+		r := validFeed()
+		guid := tt.guid
+		r.Channel.Item = []*Item{{Title: "a", Link: tt.link, GUID: &guid}}
+		w := r.Lint()
+		if tt.want == nil {
+			if len(w) != 0 {
+				t.Errorf("%s: Lint() = %v, want no warnings", tt.name, w)
+			}
+			continue
+		}
+		if len(w) != 1 || !errors.Is(w[0], tt.want) || w[0].Rule != RulePermalinkGUID {
+			t.Errorf("%s: Lint() = %v, want %v", tt.name, w, tt.want)
+			continue
+		}
+		if w[0].Error() != tt.msg {
+			t.Errorf("%s: Lint()[0] = %q, want %q", tt.name, w[0].Error(), tt.msg)
+		}
+	}
+}
+
+func TestLintFutureDates(t *testing.T) {
+	now := time.Now()
+	r := validFeed()
+	r.Channel.Item = []*Item{
+		{Title: "now", GUID: &GUID{Value: "1"}, PubDate: PubDate(now.Format(time.RFC1123Z))},
+		{Title: "in an hour", GUID: &GUID{Value: "2"}, PubDate: PubDate(now.Add(time.Hour).Format(time.RFC1123Z))},
+		{Title: "in two days", GUID: &GUID{Value: "3"}, PubDate: PubDate(now.Add(48 * time.Hour).Format(time.RFC1123Z))},
+		{Title: "undated", GUID: &GUID{Value: "4"}},
+	}
+	tests := []struct {
+		threshold time.Duration
+		want      []string
+	}{
+		{0, []string{"item[2].pubDate"}},
+		{30 * time.Minute, []string{"item[1].pubDate", "item[2].pubDate"}},
+		{72 * time.Hour, nil},
+		{-1, nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, e := range (LintOptions{FutureDateThreshold: tt.threshold}).Lint(r) {
+			if !errors.Is(e, ErrFutureDate) {
+				t.Errorf("%v: want ErrFutureDate", e)
+			}
+			got = append(got, e.Path)
+		}
+		if !equalStrings(got, tt.want) {
+			t.Errorf("FutureDateThreshold = %v: Lint() paths = %v, want %v", tt.threshold, got, tt.want)
+		}
+	}
+}
+
+func TestLintRules(t *testing.T) {
+	r := validFeed()
+	r.Channel.Description = Description(strings.Repeat("x", MaxDescriptionLength+1))
+	r.Channel.Item = []*Item{
+		{Title: "a", GUID: &GUID{Value: "1"}, Description: Description(strings.Repeat("é", MaxDescriptionLength))},
+		{Title: "b", GUID: &GUID{Value: "1"}},
+		{Title: "c", Description: Description(strings.Repeat("x", MaxDescriptionLength+1))},
+		{Title: "d", PubDate: PubDate(time.Now().Add(48 * time.Hour).Format(time.RFC1123Z))},
+	}
+	tests := []struct {
+		disable []string
+		want    []string
+	}{
+		{nil, []string{
+			"duplicate-guid item[1].guid",
+			"future-date item[3].pubDate",
+			"missing-guid item[2]",
+			"missing-guid item[3]",
+			"description-length description",
+			"description-length item[2].description",
+		}},
+		{[]string{RuleMissingGUID}, []string{
+			"duplicate-guid item[1].guid",
+			"future-date item[3].pubDate",
+			"description-length description",
+			"description-length item[2].description",
+		}},
+		{[]string{RuleDescriptionLength, RuleDuplicateGUID}, []string{
+			"future-date item[3].pubDate",
+			"missing-guid item[2]",
+			"missing-guid item[3]",
+		}},
+		{LintRules(), nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, e := range (LintOptions{DisableRules: tt.disable}).Lint(r) {
+			got = append(got, e.Rule+" "+e.Path)
+		}
+		if !equalStrings(got, tt.want) {
+			t.Errorf("DisableRules = %v: Lint() = %v, want %v", tt.disable, got, tt.want)
+		}
+	}
+}
+
+func TestIsRecommendation(t *testing.T) {
+	for _, rule := range LintRules() {
+		want := rule == RuleMissingGUID || rule == RuleDescriptionLength
+		if got := IsRecommendation(rule); got != want {
+			t.Errorf("IsRecommendation(%q) = %v, want %v", rule, got, want)
+		}
+	}
+}
+
+func TestIsLintRule(t *testing.T) {
+	tests := []struct {
+		id   string
+		want bool
+	}{
+		{RuleMissingGUID, true},
+		{"description-length", true},
+		{"missing-guids", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsLintRule(tt.id); got != tt.want {
+			t.Errorf("IsLintRule(%q) = %v, want %v", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestLintEncodingMismatch(t *testing.T) {
+	const body = `<rss version="2.0"><channel><title>%s</title><item><title>a</title><guid>1</guid></item></channel></rss>`
+	tests := []struct {
+		name string
+		doc  string
+		want string
+	}{
+		{"UTF-8 declared ISO-8859-1", `<?xml version="1.0" encoding="ISO-8859-1"?>` + fmt.Sprintf(body, "caf\u00e9"), "encoding: encoding mismatch: declared ISO-8859-1, detected UTF-8"},
+		{"BOM declared windows-1252", "\ufeff" + `<?xml version="1.0" encoding="windows-1252"?>` + fmt.Sprintf(body, "cafe"), "encoding: encoding mismatch: declared windows-1252, detected UTF-8"},
+		{"ISO-8859-1", `<?xml version="1.0" encoding="ISO-8859-1"?>` + fmt.Sprintf(body, "caf\xe9"), ""},
+		{"ASCII declared latin1", `<?xml version="1.0" encoding="latin1"?>` + fmt.Sprintf(body, "cafe"), ""},
+		{"UTF-8", `<?xml version="1.0" encoding="utf-8"?>` + fmt.Sprintf(body, "caf\u00e9"), ""},
+		{"BOM undeclared", "\ufeff" + fmt.Sprintf(body, "caf\u00e9"), ""},
+	}
+	for _, tt := range tests {
+		r, err := Parse(strings.NewReader(tt.doc))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		var got string
+		if w := r.Lint(); len(w) > 0 {
+			got = w.Error()
+			if !errors.Is(w, ErrEncodingMismatch) || w[0].Rule != RuleEncodingMismatch {
+				t.Errorf("%s: Lint() = %v, want ErrEncodingMismatch", tt.name, w)
+			}
+		}
+		if got != tt.want {
+			t.Errorf("%s: Lint() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// localizedNames are the month and day names, and their common
+// abbreviations, of languages in which non-English feeds write dates. Names
+// that are also English (e.g. "april" or "mar") are omitted, as are
+// two-letter abbreviations, which are too ambiguous to be recognized.
+var localizedNames = []struct {
+	language string
+	months   string
+	days     string
+}{
+	{
+		"French",
+		"janvier février fevrier mars avril mai juin juillet août aout septembre octobre novembre décembre decembre janv févr fév avr juil déc",
+		"lundi mardi mercredi jeudi vendredi samedi dimanche lun mer jeu ven sam dim",
+	},
+	{
+		"German",
+		"januar jänner februar märz maerz mai juni juli oktober dezember jän mär okt dez",
+		"montag dienstag mittwoch donnerstag freitag samstag sonntag",
+	},
+	{
+		"Spanish",
+		"enero febrero marzo abril mayo junio julio agosto septiembre setiembre octubre noviembre diciembre ene abr ago dic",
+		"lunes martes miércoles miercoles jueves viernes sábado sabado domingo lun mié jue vie sáb dom",
+	},
+	{
+		"Italian",
+		"gennaio febbraio marzo aprile maggio giugno luglio agosto settembre ottobre novembre dicembre gen mag giu lug set ott dic",
+		"lunedì martedì mercoledì giovedì venerdì sabato domenica gio",
+	},
+	{
+		"Portuguese",
+		"janeiro fevereiro março abril maio junho julho agosto setembro outubro novembro dezembro fev abr mai ago set out dez",
+		"segunda terça quarta quinta sexta sábado domingo seg ter qua qui sex sáb dom",
+	},
+	{
+		"Dutch",
+		"januari februari maart mei juni juli augustus oktober mrt okt",
+		"maandag dinsdag woensdag donderdag vrijdag zaterdag zondag",
+	},
+}
+
+// localizedName describes a localized month or day name.
+type localizedName struct {
+	// kind is "month" or "day".
+	kind string
+	// languages are the languages using the name.
+	languages []string
+}
+
+// localizedNameIndex maps the localized month and day names of
+// localizedNames, in lower case, to their description.
+var localizedNameIndex = indexLocalizedNames()
+
+func indexLocalizedNames() map[string]*localizedName {
+	english := make(map[string]bool)
+	for m := time.January; m <= time.December; m++ {
+		name := strings.ToLower(m.String())
+		english[name], english[name[:3]] = true, true
+	}
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		english[name], english[name[:3]] = true, true
+	}
+	index := make(map[string]*localizedName)
+	add := func(names, kind, language string) {
+		for _, name := range strings.Fields(names) {
+			if english[name] {
+				continue
+			}
+			n, ok := index[name]
+			if !ok {
+				n = &localizedName{kind: kind}
+				index[name] = n
+			}
+			n.languages = append(n.languages, language)
+		}
+	}
+	for _, l := range localizedNames {
+		add(l.months, "month", l.language)
+		add(l.days, "day", l.language)
+	}
+	return index
+}
+
+// findLocalizedName returns the first word of the date s that is a localized
+// month or day name, along with its description, or "" and nil if there is
+// none.
+func findLocalizedName(s string) (string, *localizedName) {
+	words := strings.FieldsFunc(s, func(r rune) bool { return !unicode.IsLetter(r) })
+	for _, w := range words {
+		if n, ok := localizedNameIndex[strings.ToLower(w)]; ok {
+			return w, n
+		}
+	}
+	return "", nil
+}
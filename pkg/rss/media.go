@@ -0,0 +1,70 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"encoding/xml"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// MediaNamespace is the namespace of the Media RSS module.
+//
+// See: https://www.rssboard.org/media-rss
+const MediaNamespace = "http://search.yahoo.com/mrss/"
+
+// MediaContent is a media object attached to an item (<media:content>).
+type MediaContent struct {
+	XMLName xml.Name `xml:"http://search.yahoo.com/mrss/ content"`
+	URL     URL      `xml:"url,attr,omitempty"`
+	Type    string   `xml:"type,attr,omitempty"`
+	Medium  string   `xml:"medium,attr,omitempty"`
+	Width   string   `xml:"width,attr,omitempty"`
+	Height  string   `xml:"height,attr,omitempty"`
+}
+
+// MediaThumbnail is an image representing an item (<media:thumbnail>).
+type MediaThumbnail struct {
+	XMLName xml.Name `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+	URL     URL      `xml:"url,attr"`
+	Width   string   `xml:"width,attr,omitempty"`
+	Height  string   `xml:"height,attr,omitempty"`
+	Time    string   `xml:"time,attr,omitempty"`
+}
+
+// Thumbnail returns the URL of an image representing the item: its first
+// <media:thumbnail>, else its first <media:content> or its enclosure that is
+// an image, else the URL of the image of c, which may be nil. It returns "" if
+// there is none.
+func (i *Item) Thumbnail(c *Channel) string {
+	for _, t := range i.MediaThumbnail {
+		if t.URL != "" {
+			return string(t.URL)
+		}
+	}
+	for _, m := range i.MediaContent {
+		if m.URL != "" && (m.Medium == "image" || isImage(m.Type, m.URL)) {
+			return string(m.URL)
+		}
+	}
+	if e := i.Enclosure; e != nil && e.URL != "" && isImage(e.Type, e.URL) {
+		return string(e.URL)
+	}
+	if c != nil && c.Image != nil {
+		return string(c.Image.URL)
+	}
+	return ""
+}
+
+// isImage reports whether a media object of MIME type typ at u is an image:
+// whether its type is image/*, or, if it has no type, whether u has the
+// extension of a GIF, JPEG or PNG image.
+func isImage(typ string, u URL) bool {
+	if typ != "" {
+		return strings.HasPrefix(strings.ToLower(typ), "image/")
+	}
+	p, err := url.Parse(string(u))
+	return err == nil && imageExtensions[strings.ToLower(path.Ext(p.Path))]
+}
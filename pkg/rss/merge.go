@@ -0,0 +1,127 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+)
+
+// DedupStrategy is the strategy by which Merge identifies duplicate items.
+type DedupStrategy string
+
+const (
+	// DedupGUIDOrLink identifies items by their guid, or by their link if
+	// they have no guid.
+	DedupGUIDOrLink DedupStrategy = "guid-or-link"
+	// DedupGUID identifies items by their guid. Items without a guid are
+	// never duplicates.
+	DedupGUID DedupStrategy = "guid"
+	// DedupLink identifies items by their link. Items without a link are
+	// never duplicates.
+	DedupLink DedupStrategy = "link"
+	// DedupTitleLink identifies items by their title and link, for feeds
+	// whose items have no guid and share links. Items with neither are never
+	// duplicates.
+	DedupTitleLink DedupStrategy = "title+link"
+)
+
+// IsValid returns true if the strategy is one of the DedupStrategy constants.
+func (s DedupStrategy) IsValid() bool {
+	switch s {
+	case DedupGUIDOrLink, DedupGUID, DedupLink, DedupTitleLink:
+		return true
+	}
+	return false
+}
+
+// Key returns the key identifying item under the strategy, or "" if the item
+// has none.
+func (s DedupStrategy) Key(item *Item) string {
+	var guid string
+	if item.GUID != nil {
+		guid = item.GUID.Value
+	}
+	switch s {
+	case DedupGUID:
+		return guid
+	case DedupLink:
+		return string(item.Link)
+	case DedupTitleLink:
+		if item.Title == "" && item.Link == "" {
+			return ""
+		}
+		return string(item.Title) + "\x00" + string(item.Link)
+	}
+	if guid != "" {
+		return "guid:" + guid
+	}
+	if item.Link != "" {
+		return "link:" + string(item.Link)
+	}
+	return ""
+}
+
+// MergeOptions configures the merging of feeds.
+type MergeOptions struct {
+	// Dedup is the strategy by which duplicate items are identified. If
+	// empty, DedupGUIDOrLink is used.
+	Dedup DedupStrategy
+}
+
+// Merge merges feeds using the default options (see MergeOptions.Merge).
+func Merge(feeds ...*RSS) (*RSS, error) {
+	return MergeOptions{}.Merge(feeds...)
+}
+
+// Merge returns a feed with the channel of the first feed and the items of
+// all feeds, in order, omitting items identified as duplicates of an earlier
+// item (see MergeOptions.Dedup). Namespaces declared by the other feeds are
+// also declared by the merged feed. The feeds are not modified, but their
+// items are shared with the merged feed.
+func (o MergeOptions) Merge(feeds ...*RSS) (*RSS, error) {
+	dedup := o.Dedup
+	if dedup == "" {
+		dedup = DedupGUIDOrLink
+	}
+	if !dedup.IsValid() {
+		return nil, fmt.Errorf("invalid dedup strategy %q", o.Dedup)
+	}
+	if len(feeds) == 0 || feeds[0].Channel == nil {
+		return nil, errors.New("no channel to merge into")
+	}
+	r := *feeds[0]
+	c := *r.Channel
+	r.Channel = &c
+	r.Namespaces = append([]xml.Attr(nil), r.Namespaces...)
+	c.Item = nil
+	seen := make(map[string]bool)
+	for _, f := range feeds {
+		for _, ns := range f.Namespaces {
+			if !hasNamespace(r.Namespaces, ns) {
+				r.Namespaces = append(r.Namespaces, ns)
+			}
+		}
+		for _, item := range items(f) {
+			key := dedup.Key(item)
+			if key != "" && seen[key] {
+				continue
+			}
+			seen[key] = true
+			c.Item = append(c.Item, item)
+		}
+	}
+	return &r, nil
+}
+
+// hasNamespace returns true if the namespace declaration ns is in decls.
+func hasNamespace(decls []xml.Attr, ns xml.Attr) bool {
+	for _, d := range decls {
+		if d.Name == ns.Name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	a := mustParse(t, `<rss version="2.0"><channel><title>A</title>
+<item><title>one</title><guid>1</guid><link>http://example.com/1</link></item>
+<item><title>two</title><link>http://example.com/2</link></item>
+<item><title>three</title><link>http://example.com/3</link></item>
+</channel></rss>`)
+	b := mustParse(t, `<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/"><channel><title>B</title>
+<item><title>one again</title><guid>1</guid><link>http://example.com/1b</link></item>
+<item><title>two</title><link>http://example.com/2</link></item>
+<item><title>three updated</title><link>http://example.com/3</link></item>
+<item><title>untitled</title></item>
+</channel></rss>`)
+	tests := []struct {
+		dedup DedupStrategy
+		want  []Title
+	}{
+		{"", []Title{"one", "two", "three", "untitled"}},
+		{DedupGUIDOrLink, []Title{"one", "two", "three", "untitled"}},
+		{DedupGUID, []Title{"one", "two", "three", "two", "three updated", "untitled"}},
+		{DedupLink, []Title{"one", "two", "three", "one again", "untitled"}},
+		{DedupTitleLink, []Title{"one", "two", "three", "one again", "three updated", "untitled"}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.dedup), func(t *testing.T) {
+			r, err := MergeOptions{Dedup: tt.dedup}.Merge(a, b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []Title
+			for _, item := range r.Channel.Item {
+				got = append(got, item.Title)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("titles = %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("titles = %q, want %q", got, tt.want)
+				}
+			}
+			if r.Channel.Title != "A" {
+				t.Errorf("Channel.Title = %q, want %q", r.Channel.Title, "A")
+			}
+			if len(r.Namespaces) != 1 || r.Namespaces[0].Value != "http://purl.org/dc/elements/1.1/" {
+				t.Errorf("Namespaces = %v, want the dc namespace", r.Namespaces)
+			}
+		})
+	}
+	if len(a.Channel.Item) != 3 || len(a.Namespaces) != 0 {
+		t.Errorf("Merge modified its first feed")
+	}
+}
+
+func TestMergeInvalid(t *testing.T) {
+	if _, err := (MergeOptions{Dedup: "title"}).Merge(validFeed()); err == nil {
+		t.Error("Merge() with an invalid strategy: expected error")
+	}
+	if _, err := Merge(); err == nil {
+		t.Error("Merge() of no feeds: expected error")
+	}
+}
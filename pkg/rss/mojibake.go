@@ -0,0 +1,65 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// FixMojibake repairs text that was encoded as UTF-8 and then decoded as
+// Windows-1252 or ISO 8859-1, such as "cafÃ©" for "café" or "itâ€™s" for
+// "it’s". Each run of non-ASCII characters is encoded back to Windows-1252
+// and, if the result is valid UTF-8, replaced by its decoding. Other text is
+// left as is.
+//
+// The repair is heuristic: text that legitimately contains such runs (e.g.
+// "Ã©" written on purpose) is changed too.
+func FixMojibake(s string) string {
+	var b strings.Builder
+	start := -1 // start of the current run of non-ASCII characters
+	flush := func(end int) {
+		if start >= 0 {
+			b.WriteString(fixMojibakeRun(s[start:end]))
+			start = -1
+		}
+	}
+	for i, r := range s {
+		if r < utf8.RuneSelf {
+			flush(i)
+			b.WriteRune(r)
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	flush(len(s))
+	return b.String()
+}
+
+// fixMojibakeRun returns the decoding of the run of non-ASCII characters s
+// encoded as Windows-1252, or s if it cannot be encoded or the result is not
+// valid UTF-8.
+func fixMojibakeRun(s string) string {
+	buf := make([]byte, 0, len(s))
+	for _, r := range s {
+		c, ok := charmap.Windows1252.EncodeRune(r)
+		if !ok {
+			// Windows-1252 leaves five bytes undefined, which ISO 8859-1
+			// decodes as C1 control characters.
+			if r > 0xff {
+				return s
+			}
+			c = byte(r)
+		}
+		buf = append(buf, c)
+	}
+	if !utf8.Valid(buf) {
+		return s
+	}
+	return string(buf)
+}
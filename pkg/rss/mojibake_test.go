@@ -0,0 +1,31 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import "testing"
+
+func TestFixMojibake(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		// Mojibake.
+		{"cafÃ©", "café"},
+		{"itâ€™s", "it’s"},
+		{"â€œquotedâ€\u009d", "“quoted”"},
+		{"naÃ¯ve cafÃ© â€” crÃ¨me brÃ»lÃ©e", "naïve café — crème brûlée"},
+		{"æ—¥æœ¬", "日本"},
+		// Clean text.
+		{"", ""},
+		{"plain ASCII", "plain ASCII"},
+		{"café", "café"},
+		{"it’s — “quoted”", "it’s — “quoted”"},
+		{"日本語", "日本語"},
+		{"© 2003 Liftoff", "© 2003 Liftoff"},
+	}
+	for _, tt := range tests {
+		if got := FixMojibake(tt.in); got != tt.want {
+			t.Errorf("FixMojibake(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import "golang.org/x/text/unicode/norm"
+
+// NormalizeText returns s in Unicode Normalization Form C (NFC), in which
+// characters are composed where possible: e.g. "e" followed by a combining
+// acute accent (U+0301) becomes "é" (U+00E9). Texts that render the same then
+// compare equal.
+func NormalizeText(s string) string {
+	return norm.NFC.String(s)
+}
@@ -0,0 +1,26 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import "testing"
+
+func TestNormalizeText(t *testing.T) {
+	tests := []struct {
+		nfd, nfc string
+	}{
+		{"Cafe\u0301", "Caf\u00e9"},
+		{"A\u030angstro\u0308m", "\u00c5ngstr\u00f6m"},
+		{"\u1100\u1161", "\uac00"},
+		{"plain", "plain"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeText(tt.nfd); got != tt.nfc {
+			t.Errorf("NormalizeText(%+q) = %+q, want %+q", tt.nfd, got, tt.nfc)
+		}
+		if got := NormalizeText(tt.nfc); got != tt.nfc {
+			t.Errorf("NormalizeText(%+q) = %+q, want it unchanged", tt.nfc, got)
+		}
+	}
+}
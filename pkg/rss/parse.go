@@ -0,0 +1,374 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// Parse reads an RSS document from r. If the document has no channel, Parse
+// returns an error wrapping ErrMissingChannel. A document compressed with
+// gzip, as detected by its magic number, is decompressed.
+func Parse(r io.Reader) (*RSS, error) {
+	b, err := readAll(r, -1)
+	if err != nil {
+		return nil, err
+	}
+	return parse(b, 0)
+}
+
+// ParseLimit reads an RSS document from r, reading at most maxBytes bytes. If
+// the document is larger than maxBytes, ParseLimit returns ErrFeedTooLarge.
+// The limit applies both to a document compressed with gzip and to the
+// decompressed document.
+func ParseLimit(r io.Reader, maxBytes int64) (*RSS, error) {
+	b, err := readAll(r, maxBytes)
+	if err != nil {
+		return nil, err
+	}
+	return parse(b, 0)
+}
+
+// ParseStrict is like Parse, but also rejects documents whose channel or
+// items have child elements that are not defined by the specification and
+// are not in the namespace of a module, which Parse keeps as extensions. This
+// catches misspelled elements, such as <titel>, which would otherwise be
+// silently ignored. The returned ValidationErrors wrap ErrUnknownElement.
+func ParseStrict(r io.Reader) (*RSS, error) {
+	rss, err := Parse(r)
+	if err != nil {
+		return nil, err
+	}
+	var v validator
+	v.unknownElements(rss.Channel)
+	if len(v.errs) > 0 {
+		return nil, v.errs
+	}
+	return rss, nil
+}
+
+// ParseAny reads an RSS 2.0, Atom or RSS 1.0 (RDF) document from r, detecting
+// the format from the name of the root element. Atom and RDF documents are
+// converted to RSS 2.0 (see ParseAtom and ParseRDF). If the root element is
+// not that of a supported format, ParseAny returns ErrUnsupportedFormat. As
+// with Parse, a document compressed with gzip is decompressed.
+func ParseAny(r io.Reader) (*RSS, error) {
+	b, err := readAll(r, -1)
+	if err != nil {
+		return nil, err
+	}
+	return parseAny(b, 0)
+}
+
+// ParseAnyLimit is like ParseAny, but reads at most maxBytes bytes. If the
+// document is larger than maxBytes, ParseAnyLimit returns ErrFeedTooLarge.
+func ParseAnyLimit(r io.Reader, maxBytes int64) (*RSS, error) {
+	b, err := readAll(r, maxBytes)
+	if err != nil {
+		return nil, err
+	}
+	return parseAny(b, 0)
+}
+
+// ParseOptions configures the parsing of a document.
+type ParseOptions struct {
+	// MaxBytes, if positive, is the maximum size of the document in bytes,
+	// both compressed with gzip and decompressed. A larger document is
+	// rejected with ErrFeedTooLarge.
+	MaxBytes int64
+	// MaxItems, if positive, is the maximum number of items (or entries,
+	// for an Atom feed) of the document. Decoding stops at the first item
+	// over the limit, which is rejected with ErrTooManyItems, so that the
+	// items of a misbehaving feed are not all decoded into memory.
+	MaxItems int
+}
+
+// Parse is like the package function Parse, but enforces the limits of o.
+func (o ParseOptions) Parse(r io.Reader) (*RSS, error) {
+	b, err := o.readAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return parse(b, o.MaxItems)
+}
+
+// ParseAny is like the package function ParseAny, but enforces the limits of
+// o.
+func (o ParseOptions) ParseAny(r io.Reader) (*RSS, error) {
+	b, err := o.readAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return parseAny(b, o.MaxItems)
+}
+
+// readAll reads the document in r, enforcing o.MaxBytes.
+func (o ParseOptions) readAll(r io.Reader) ([]byte, error) {
+	if o.MaxBytes > 0 {
+		return readAll(r, o.MaxBytes)
+	}
+	return readAll(r, -1)
+}
+
+// gzipMagic is the magic number at the start of gzip-compressed data.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// readAll reads the document in r until EOF, decompressing it if it is
+// compressed with gzip. If maxBytes is not negative, it returns
+// ErrFeedTooLarge if the document, compressed or not, is longer than
+// maxBytes.
+func readAll(r io.Reader, maxBytes int64) ([]byte, error) {
+	var (
+		b   []byte
+		err error
+	)
+	if maxBytes < 0 {
+		b, err = io.ReadAll(r)
+	} else {
+		b, err = readLimit(r, maxBytes)
+	}
+	if err != nil || !bytes.HasPrefix(b, gzipMagic) {
+		return b, err
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	if maxBytes < 0 {
+		return io.ReadAll(zr)
+	}
+	return readLimit(zr, maxBytes)
+}
+
+// readLimit reads r until EOF, returning ErrFeedTooLarge if it is longer
+// than maxBytes.
+func readLimit(r io.Reader, maxBytes int64) ([]byte, error) {
+	b, err := io.ReadAll(io.LimitReader(r, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(b)) > maxBytes {
+		return nil, ErrFeedTooLarge
+	}
+	return b, nil
+}
+
+// parseAny decodes the document in b using the parser for the format of its
+// root element, with at most maxItems items if it is positive.
+func parseAny(b []byte, maxItems int) (*RSS, error) {
+	d := newDecoder(b)
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, parseError(d, b, err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		var r *RSS
+		switch start.Name {
+		case xml.Name{Local: "rss"}:
+			return parse(b, maxItems)
+		case xml.Name{Space: AtomNamespace, Local: "feed"}:
+			r, err = parseAtom(b, maxItems)
+		case xml.Name{Space: rdfNamespace, Local: "RDF"}:
+			r, err = parseRDF(b, maxItems)
+		default:
+			return nil, ErrUnsupportedFormat
+		}
+		if err != nil {
+			return nil, err
+		}
+		r.encoding = sniffEncoding(b)
+		return r, nil
+	}
+}
+
+// parse decodes the RSS document in b, with at most maxItems items if it is
+// positive. The decoder is strict, so malformed XML (e.g. unclosed elements
+// or undefined entities) is an error.
+func parse(b []byte, maxItems int) (*RSS, error) {
+	rss := &RSS{}
+	if err := decode(b, rss, itemLimit{name: "item", depth: 3, max: maxItems}); err != nil {
+		return nil, err
+	}
+	if rss.Channel == nil {
+		return nil, &ValidationError{Path: "channel", Err: ErrMissingChannel}
+	}
+	rss.encoding = sniffEncoding(b)
+	return rss, nil
+}
+
+// decode decodes the XML document in b into v with a strict decoder, stopping
+// with ErrTooManyItems at the first item over limit. Decode errors are
+// returned as a *ParseError.
+func decode(b []byte, v interface{}, limit itemLimit) error {
+	d := newDecoder(b)
+	dec := d
+	if limit.max > 0 {
+		dec = xml.NewTokenDecoder(&itemLimiter{d: d, limit: limit})
+	}
+	if err := dec.Decode(v); err != nil {
+		if errors.Is(err, ErrTooManyItems) {
+			return err
+		}
+		return parseError(d, b, err)
+	}
+	return nil
+}
+
+// itemLimit limits the number of items of a document: the elements with the
+// local name name at depth, the root element being at depth 1. A max of zero
+// means no limit.
+type itemLimit struct {
+	name  string
+	depth int
+	max   int
+}
+
+// itemLimiter is an xml.TokenReader reading the tokens of d, which fails with
+// ErrTooManyItems at the start of the first item over its limit.
+type itemLimiter struct {
+	d     *xml.Decoder
+	limit itemLimit
+	depth int
+	items int
+}
+
+// Token implements xml.TokenReader.
+func (l *itemLimiter) Token() (xml.Token, error) {
+	tok, err := l.d.Token()
+	if err != nil {
+		return tok, err
+	}
+	switch t := tok.(type) {
+	case xml.StartElement:
+		l.depth++
+		if l.depth == l.limit.depth && t.Name.Local == l.limit.name {
+			if l.items++; l.items > l.limit.max {
+				return nil, fmt.Errorf("%w: more than %d items", ErrTooManyItems, l.limit.max)
+			}
+		}
+	case xml.EndElement:
+		l.depth--
+	}
+	return tok, nil
+}
+
+// newDecoder returns a strict decoder of the XML document in b, reading
+// documents in any encoding supported by charsetReader.
+func newDecoder(b []byte) *xml.Decoder {
+	d := xml.NewDecoder(bytes.NewReader(b))
+	d.Strict = true
+	d.CharsetReader = charsetReader
+	return d
+}
+
+// parseError returns a *ParseError locating err, returned by d while decoding
+// the document b. The line is that of the *xml.SyntaxError if err is one, and
+// is otherwise derived from the input offset of d.
+func parseError(d *xml.Decoder, b []byte, err error) error {
+	offset := d.InputOffset()
+	line := 1 + bytes.Count(b[:min(int(offset), len(b))], []byte("\n"))
+	var se *xml.SyntaxError
+	if errors.As(err, &se) {
+		line = se.Line
+	}
+	return &ParseError{Line: line, Offset: offset, Err: err}
+}
+
+// UnmarshalXML implements xml.Unmarshaler. It records the namespace
+// declarations of the <rss> element in r.Namespaces.
+func (r *RSS) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type rss RSS // rss has the fields of RSS but not its methods.
+	if err := d.DecodeElement((*rss)(r), &start); err != nil {
+		return err
+	}
+	r.Namespaces = nil
+	for _, a := range start.Attr {
+		switch {
+		case a.Name.Space == "xmlns":
+			r.Namespaces = append(r.Namespaces, xml.Attr{Name: xml.Name{Local: "xmlns:" + a.Name.Local}, Value: a.Value})
+		case a.Name.Space == "" && a.Name.Local == "xmlns":
+			r.Namespaces = append(r.Namespaces, a)
+		}
+	}
+	return nil
+}
+
+// UnmarshalXML implements xml.Unmarshaler. Elements from namespace modules
+// are decoded into c.Extensions, and the order of the child elements is
+// recorded for MarshalOptions.PreserveOrder.
+func (c *Channel) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	order, err := decodeStruct(d, start, reflect.ValueOf(c).Elem())
+	c.order = order
+	return err
+}
+
+// UnmarshalXML implements xml.Unmarshaler. Elements from namespace modules
+// are decoded into i.Extensions, and the order of the child elements is
+// recorded for MarshalOptions.PreserveOrder.
+func (i *Item) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	order, err := decodeStruct(d, start, reflect.ValueOf(i).Elem())
+	i.order = order
+	return err
+}
+
+// decodeStruct decodes the element start into the struct v and returns the
+// names of its child elements in document order. Unlike xml.Decoder, it only
+// decodes a child element into a field without a namespace if the child is in
+// the namespace of start.
+func decodeStruct(d *xml.Decoder, start xml.StartElement, v reflect.Value) ([]xml.Name, error) {
+	_, fields := structFields(v.Type())
+	if f := v.FieldByName("XMLName"); f.IsValid() {
+		f.Set(reflect.ValueOf(start.Name))
+	}
+	for _, a := range start.Attr {
+		for _, f := range fields {
+			if f.attr && f.name == a.Name {
+				v.Field(f.index).SetString(a.Value)
+			}
+		}
+	}
+	var order []xml.Name
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return order, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			order = append(order, t.Name)
+			j, ok := match(fields, start.Name.Space, t.Name)
+			if !ok {
+				if err := d.Skip(); err != nil {
+					return order, err
+				}
+				continue
+			}
+			fv := v.Field(fields[j].index)
+			if fv.Kind() == reflect.Slice {
+				ev := reflect.New(fv.Type().Elem())
+				if err := d.DecodeElement(ev.Interface(), &t); err != nil {
+					return order, err
+				}
+				fv.Set(reflect.Append(fv, ev.Elem()))
+				continue
+			}
+			if err := d.DecodeElement(fv.Addr().Interface(), &t); err != nil {
+				return order, err
+			}
+		case xml.EndElement:
+			return order, nil
+		}
+	}
+}
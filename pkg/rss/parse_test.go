@@ -0,0 +1,357 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func mustParseFile(t testing.TB, name string) *RSS {
+	t.Helper()
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	r, err := Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func mustParse(t testing.TB, doc string) *RSS {
+	t.Helper()
+	r, err := Parse(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestParseSource(t *testing.T) {
+	r := mustParse(t, `<rss version="2.0"><channel><title>Liftoff News</title>
+<item><title>a</title><source url="http://www.tomalak.org/links2.xml">Tomalak's Realm</source></item>
+<item><title>b</title><source>Tomalak's Realm</source></item>
+</channel></rss>`)
+	want := []Source{
+		{URL: "http://www.tomalak.org/links2.xml", Value: "Tomalak's Realm"},
+		{Value: "Tomalak's Realm"},
+	}
+	for i, item := range r.Channel.Item {
+		if s := item.Source; s == nil || s.URL != want[i].URL || s.Value != want[i].Value {
+			t.Errorf("Item[%d].Source = %+v, want %+v", i, s, want[i])
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	r := mustParseFile(t, "../../test/data/rss-0.xml")
+	if r.Version != "2.0" {
+		t.Errorf("Version = %q, want %q", r.Version, "2.0")
+	}
+	if r.Channel == nil {
+		t.Fatal("Channel = nil")
+	}
+	if r.Channel.Title != "Liftoff News" {
+		t.Errorf("Channel.Title = %q, want %q", r.Channel.Title, "Liftoff News")
+	}
+	if got := len(r.Channel.Item); got != 4 {
+		t.Fatalf("len(Channel.Item) = %d, want 4", got)
+	}
+	if got := r.Channel.Item[0].GUID.Value; got != "http://liftoff.msfc.nasa.gov/2003/06/03.html#item573" {
+		t.Errorf("Channel.Item[0].GUID.Value = %q", got)
+	}
+}
+
+func TestParseRecordsOrder(t *testing.T) {
+	r := mustParseFile(t, "../../test/data/rss-shuffled.xml")
+	want := []string{"item", "language", "description", "generator", "link", "item", "title", "docs"}
+	if got := localNames(r.Channel.order); !equalStrings(got, want) {
+		t.Errorf("Channel.order = %v, want %v", got, want)
+	}
+	want = []string{"guid", "pubDate", "title", "category", "link", "category"}
+	if got := localNames(r.Channel.Item[0].order); !equalStrings(got, want) {
+		t.Errorf("Channel.Item[0].order = %v, want %v", got, want)
+	}
+}
+
+func TestParseLimit(t *testing.T) {
+	b, err := os.ReadFile("../../test/data/rss-0.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	size := int64(len(b))
+	if _, err := ParseLimit(bytes.NewReader(b), size); err != nil {
+		t.Errorf("ParseLimit(%d) at limit: %v", size, err)
+	}
+	if _, err := ParseLimit(bytes.NewReader(b), size+1); err != nil {
+		t.Errorf("ParseLimit(%d) under limit: %v", size+1, err)
+	}
+	if _, err := ParseLimit(bytes.NewReader(b), size-1); !errors.Is(err, ErrFeedTooLarge) {
+		t.Errorf("ParseLimit(%d) over limit: got %v, want ErrFeedTooLarge", size-1, err)
+	}
+}
+
+func TestParseGzip(t *testing.T) {
+	want := mustParseFile(t, "../../test/data/rss-0.xml")
+	if got := mustParseFile(t, "../../test/data/rss-0.xml.gz"); !got.Equal(want) {
+		t.Errorf("Parse(rss-0.xml.gz) = %+v, want %+v", got.Channel, want.Channel)
+	}
+	b, err := os.ReadFile("../../test/data/rss-0.xml.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The limit applies to the decompressed document, which is larger.
+	if _, err := ParseLimit(bytes.NewReader(b), int64(len(b))); !errors.Is(err, ErrFeedTooLarge) {
+		t.Errorf("ParseLimit(%d): got %v, want ErrFeedTooLarge", len(b), err)
+	}
+	if _, err := Parse(bytes.NewReader(b[:len(b)/2])); err == nil {
+		t.Error("Parse(truncated gzip): expected error")
+	}
+}
+
+func TestParseCharset(t *testing.T) {
+	tests := []struct {
+		encoding string
+		title    string
+		want     Title
+	}{
+		{"ISO-8859-1", "caf\xe9", "caf\u00e9"},
+		{"windows-1252", "\x93quoted\x94", "\u201cquoted\u201d"},
+		{"UTF-8", "caf\u00e9", "caf\u00e9"},
+	}
+	for _, tt := range tests {
+		doc := `<?xml version="1.0" encoding="` + tt.encoding + `"?><rss version="2.0"><channel><title>` + tt.title + `</title></channel></rss>`
+		r, err := Parse(strings.NewReader(doc))
+		if err != nil {
+			t.Errorf("%s: %v", tt.encoding, err)
+			continue
+		}
+		if r.Channel.Title != tt.want {
+			t.Errorf("%s: Channel.Title = %q, want %q", tt.encoding, r.Channel.Title, tt.want)
+		}
+	}
+	const doc = `<?xml version="1.0" encoding="x-unknown"?><rss version="2.0"><channel/></rss>`
+	if _, err := Parse(strings.NewReader(doc)); err == nil {
+		t.Error("Parse(unknown encoding): expected error")
+	}
+}
+
+func TestParseMissingChannel(t *testing.T) {
+	_, err := Parse(strings.NewReader(`<rss version="2.0"></rss>`))
+	if !errors.Is(err, ErrMissingChannel) {
+		t.Errorf("Parse() = %v, want error matching ErrMissingChannel", err)
+	}
+}
+
+func TestParseNamespacedElements(t *testing.T) {
+	const doc = `<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd">
+  <channel>
+    <title>Example Podcast</title>
+    <link>http://example.com/</link>
+    <atom:link href="http://example.com/feed.xml" rel="self" type="application/rss+xml"/>
+    <itunes:image href="http://example.com/artwork.jpg"/>
+    <item>
+      <title>Episode 1</title>
+      <itunes:title>Episode One</itunes:title>
+    </item>
+  </channel>
+</rss>`
+	r, err := Parse(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := r.Channel
+	if c.Link != "http://example.com/" {
+		t.Errorf("Channel.Link = %q, want %q", c.Link, "http://example.com/")
+	}
+	if c.Image != nil {
+		t.Errorf("Channel.Image = %+v, want nil", c.Image)
+	}
+	if len(c.Extensions) != 2 || c.Extensions[0].XMLName.Local != "link" || c.Extensions[1].XMLName.Local != "image" {
+		t.Errorf("Channel.Extensions = %+v, want atom:link and itunes:image", c.Extensions)
+	}
+	if item := c.Item[0]; item.Title != "Episode 1" || len(item.Extensions) != 1 {
+		t.Errorf("Item = %+v, want title %q and one extension", item, "Episode 1")
+	}
+}
+
+func TestParseContentEncoded(t *testing.T) {
+	const doc = `<rss version="2.0" xmlns:content="http://purl.org/rss/1.0/modules/content/">
+  <channel>
+    <item>
+      <description>Summary</description>
+      <content:encoded><![CDATA[<p>Full <b>content</b></p>]]></content:encoded>
+    </item>
+  </channel>
+</rss>`
+	r, err := Parse(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	item := r.Channel.Item[0]
+	if want := ContentEncoded("<p>Full <b>content</b></p>"); item.ContentEncoded != want {
+		t.Errorf("ContentEncoded = %q, want %q", item.ContentEncoded, want)
+	}
+	if len(item.Extensions) != 0 {
+		t.Errorf("Extensions = %+v, want none", item.Extensions)
+	}
+}
+
+func TestParseStrict(t *testing.T) {
+	const doc = `<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd">
+  <channel>
+    <title>Example</title>
+    <itunes:author>John Doe</itunes:author>
+    <item>
+      <titel>Episode 1</titel>
+    </item>
+  </channel>
+</rss>`
+	if _, err := Parse(strings.NewReader(doc)); err != nil {
+		t.Fatalf("Parse() = %v, want lenient parsing to succeed", err)
+	}
+	_, err := ParseStrict(strings.NewReader(doc))
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Path != "item[0].titel" || !errors.Is(err, ErrUnknownElement) {
+		t.Errorf("ParseStrict() = %v, want ErrUnknownElement for item[0].titel", err)
+	}
+
+	r, err := ParseStrict(strings.NewReader(strings.Replace(doc, "titel", "title", 2)))
+	if err != nil {
+		t.Fatalf("ParseStrict() = %v", err)
+	}
+	if r.Channel.Item[0].Title != "Episode 1" {
+		t.Errorf("Item[0].Title = %q, want %q", r.Channel.Item[0].Title, "Episode 1")
+	}
+	if _, err := ParseStrict(strings.NewReader(`<rss version="2.0"><channel><title>a &nbsp; b</title></channel></rss>`)); err == nil {
+		t.Error("ParseStrict() = nil, want error for undefined entity")
+	}
+}
+
+func TestParseAny(t *testing.T) {
+	tests := []struct {
+		name  string
+		title Title
+	}{
+		{"../../test/data/rss-0.xml", "Liftoff News"},
+		{"../../test/data/atom-0.xml", "Example Feed"},
+		{"../../test/data/rdf-0.xml", "XML.com"},
+		{"../../test/data/rss-0.xml.gz", "Liftoff News"},
+	}
+	for _, tt := range tests {
+		f, err := os.Open(tt.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r, err := ParseAny(f)
+		f.Close()
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if r.Channel.Title != tt.title || len(r.Channel.Item) == 0 {
+			t.Errorf("%s: Channel.Title = %q with %d items, want %q with items", tt.name, r.Channel.Title, len(r.Channel.Item), tt.title)
+		}
+	}
+}
+
+func TestParseAnyErrors(t *testing.T) {
+	tests := []struct {
+		doc  string
+		want error
+	}{
+		{`<?xml version="1.0"?><html><body/></html>`, ErrUnsupportedFormat},
+		{`<feed><title>Not Atom</title></feed>`, ErrUnsupportedFormat},
+		{`<rss version="2.0"></rss>`, ErrMissingChannel},
+	}
+	for _, tt := range tests {
+		if _, err := ParseAny(strings.NewReader(tt.doc)); !errors.Is(err, tt.want) {
+			t.Errorf("ParseAny(%q) = %v, want %v", tt.doc, err, tt.want)
+		}
+	}
+	if _, err := ParseAnyLimit(strings.NewReader(`<rss version="2.0"><channel/></rss>`), 8); !errors.Is(err, ErrFeedTooLarge) {
+		t.Errorf("ParseAnyLimit() = %v, want ErrFeedTooLarge", err)
+	}
+}
+
+func TestParseSyntaxError(t *testing.T) {
+	tests := []struct {
+		name  string
+		parse func(io.Reader) (*RSS, error)
+		doc   string
+		line  int
+	}{
+		{"Parse", Parse, "<rss version=\"2.0\">\n<channel>\n<title>a</titel>\n</channel>\n</rss>", 3},
+		{"ParseAny", ParseAny, "<rss version=\"2.0\">\n<channel>\n<title>a &nbsp; b</title>\n</channel>\n</rss>", 3},
+		{"ParseAny Atom", ParseAny, "<feed xmlns=\"http://www.w3.org/2005/Atom\">\n<title>a</title>\n\n<entry>\n</feed>", 5},
+		{"ParseAny prolog", ParseAny, "\n<?xml version=\"1.0\"?>\n<<rss>", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.parse(strings.NewReader(tt.doc))
+			var pe *ParseError
+			if !errors.As(err, &pe) {
+				t.Fatalf("error = %v, want *ParseError", err)
+			}
+			if pe.Line != tt.line {
+				t.Errorf("Line = %d, want %d", pe.Line, tt.line)
+			}
+			if pe.Offset <= 0 || pe.Offset > int64(len(tt.doc)) {
+				t.Errorf("Offset = %d, want an offset within the document", pe.Offset)
+			}
+			if want := fmt.Sprintf("line %d ", tt.line); !strings.HasPrefix(err.Error(), want) {
+				t.Errorf("error = %q, want prefix %q", err, want)
+			}
+			var se *xml.SyntaxError
+			if !errors.As(err, &se) {
+				t.Errorf("error = %v, want it to wrap *xml.SyntaxError", err)
+			}
+		})
+	}
+}
+
+func TestParseOptionsMaxItems(t *testing.T) {
+	// Each document has two items followed by malformed XML, so decoding
+	// fails with a *ParseError unless it stops at the item over the limit.
+	tests := []struct {
+		name string
+		doc  string
+	}{
+		{"rss", `<rss version="2.0"><channel><title>a</title><item><title>1</title></item><item><title>2</title></item><bad></channel></rss>`},
+		{"atom", `<feed xmlns="http://www.w3.org/2005/Atom"><title>a</title><entry><title>1</title></entry><entry><title>2</title></entry><bad></feed>`},
+		{"rdf", `<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/"><channel><title>a</title></channel><item><title>1</title></item><item><title>2</title></item><bad></rdf:RDF>`},
+	}
+	for _, tt := range tests {
+		_, err := ParseOptions{MaxItems: 1}.ParseAny(strings.NewReader(tt.doc))
+		var pe *ParseError
+		if !errors.Is(err, ErrTooManyItems) || errors.As(err, &pe) {
+			t.Errorf("%s: ParseAny() = %v, want ErrTooManyItems before the malformed XML", tt.name, err)
+		}
+		if _, err := (ParseOptions{MaxItems: 2}).ParseAny(strings.NewReader(tt.doc)); !errors.As(err, &pe) {
+			t.Errorf("%s: ParseAny() with MaxItems 2 = %v, want a *ParseError", tt.name, err)
+		}
+	}
+
+	// Items nested in extensions are not counted.
+	doc := `<rss version="2.0" xmlns:x="http://example.com/x"><channel><title>a</title><item><title>1</title><x:list><x:item/><x:item/></x:list></item></channel></rss>`
+	r, err := ParseOptions{MaxItems: 1}.Parse(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Channel.Item) != 1 || r.Channel.Item[0].Title != "1" {
+		t.Errorf("Parse() items = %v, want the single item", r.Channel.Item)
+	}
+	if _, err := (ParseOptions{MaxBytes: 8}).Parse(strings.NewReader(doc)); !errors.Is(err, ErrFeedTooLarge) {
+		t.Errorf("Parse() with MaxBytes = %v, want ErrFeedTooLarge", err)
+	}
+}
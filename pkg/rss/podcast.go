@@ -0,0 +1,62 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ItunesNamespace is the namespace of the iTunes podcast elements (e.g.
+// <itunes:author>).
+//
+// See: https://podcasters.apple.com/support/823-podcast-requirements
+const ItunesNamespace = "http://www.itunes.com/dtds/podcast-1.0.dtd"
+
+// IsValid returns true if the enclosure has the three required attributes:
+// a url that is an absolute URL, a length that is a number of bytes and a
+// type.
+func (r *Enclosure) IsValid() bool {
+	if !r.URL.IsValid() || strings.TrimSpace(r.Type) == "" {
+		return false
+	}
+	_, err := strconv.ParseUint(strings.TrimSpace(r.Length), 10, 64)
+	return err == nil
+}
+
+// DeclareNamespace declares the namespace space with prefix on the <rss>
+// element, so that elements in the namespace are marshaled with the prefix
+// (e.g. <itunes:author>) rather than with a default namespace declaration.
+// It does nothing if space is already declared. If prefix is declared for
+// another namespace, DeclareNamespace returns an error.
+func (r *RSS) DeclareNamespace(prefix, space string) error {
+	for _, ns := range r.Namespaces {
+		if !strings.HasPrefix(ns.Name.Local, "xmlns:") {
+			continue
+		}
+		if ns.Value == space {
+			return nil
+		}
+		if ns.Name.Local == "xmlns:"+prefix {
+			return fmt.Errorf("prefix %q is declared for namespace %q", prefix, ns.Value)
+		}
+	}
+	r.Namespaces = append(r.Namespaces, xml.Attr{Name: xml.Name{Local: "xmlns:" + prefix}, Value: space})
+	return nil
+}
+
+// missingEnclosures reports every item without an enclosure, or with an
+// enclosure that is not valid, which podcast apps cannot play.
+func (v *validator) missingEnclosures(c *Channel) {
+	for i, item := range c.Item {
+		switch {
+		case item.Enclosure == nil:
+			v.add(fmt.Sprintf("item[%d]", i), ErrMissingEnclosure)
+		case !item.Enclosure.IsValid():
+			v.add(fmt.Sprintf("item[%d].enclosure", i), ErrInvalidEnclosure)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const testPodcast = "../../test/data/rss-podcast.xml"
+
+func TestEnclosureIsValid(t *testing.T) {
+	tests := []struct {
+		e    Enclosure
+		want bool
+	}{
+		{Enclosure{URL: "http://example.com/1.mp3", Length: "12216320", Type: "audio/mpeg"}, true},
+		{Enclosure{URL: "http://example.com/1.mp3", Length: "0", Type: "audio/mpeg"}, true},
+		{Enclosure{URL: "1.mp3", Length: "12216320", Type: "audio/mpeg"}, false},
+		{Enclosure{URL: "http://example.com/1.mp3", Length: "", Type: "audio/mpeg"}, false},
+		{Enclosure{URL: "http://example.com/1.mp3", Length: "12 MB", Type: "audio/mpeg"}, false},
+		{Enclosure{URL: "http://example.com/1.mp3", Length: "12216320"}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.e.IsValid(); got != tt.want {
+			t.Errorf("%+v.IsValid() = %v, want %v", tt.e, got, tt.want)
+		}
+	}
+}
+
+func TestDeclareNamespace(t *testing.T) {
+	r := mustParseFile(t, testPodcast)
+	b, err := Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "<itunes:author>") {
+		t.Fatalf("Marshal() before DeclareNamespace has prefixed iTunes elements:\n%s", b)
+	}
+	for i := 0; i < 2; i++ {
+		if err := r.DeclareNamespace("itunes", ItunesNamespace); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(r.Namespaces) != 1 {
+		t.Errorf("Namespaces = %v, want one declaration", r.Namespaces)
+	}
+	if b, err = Marshal(r); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`xmlns:itunes="` + ItunesNamespace + `"`, "<itunes:author>Example Author</itunes:author>", "<itunes:duration>00:42:17</itunes:duration>"} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("Marshal() does not contain %s:\n%s", want, b)
+		}
+	}
+	if err := r.DeclareNamespace("itunes", "http://example.com/other"); err == nil {
+		t.Error("DeclareNamespace with a declared prefix: expected error")
+	}
+}
+
+func TestLintPodcast(t *testing.T) {
+	r := mustParseFile(t, testPodcast)
+	if w := r.Lint(); len(w) != 0 {
+		t.Errorf("Lint() = %v, want no warnings", w)
+	}
+	r.Channel.Item = append(r.Channel.Item, &Item{Title: "Episode 3", GUID: &GUID{Value: "3"}, Enclosure: &Enclosure{URL: "3.mp3"}})
+	var got []string
+	for _, e := range (LintOptions{Podcast: true}).Lint(r) {
+		if e.Rule != RuleMissingEnclosure {
+			t.Errorf("%v: Rule = %q, want %q", e, e.Rule, RuleMissingEnclosure)
+		}
+		got = append(got, e.Error())
+	}
+	want := []string{"item[1]: missing enclosure", "item[2].enclosure: invalid enclosure"}
+	if !equalStrings(got, want) {
+		t.Errorf("Lint() = %q, want %q", got, want)
+	}
+	w := (LintOptions{Podcast: true, DisableRules: []string{RuleMissingEnclosure}}).Lint(r)
+	if errors.Is(w, ErrMissingEnclosure) {
+		t.Errorf("Lint() with %s disabled = %v", RuleMissingEnclosure, w)
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// rdfNamespace is the namespace of the RDF syntax.
+const rdfNamespace = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+
+// rdfDocument is an RSS 1.0 document.
+//
+// See: https://web.resource.org/rss/1.0/spec
+type rdfDocument struct {
+	XMLName   xml.Name      `xml:"http://www.w3.org/1999/02/22-rdf-syntax-ns# RDF"`
+	Channel   *rdfChannel   `xml:"channel"`
+	Image     *rdfImage     `xml:"image"`
+	Items     []*rdfItem    `xml:"item"`
+	TextInput *rdfTextInput `xml:"textinput"`
+}
+
+type rdfChannel struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	Description string `xml:"description"`
+	Language    string `xml:"http://purl.org/dc/elements/1.1/ language"`
+	Date        string `xml:"http://purl.org/dc/elements/1.1/ date"`
+	// Items are the resources of the items of the channel, in order.
+	Items []struct {
+		Resource string `xml:"resource,attr"`
+	} `xml:"items>Seq>li"`
+}
+
+type rdfImage struct {
+	Title string `xml:"title"`
+	Link  string `xml:"link"`
+	URL   string `xml:"url"`
+}
+
+type rdfItem struct {
+	About       string `xml:"about,attr"`
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	Description string `xml:"description"`
+	Date        string `xml:"http://purl.org/dc/elements/1.1/ date"`
+}
+
+type rdfTextInput struct {
+	Title       string `xml:"title"`
+	Description string `xml:"description"`
+	Name        string `xml:"name"`
+	Link        string `xml:"link"`
+}
+
+// ParseRDF reads an RSS 1.0 (RDF) document from r and converts it to RSS 2.0.
+// Items are ordered as listed in the <items> sequence of the channel, followed
+// by any unlisted items in document order. The rdf:about URI of each item
+// becomes its guid, and Dublin Core dates become publication dates. If the
+// document has no channel, ParseRDF returns an error wrapping
+// ErrMissingChannel.
+func ParseRDF(r io.Reader) (*RSS, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return parseRDF(b, 0)
+}
+
+// parseRDF decodes the RSS 1.0 document in b, with at most maxItems items if
+// it is positive.
+func parseRDF(b []byte, maxItems int) (*RSS, error) {
+	var doc rdfDocument
+	if err := decode(b, &doc, itemLimit{name: "item", depth: 2, max: maxItems}); err != nil {
+		return nil, err
+	}
+	if doc.Channel == nil {
+		return nil, &ValidationError{Path: "channel", Err: ErrMissingChannel}
+	}
+	c := &Channel{
+		Title:       Title(doc.Channel.Title),
+		Link:        Link(doc.Channel.Link),
+		Description: Description(doc.Channel.Description),
+		Language:    Language(doc.Channel.Language),
+		PubDate:     PubDate(doc.Channel.Date),
+	}
+	if img := doc.Image; img != nil {
+		c.Image = &Image{URL: URL(img.URL), Title: Title(img.Title), Link: Link(img.Link)}
+	}
+	if ti := doc.TextInput; ti != nil {
+		c.TextInput = &TextInput{
+			Title:       Title(ti.Title),
+			Description: Description(ti.Description),
+			Name:        ti.Name,
+			Link:        Link(ti.Link),
+		}
+	}
+	byAbout := make(map[string]*rdfItem)
+	for _, item := range doc.Items {
+		if item.About != "" {
+			byAbout[item.About] = item
+		}
+	}
+	added := make(map[*rdfItem]bool)
+	add := func(item *rdfItem) {
+		if added[item] {
+			return
+		}
+		added[item] = true
+		i := &Item{
+			Title:       Title(item.Title),
+			Link:        Link(item.Link),
+			Description: Description(item.Description),
+			PubDate:     PubDate(item.Date),
+		}
+		if item.About != "" {
+			i.GUID = &GUID{IsPermaLink: "false", Value: item.About}
+		}
+		c.Item = append(c.Item, i)
+	}
+	for _, li := range doc.Channel.Items {
+		if item, ok := byAbout[li.Resource]; ok {
+			add(item)
+		}
+	}
+	for _, item := range doc.Items {
+		add(item)
+	}
+	return &RSS{Version: "2.0", Channel: c}, nil
+}
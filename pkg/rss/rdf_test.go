@@ -0,0 +1,85 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseRDF(t *testing.T) {
+	f, err := os.Open("../../test/data/rdf-0.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	r, err := ParseRDF(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+	c := r.Channel
+	if c.Title != "XML.com" || c.Link != "http://xml.com/pub" || c.Language != "en-us" {
+		t.Errorf("Channel = %+v", c)
+	}
+	if c.PubDate != "2000-08-23T12:00:00Z" {
+		t.Errorf("Channel.PubDate = %q, want %q", c.PubDate, "2000-08-23T12:00:00Z")
+	}
+	if c.Image == nil || c.Image.URL != "http://xml.com/universal/images/xml_tiny.gif" {
+		t.Errorf("Channel.Image = %+v", c.Image)
+	}
+	if c.TextInput == nil || c.TextInput.Name != "s" {
+		t.Errorf("Channel.TextInput = %+v", c.TextInput)
+	}
+	// Items are ordered by the <items> sequence, not document order.
+	want := []string{
+		"http://xml.com/pub/2000/08/09/xslt/xslt.html",
+		"http://xml.com/pub/2000/08/09/rdfdb/index.html",
+	}
+	if len(c.Item) != len(want) {
+		t.Fatalf("len(Channel.Item) = %d, want %d", len(c.Item), len(want))
+	}
+	for i, item := range c.Item {
+		if item.GUID == nil || item.GUID.Value != want[i] || item.GUID.IsPermaLink != "false" {
+			t.Errorf("Item[%d].GUID = %+v, want %q", i, item.GUID, want[i])
+		}
+	}
+	if c.Item[0].PubDate != "2000-08-10T09:00:00Z" {
+		t.Errorf("Item[0].PubDate = %q, want %q", c.Item[0].PubDate, "2000-08-10T09:00:00Z")
+	}
+}
+
+func TestParseRDFUnlistedItems(t *testing.T) {
+	const doc = `<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/">
+<channel><title>t</title><items><rdf:Seq><rdf:li resource="b"/></rdf:Seq></items></channel>
+<item rdf:about="a"><title>a</title></item>
+<item rdf:about="b"><title>b</title></item>
+<item><title>c</title></item>
+</rdf:RDF>`
+	r, err := ParseRDF(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, item := range r.Channel.Item {
+		got = append(got, string(item.Title))
+	}
+	if want := []string{"b", "a", "c"}; !equalStrings(got, want) {
+		t.Errorf("item titles = %v, want %v", got, want)
+	}
+}
+
+func TestParseRDFErrors(t *testing.T) {
+	const noChannel = `<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#"></rdf:RDF>`
+	if _, err := ParseRDF(strings.NewReader(noChannel)); !errors.Is(err, ErrMissingChannel) {
+		t.Errorf("got %v, want ErrMissingChannel", err)
+	}
+	if _, err := ParseRDF(strings.NewReader(`<rss version="2.0"><channel/></rss>`)); err == nil {
+		t.Error("expected error for non-RDF document")
+	}
+}
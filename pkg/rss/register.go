@@ -0,0 +1,84 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sync"
+)
+
+// ElementValidator validates an extension element: a child element of the
+// channel or of an item that is not defined by the specification, such as an
+// element of a namespace module. It returns an error if the element is
+// invalid.
+type ElementValidator func(x *Extension) error
+
+var (
+	validatorsMu sync.RWMutex
+	validators   = make(map[xml.Name]ElementValidator)
+)
+
+// RegisterElementValidator registers fn to validate the extension elements
+// named name, e.g. {Space: "http://purl.org/dc/elements/1.1/", Local:
+// "creator"}, of the channel and of its items. Validate reports the errors
+// returned by fn as ValidationErrors wrapping them. Registering a validator
+// for a name replaces the validator previously registered for it; a nil fn
+// removes it.
+func RegisterElementValidator(name xml.Name, fn ElementValidator) {
+	validatorsMu.Lock()
+	defer validatorsMu.Unlock()
+	if fn == nil {
+		delete(validators, name)
+		return
+	}
+	validators[name] = fn
+}
+
+// RegisterElement registers the RSSElement type returned by newElement for
+// the extension elements named name: Validate decodes each such element into
+// a new value returned by newElement and reports it with ErrInvalidElement
+// if it is not valid.
+func RegisterElement(name xml.Name, newElement func() RSSElement) {
+	RegisterElementValidator(name, func(x *Extension) error {
+		e := newElement()
+		b, err := xml.Marshal(x)
+		if err == nil {
+			err = xml.Unmarshal(b, e)
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidElement, err)
+		}
+		if !e.IsValid() {
+			return ErrInvalidElement
+		}
+		return nil
+	})
+}
+
+// elementValidator returns the validator registered for name, if any.
+func elementValidator(name xml.Name) ElementValidator {
+	validatorsMu.RLock()
+	defer validatorsMu.RUnlock()
+	return validators[name]
+}
+
+// extensions validates the extension elements exts of the channel, if item
+// is negative, or of the item at index item, using the registered
+// validators.
+func (v *validator) extensions(item int, exts []*Extension) {
+	for _, x := range exts {
+		fn := elementValidator(x.XMLName)
+		if fn == nil {
+			continue
+		}
+		if err := fn(x); err != nil {
+			path := x.XMLName.Local
+			if item >= 0 {
+				path = fmt.Sprintf("item[%d].%s", item, path)
+			}
+			v.add(path, err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const exampleNamespace = "http://example.com/ns"
+
+// exampleRating is a custom element with a score from 1 to 5.
+type exampleRating struct {
+	Score int `xml:"score,attr"`
+}
+
+func (r *exampleRating) IsValid() bool {
+	return r.Score >= 1 && r.Score <= 5
+}
+
+func TestRegisterElementValidator(t *testing.T) {
+	const doc = `<rss version="2.0" xmlns:ex="http://example.com/ns"><channel>
+<title>Liftoff News</title><link>http://liftoff.msfc.nasa.gov/</link><description>Liftoff</description>
+<ex:owner>nobody</ex:owner>
+<item><title>a</title><ex:rating score="4"/></item>
+<item><title>b</title><ex:rating score="9"/><ex:owner>Nickolas</ex:owner></item>
+</channel></rss>`
+	r := mustParse(t, doc)
+	if err := r.Validate(); err != nil {
+		t.Fatalf("Validate() without validators = %v", err)
+	}
+
+	errNobody := errors.New("owner must be named")
+	owner := xml.Name{Space: exampleNamespace, Local: "owner"}
+	rating := xml.Name{Space: exampleNamespace, Local: "rating"}
+	RegisterElementValidator(owner, func(x *Extension) error {
+		if strings.TrimSpace(x.InnerXML) == "nobody" {
+			return errNobody
+		}
+		return nil
+	})
+	RegisterElement(rating, func() RSSElement { return &exampleRating{} })
+	t.Cleanup(func() {
+		RegisterElementValidator(owner, nil)
+		RegisterElementValidator(rating, nil)
+	})
+
+	err := r.Validate()
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Validate() = %v, want ValidationErrors", err)
+	}
+	var got []string
+	for _, e := range errs {
+		got = append(got, e.Path)
+	}
+	if want := []string{"owner", "item[1].rating"}; !equalStrings(got, want) {
+		t.Errorf("Validate() paths = %v, want %v", got, want)
+	}
+	if !errors.Is(err, errNobody) || !errors.Is(err, ErrInvalidElement) {
+		t.Errorf("Validate() = %v, want errNobody and ErrInvalidElement", err)
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// RepairOptions configures the repair of an RSS document.
+type RepairOptions struct {
+	// NormalizeUnicode converts the titles and descriptions of the channel
+	// and of its items to Unicode Normalization Form C (see NormalizeText),
+	// before guids are generated.
+	NormalizeUnicode bool
+	// FixMojibake repairs double-encoded UTF-8 in the titles and
+	// descriptions of the channel and of its items (see FixMojibake), before
+	// they are normalized. The repair is heuristic and may change text that
+	// is not mojibake, so it is disabled by default.
+	FixMojibake bool
+}
+
+// Repair repairs r using the default options (see RepairOptions.Repair).
+func (r *RSS) Repair() []string {
+	return RepairOptions{}.Repair(r)
+}
+
+// Repair applies safe fixes for common problems to r and returns a
+// description of each change made. Repair:
+//
+//   - trims leading and trailing whitespace from titles and collapses runs
+//     of whitespace in them (see Title.Trimmed)
+//   - converts dates that are not RFC 822 dates (e.g. RFC 3339 dates) to
+//     RFC 1123Z, and reports the dates that cannot be parsed (e.g. dates
+//     with French month names), which are left unchanged
+//   - lowercases the language code
+//   - generates a guid for items without one, using the item link if
+//     present or a hash of the item title and description otherwise
+//   - if o.FixMojibake is set, repairs double-encoded UTF-8 in titles and
+//     descriptions
+//   - if o.NormalizeUnicode is set, normalizes titles and descriptions to
+//     NFC
+//   - removes empty optional elements (see
+//     Channel.RemoveEmptyOptionalElements)
+func (o RepairOptions) Repair(r *RSS) []string {
+	if r.Channel == nil {
+		return nil
+	}
+	rp := repairer{normalize: o.NormalizeUnicode, fixMojibake: o.FixMojibake}
+	c := r.Channel
+	rp.title("title", &c.Title)
+	c.Description = Description(rp.text("description", string(c.Description)))
+	if lang := Language(strings.ToLower(string(c.Language))); lang != c.Language {
+		rp.logf("language", "lowercased %q to %q", c.Language, lang)
+		c.Language = lang
+	}
+	rp.pubDate("pubDate", &c.PubDate)
+	lbd := PubDate(c.LastBuildDate)
+	rp.pubDate("lastBuildDate", &lbd)
+	c.LastBuildDate = LastBuildDate(lbd)
+	for i, item := range c.Item {
+		path := fmt.Sprintf("item[%d]", i)
+		rp.title(path+".title", &item.Title)
+		item.Description = Description(rp.text(path+".description", string(item.Description)))
+		rp.pubDate(path+".pubDate", &item.PubDate)
+		rp.guid(path+".guid", item)
+	}
+	for _, path := range c.RemoveEmptyOptionalElements() {
+		rp.logf(path, "removed empty element")
+	}
+	return rp.log
+}
+
+// repairer accumulates the changes made by Repair.
+type repairer struct {
+	log []string
+	// normalize enables Unicode normalization (see
+	// RepairOptions.NormalizeUnicode).
+	normalize bool
+	// fixMojibake enables the repair of mojibake (see
+	// RepairOptions.FixMojibake).
+	fixMojibake bool
+}
+
+func (rp *repairer) logf(path, format string, a ...interface{}) {
+	rp.log = append(rp.log, path+": "+fmt.Sprintf(format, a...))
+}
+
+func (rp *repairer) title(path string, t *Title) {
+	if trimmed := t.Trimmed(); trimmed != *t {
+		rp.logf(path, "trimmed whitespace")
+		*t = trimmed
+	}
+	*t = Title(rp.text(path, string(*t)))
+}
+
+// text returns s with mojibake fixed if rp.fixMojibake is set, and
+// normalized if rp.normalize is set.
+func (rp *repairer) text(path, s string) string {
+	if rp.fixMojibake {
+		if f := FixMojibake(s); f != s {
+			rp.logf(path, "fixed mojibake")
+			s = f
+		}
+	}
+	if rp.normalize {
+		if n := NormalizeText(s); n != s {
+			rp.logf(path, "normalized Unicode to NFC")
+			s = n
+		}
+	}
+	return s
+}
+
+func (rp *repairer) pubDate(path string, d *PubDate) {
+	if *d == "" || isRFC822(string(*d)) {
+		return
+	}
+	n, err := d.Normalize()
+	if err != nil {
+		rp.logf(path, "left unchanged: %v", err)
+		return
+	}
+	rp.logf(path, "normalized %q to %q", *d, n)
+	*d = n
+}
+
+func (rp *repairer) guid(path string, item *Item) {
+	if item.GUID != nil && item.GUID.Value != "" {
+		return
+	}
+	if item.Link != "" {
+		item.GUID = &GUID{Value: string(item.Link)}
+		rp.logf(path, "generated from link")
+		return
+	}
+	sum := sha1.Sum([]byte(string(item.Title) + "\x00" + string(item.Description)))
+	item.GUID = &GUID{IsPermaLink: "false", Value: hex.EncodeToString(sum[:])}
+	rp.logf(path, "generated from title and description")
+}
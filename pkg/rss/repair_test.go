@@ -0,0 +1,124 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRepair(t *testing.T) {
+	r := validFeed()
+	r.Channel.Title = "  Liftoff News\n"
+	r.Channel.Language = "EN-US"
+	r.Channel.PubDate = "2003-06-10T04:00:00Z"
+	r.Channel.Item = []*Item{
+		{Title: " Star\n    City ", Link: "http://liftoff.msfc.nasa.gov/news/2003/news-starcity.asp"},
+		{Description: "Sky watchers in Europe.", PubDate: "2003-05-30T11:06:42Z"},
+	}
+	log := r.Repair()
+	want := []string{
+		"title: trimmed whitespace",
+		`language: lowercased "EN-US" to "en-us"`,
+		`pubDate: normalized "2003-06-10T04:00:00Z" to "Tue, 10 Jun 2003 04:00:00 +0000"`,
+		"item[0].title: trimmed whitespace",
+		"item[0].guid: generated from link",
+		`item[1].pubDate: normalized "2003-05-30T11:06:42Z" to "Fri, 30 May 2003 11:06:42 +0000"`,
+		"item[1].guid: generated from title and description",
+	}
+	if !equalStrings(log, want) {
+		t.Errorf("Repair() =\n%s\nwant\n%s", strings.Join(log, "\n"), strings.Join(want, "\n"))
+	}
+	c := r.Channel
+	if c.Title != "Liftoff News" || c.Language != "en-us" || c.PubDate != "Tue, 10 Jun 2003 04:00:00 +0000" {
+		t.Errorf("channel not repaired: %q %q %q", c.Title, c.Language, c.PubDate)
+	}
+	if c.Item[0].Title != "Star City" {
+		t.Errorf("Item[0].Title = %q", c.Item[0].Title)
+	}
+	if g := c.Item[0].GUID; g == nil || g.Value != string(c.Item[0].Link) {
+		t.Errorf("Item[0].GUID = %+v, want item link", g)
+	}
+	if g := c.Item[1].GUID; g == nil || g.Value == "" || g.IsPermaLink != "false" {
+		t.Errorf("Item[1].GUID = %+v, want non-permalink hash", g)
+	}
+	if log := r.Repair(); len(log) != 0 {
+		t.Errorf("second Repair() = %v, want no changes", log)
+	}
+}
+
+func TestRepairLocalizedDate(t *testing.T) {
+	r := validFeed()
+	r.Channel.Item = []*Item{{Title: "a", PubDate: "12 décembre 2023 10:00:00 +0100"}}
+	log := r.Repair()
+	want := `item[0].pubDate: left unchanged: invalid date "12 décembre 2023 10:00:00 +0100": "décembre" is a French month name, but RFC 822 requires English names`
+	if len(log) == 0 || log[0] != want {
+		t.Errorf("Repair() = %q, want %q first", log, want)
+	}
+	if r.Channel.Item[0].PubDate != "12 décembre 2023 10:00:00 +0100" {
+		t.Errorf("Item[0].PubDate = %q, want it unchanged", r.Channel.Item[0].PubDate)
+	}
+}
+
+func TestRepairCleanFeed(t *testing.T) {
+	r := mustParseFile(t, "../../test/data/rss-0.xml")
+	if log := r.Repair(); len(log) != 0 {
+		t.Errorf("Repair() = %v, want no changes", log)
+	}
+}
+
+func TestRepairNormalizeUnicode(t *testing.T) {
+	newFeed := func(title, description string) *RSS {
+		r := validFeed()
+		r.Channel.Item = []*Item{{Title: Title(title), Description: Description(description)}}
+		return r
+	}
+	nfd := newFeed("Cafe\u0301 opens", "Cre\u0300me bru\u0302le\u0301e")
+	nfc := newFeed("Caf\u00e9 opens", "Cr\u00e8me br\u00fbl\u00e9e")
+	log := RepairOptions{NormalizeUnicode: true}.Repair(nfd)
+	want := []string{
+		"item[0].title: normalized Unicode to NFC",
+		"item[0].description: normalized Unicode to NFC",
+		"item[0].guid: generated from title and description",
+	}
+	if !equalStrings(log, want) {
+		t.Errorf("Repair() =\n%s\nwant\n%s", strings.Join(log, "\n"), strings.Join(want, "\n"))
+	}
+	RepairOptions{NormalizeUnicode: true}.Repair(nfc)
+	a, b := nfd.Channel.Item[0], nfc.Channel.Item[0]
+	if a.Title != b.Title || a.Description != b.Description || a.GUID.Value != b.GUID.Value {
+		t.Errorf("NFD item = %+v, NFC item = %+v, want equal", a, b)
+	}
+
+	// Without the option, the forms are left as is.
+	nfd = newFeed("Cafe\u0301 opens", "")
+	nfd.Repair()
+	if nfd.Channel.Item[0].Title != "Cafe\u0301 opens" {
+		t.Errorf("Repair() without NormalizeUnicode changed the title to %+q", nfd.Channel.Item[0].Title)
+	}
+}
+
+func TestRepairFixMojibake(t *testing.T) {
+	r := validFeed()
+	r.Channel.Item = []*Item{
+		{Title: "CafÃ© opens", Description: "itâ€™s open", GUID: &GUID{Value: "1"}},
+		{Title: "Café closes", GUID: &GUID{Value: "2"}},
+	}
+	log := RepairOptions{FixMojibake: true}.Repair(r)
+	want := []string{
+		"item[0].title: fixed mojibake",
+		"item[0].description: fixed mojibake",
+	}
+	if !equalStrings(log, want) {
+		t.Errorf("Repair() =\n%s\nwant\n%s", strings.Join(log, "\n"), strings.Join(want, "\n"))
+	}
+	if item := r.Channel.Item[0]; item.Title != "Café opens" || item.Description != "it’s open" {
+		t.Errorf("Item[0] = %q, %q", item.Title, item.Description)
+	}
+
+	r.Channel.Item[0].Title = "CafÃ© opens"
+	if log := r.Repair(); len(log) != 0 {
+		t.Errorf("Repair() without FixMojibake = %v, want no changes", log)
+	}
+}
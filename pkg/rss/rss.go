@@ -0,0 +1,474 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package rss implements the RSS 2.0 specification.
+//
+// See: https://www.rssboard.org/rss-specification
+package rss
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// RSSElement is implemented by every element of an RSS document.
+type RSSElement interface {
+	IsValid() bool
+}
+
+// RSS is the top-level element of an RSS 2.0 document.
+type RSS struct {
+	XMLName xml.Name `xml:"rss"`
+	Version Version  `xml:"version,attr"`
+	XMLLang string   `xml:"http://www.w3.org/XML/1998/namespace lang,attr,omitempty"`
+	Channel *Channel `xml:"channel"`
+
+	// Namespaces are the namespace declarations of the <rss> element (e.g.
+	// xmlns:itunes), in document order. Each is named as written, e.g.
+	// {Local: "xmlns:itunes"}, and is re-emitted when the document is
+	// marshaled.
+	Namespaces []xml.Attr `xml:"-"`
+
+	// encoding records the character encoding of the parsed document.
+	encoding docEncoding
+}
+
+// Version is the version of RSS to which the document conforms.
+type Version string
+
+// IsValid returns true if the version is "2.0".
+func (r Version) IsValid() bool {
+	return r == "2.0"
+}
+
+// Channel contains metadata about the feed and its contents.
+type Channel struct {
+	XMLName        xml.Name       `xml:"channel"`
+	XMLLang        string         `xml:"http://www.w3.org/XML/1998/namespace lang,attr,omitempty"`
+	Title          Title          `xml:"title"`
+	Link           Link           `xml:"link"`
+	Description    Description    `xml:"description"`
+	Language       Language       `xml:"language,omitempty"`
+	Copyright      Copyright      `xml:"copyright,omitempty"`
+	ManagingEditor ManagingEditor `xml:"managingEditor,omitempty"`
+	WebMaster      WebMaster      `xml:"webMaster,omitempty"`
+	PubDate        PubDate        `xml:"pubDate,omitempty"`
+	LastBuildDate  LastBuildDate  `xml:"lastBuildDate,omitempty"`
+	Category       []*Category    `xml:"category,omitempty"`
+	Generator      Generator      `xml:"generator,omitempty"`
+	Docs           Docs           `xml:"docs,omitempty"`
+	Cloud          *Cloud         `xml:"cloud,omitempty"`
+	TTL            TTL            `xml:"ttl,omitempty"`
+	Image          *Image         `xml:"image,omitempty"`
+	Rating         Rating         `xml:"rating,omitempty"`
+	TextInput      *TextInput     `xml:"textInput,omitempty"`
+	SkipHours      *SkipHours     `xml:"skipHours,omitempty"`
+	SkipDays       *SkipDays      `xml:"skipDays,omitempty"`
+	Item           []*Item        `xml:"item,omitempty"`
+	Extensions     []*Extension   `xml:",any"`
+
+	// order records the names of the child elements in the order in which
+	// they appeared in the source document.
+	order []xml.Name
+}
+
+// Item represents a story, much like a story in a newspaper or magazine.
+type Item struct {
+	XMLName        xml.Name          `xml:"item"`
+	XMLLang        string            `xml:"http://www.w3.org/XML/1998/namespace lang,attr,omitempty"`
+	Title          Title             `xml:"title,omitempty"`
+	Link           Link              `xml:"link,omitempty"`
+	Description    Description       `xml:"description,omitempty"`
+	Author         Author            `xml:"author,omitempty"`
+	Category       []*Category       `xml:"category,omitempty"`
+	Comments       Comments          `xml:"comments,omitempty"`
+	Enclosure      *Enclosure        `xml:"enclosure,omitempty"`
+	GUID           *GUID             `xml:"guid,omitempty"`
+	PubDate        PubDate           `xml:"pubDate,omitempty"`
+	Source         *Source           `xml:"source,omitempty"`
+	ContentEncoded ContentEncoded    `xml:"http://purl.org/rss/1.0/modules/content/ encoded,omitempty"`
+	MediaContent   []*MediaContent   `xml:"http://search.yahoo.com/mrss/ content,omitempty"`
+	MediaThumbnail []*MediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail,omitempty"`
+	Extensions     []*Extension      `xml:",any"`
+
+	// order records the names of the child elements in the order in which
+	// they appeared in the source document.
+	order []xml.Name
+}
+
+// IsValid returns true if the item has a title or a description. At least one
+// of them must be present and not consist only of whitespace.
+func (i *Item) IsValid() bool {
+	return i.Title.IsValid() || strings.TrimSpace(string(i.Description)) != ""
+}
+
+// EffectiveLanguage returns the language of the item. If the item does not
+// declare an xml:lang attribute, the language is inherited from c: its xml:lang
+// attribute or, failing that, its <language> element.
+func (i *Item) EffectiveLanguage(c *Channel) string {
+	if i.XMLLang != "" {
+		return i.XMLLang
+	}
+	if c == nil {
+		return ""
+	}
+	if c.XMLLang != "" {
+		return c.XMLLang
+	}
+	return string(c.Language)
+}
+
+// Extension is an element that is not defined by the RSS 2.0 specification,
+// such as an element from a namespace module.
+type Extension struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr `xml:",any,attr"`
+	InnerXML string     `xml:",innerxml"`
+}
+
+// Title is the name of the channel or item.
+type Title string
+
+// IsValid returns true if the title is not empty after trimming whitespace.
+func (r Title) IsValid() bool {
+	return strings.TrimSpace(string(r)) != ""
+}
+
+// Trimmed returns the title with leading and trailing whitespace removed and
+// each internal run of whitespace, such as a line break and indentation,
+// replaced by a single space.
+func (r Title) Trimmed() Title {
+	return Title(collapseSpace(string(r)))
+}
+
+// Link is the URL of the HTML website corresponding to the channel or item.
+type Link string
+
+// IsValid returns true if the link is an absolute URL.
+func (r Link) IsValid() bool {
+	return IsValidURL(string(r))
+}
+
+// Description is a phrase or sentence describing the channel or item.
+type Description string
+
+// Trimmed returns the description with leading and trailing whitespace
+// removed and each internal run of whitespace replaced by a single space.
+// Note that this changes the rendering of HTML that preserves whitespace,
+// such as <pre> elements.
+func (r Description) Trimmed() Description {
+	return Description(collapseSpace(string(r)))
+}
+
+// Language is the language the channel is written in.
+type Language string
+
+// IsValid returns true if the language is empty or a language tag whose
+// primary subtag is an ISO 639 language code, optionally followed by a region
+// or other subtags (e.g. "en" or "en-us"). Case is ignored.
+func (r Language) IsValid() bool {
+	if r == "" {
+		return true
+	}
+	primary, _, _ := strings.Cut(string(r), "-")
+	if _, err := language.ParseBase(primary); err != nil {
+		return false
+	}
+	_, err := language.Parse(string(r))
+	return err == nil
+}
+
+// Copyright is the copyright notice for content in the channel.
+type Copyright string
+
+// IsValid returns true if the copyright notice is not empty. The notice is
+// free-form text.
+func (r Copyright) IsValid() bool {
+	return r != ""
+}
+
+// ManagingEditor is the email address for the person responsible for
+// editorial content.
+type ManagingEditor string
+
+// WebMaster is the email address for the person responsible for technical
+// issues relating to the channel.
+type WebMaster string
+
+// PubDate is the publication date for the content in the channel or item.
+type PubDate string
+
+// LastBuildDate is the last time the content of the channel changed.
+type LastBuildDate string
+
+// Category specifies one or more categories that the channel or item belongs
+// to.
+type Category struct {
+	XMLName xml.Name `xml:"category"`
+	Domain  string   `xml:"domain,attr,omitempty"`
+	Value   string   `xml:",chardata"`
+}
+
+// Generator is a string indicating the program used to generate the channel.
+type Generator string
+
+// Docs is a URL that points to the documentation for the format used in the
+// RSS file.
+type Docs string
+
+// Cloud allows processes to register with a cloud to be notified of updates to
+// the channel.
+type Cloud struct {
+	XMLName           xml.Name `xml:"cloud"`
+	Domain            string   `xml:"domain,attr"`
+	Port              string   `xml:"port,attr"`
+	Path              string   `xml:"path,attr"`
+	RegisterProcedure string   `xml:"registerProcedure,attr"`
+	Protocol          string   `xml:"protocol,attr"`
+}
+
+// TTL (time to live) is the number of minutes that indicates how long a
+// channel can be cached before refreshing from the source.
+type TTL string
+
+// Image specifies a GIF, JPEG or PNG image that can be displayed with the
+// channel.
+type Image struct {
+	XMLName     xml.Name    `xml:"image"`
+	URL         URL         `xml:"url"`
+	Title       Title       `xml:"title"`
+	Link        Link        `xml:"link"`
+	Width       Width       `xml:"width,omitempty"`
+	Height      Height      `xml:"height,omitempty"`
+	Description Description `xml:"description,omitempty"`
+}
+
+// URL is the URL of a GIF, JPEG or PNG image that represents the channel.
+type URL string
+
+// IsValid returns true if the URL is an absolute URL.
+func (r URL) IsValid() bool {
+	return IsValidURL(string(r))
+}
+
+// MaxImageWidth and MaxImageHeight are the maximum dimensions of the image
+// of a channel, in pixels.
+const (
+	MaxImageWidth  = 144
+	MaxImageHeight = 400
+)
+
+// Width is the width of the image in pixels.
+type Width string
+
+// IsValid returns true if the width is empty or a number of pixels no greater
+// than MaxImageWidth.
+func (r Width) IsValid() bool {
+	return r.validate() == nil
+}
+
+func (r Width) validate() error {
+	return validateDimension(string(r), MaxImageWidth)
+}
+
+// Height is the height of the image in pixels.
+type Height string
+
+// IsValid returns true if the height is empty or a number of pixels no
+// greater than MaxImageHeight.
+func (r Height) IsValid() bool {
+	return r.validate() == nil
+}
+
+func (r Height) validate() error {
+	return validateDimension(string(r), MaxImageHeight)
+}
+
+// validateDimension returns an error wrapping ErrNotANumber if the image
+// dimension s is not a number, or ErrExceedsMaximum if it exceeds max. An
+// empty dimension is valid, as the element is optional.
+func validateDimension(s string, max uint64) error {
+	if s == "" {
+		return nil
+	}
+	n, err := strconv.ParseUint(strings.TrimSpace(s), 10, 64)
+	if err != nil {
+		return fmt.Errorf("%q %w", s, ErrNotANumber)
+	}
+	if n > max {
+		return fmt.Errorf("%d %w %d", n, ErrExceedsMaximum, max)
+	}
+	return nil
+}
+
+// Rating is the PICS rating for the channel.
+type Rating string
+
+// IsValid returns true if the rating is empty or has the structure of a PICS
+// label list: it starts with "(PICS-1.1" and its parentheses, outside of
+// quoted strings, are balanced. The content of the labels is not checked.
+//
+// See: https://www.w3.org/TR/REC-PICS-labels
+func (r Rating) IsValid() bool {
+	s := strings.TrimSpace(string(r))
+	if s == "" {
+		return true
+	}
+	if !strings.HasPrefix(s, "(PICS-1.1") {
+		return false
+	}
+	depth := 0
+	quoted := false
+	for _, c := range s {
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth < 0 {
+				return false
+			}
+		}
+	}
+	return depth == 0 && !quoted
+}
+
+// TextInput specifies a text input box that can be displayed with the
+// channel.
+type TextInput struct {
+	XMLName     xml.Name    `xml:"textInput"`
+	Title       Title       `xml:"title"`
+	Description Description `xml:"description"`
+	Name        string      `xml:"name"`
+	Link        Link        `xml:"link"`
+}
+
+// IsValid returns true if the text input has a title, a description and a
+// name, and its link is an absolute URL. All four are required.
+func (r *TextInput) IsValid() bool {
+	var v validator
+	v.textInput(r)
+	return len(v.errs) == 0
+}
+
+// SkipHours is a hint for aggregators telling them which hours they can skip.
+type SkipHours struct {
+	XMLName xml.Name `xml:"skipHours"`
+	Hour    []Hour   `xml:"hour"`
+}
+
+// IsValid returns true if there are at most 24 hours, each of which is valid
+// and appears only once.
+func (r SkipHours) IsValid() bool {
+	if len(r.Hour) > 24 {
+		return false
+	}
+	seen := make(map[int]bool, len(r.Hour))
+	for _, h := range r.Hour {
+		n, err := h.Int()
+		if err != nil || !h.IsValid() || seen[n] {
+			return false
+		}
+		seen[n] = true
+	}
+	return true
+}
+
+// Hour is an hour of the day, in GMT, during which aggregators may not read
+// the channel.
+type Hour string
+
+// IsValid returns true if the hour is a number between 0 and 23.
+func (r Hour) IsValid() bool {
+	n, err := r.Int()
+	return err == nil && n >= 0 && n <= 23
+}
+
+// Int returns the hour as an int.
+func (r Hour) Int() (int, error) {
+	return strconv.Atoi(string(r))
+}
+
+// SkipDays is a hint for aggregators telling them which days they can skip.
+type SkipDays struct {
+	XMLName xml.Name `xml:"skipDays"`
+	Day     []Day    `xml:"day"`
+}
+
+// Day is a day of the week during which aggregators may not read the channel.
+type Day string
+
+// Author is the email address of the author of the item.
+type Author string
+
+// Comments is the URL of a page for comments relating to the item.
+type Comments string
+
+// IsValid returns true if the comments URL is empty or an absolute URL. The
+// element is optional.
+func (r Comments) IsValid() bool {
+	return r == "" || IsValidURL(string(r))
+}
+
+// Enclosure describes a media object that is attached to the item.
+type Enclosure struct {
+	XMLName xml.Name `xml:"enclosure"`
+	URL     URL      `xml:"url,attr"`
+	Length  string   `xml:"length,attr"`
+	Type    string   `xml:"type,attr"`
+}
+
+// GUID is a string that uniquely identifies the item.
+type GUID struct {
+	XMLName     xml.Name `xml:"guid"`
+	IsPermaLink string   `xml:"isPermaLink,attr,omitempty"`
+	Value       string   `xml:",chardata"`
+}
+
+// IsValid returns true if the isPermaLink attribute of the guid is absent,
+// which means "true", or is "true" or "false", ignoring case.
+func (r *GUID) IsValid() bool {
+	p := strings.TrimSpace(r.IsPermaLink)
+	return p == "" || strings.EqualFold(p, "true") || strings.EqualFold(p, "false")
+}
+
+// Source is the RSS channel that the item came from.
+type Source struct {
+	XMLName xml.Name `xml:"source"`
+	// URL is the URL of the XML of the source channel. It is required.
+	URL URL `xml:"url,attr"`
+	// Value is the title of the source channel.
+	Value string `xml:",chardata"`
+}
+
+// IsValid returns true if the source has a url attribute that is an absolute
+// URL.
+func (r *Source) IsValid() bool {
+	return r.URL.IsValid()
+}
+
+// collapseSpace trims s and replaces each run of whitespace in it by a single
+// space.
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// IsValidURL returns true if s is an absolute URL with a non-empty scheme and
+// host. Relative references (e.g. "/path"), scheme-relative references (e.g.
+// "//example.com") and opaque URLs (e.g. "mailto:x@y.com") are rejected.
+func IsValidURL(s string) bool {
+	u, err := url.ParseRequestURI(s)
+	if err != nil {
+		return false
+	}
+	return u.Scheme != "" && u.Host != ""
+}
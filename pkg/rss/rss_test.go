@@ -0,0 +1,323 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestIsValidURL(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"http://a.com", true},
+		{"https://a.com/feed.xml?x=1", true},
+		{"//a.com", false},
+		{"/path", false},
+		{"mailto:x@y.com", false},
+		{"", false},
+		{"a.com", false},
+	}
+	for _, tt := range tests {
+		if got := IsValidURL(tt.in); got != tt.want {
+			t.Errorf("IsValidURL(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLinkIsValid(t *testing.T) {
+	if !Link("http://a.com").IsValid() {
+		t.Error("expected absolute link to be valid")
+	}
+	if Link("/path").IsValid() {
+		t.Error("expected relative link to be invalid")
+	}
+}
+
+func TestURLIsValid(t *testing.T) {
+	if !URL("http://a.com/logo.png").IsValid() {
+		t.Error("expected absolute url to be valid")
+	}
+	if URL("//a.com/logo.png").IsValid() {
+		t.Error("expected scheme-relative url to be invalid")
+	}
+}
+
+func TestCommentsIsValid(t *testing.T) {
+	tests := []struct {
+		comments Comments
+		want     bool
+	}{
+		{"http://a.com/comments/1", true},
+		{"", true},
+		{"see the forum", false},
+	}
+	for _, tt := range tests {
+		if got := tt.comments.IsValid(); got != tt.want {
+			t.Errorf("Comments(%q).IsValid() = %v, want %v", tt.comments, got, tt.want)
+		}
+	}
+}
+
+func TestGUIDIsValid(t *testing.T) {
+	tests := []struct {
+		isPermaLink string
+		want        bool
+	}{
+		{"", true},
+		{"true", true},
+		{"false", true},
+		{"TRUE", true},
+		{"False", true},
+		{"yes", false},
+		{"1", false},
+	}
+	for _, tt := range tests {
+		g := &GUID{IsPermaLink: tt.isPermaLink, Value: "http://example.com/1"}
+		if got := g.IsValid(); got != tt.want {
+			t.Errorf("GUID{IsPermaLink: %q}.IsValid() = %v, want %v", tt.isPermaLink, got, tt.want)
+		}
+	}
+}
+
+func TestEffectiveLanguage(t *testing.T) {
+	tests := []struct {
+		name string
+		item *Item
+		c    *Channel
+		want string
+	}{
+		{"inherit language", &Item{}, &Channel{Language: "en-us"}, "en-us"},
+		{"inherit xml:lang", &Item{}, &Channel{XMLLang: "fr", Language: "en-us"}, "fr"},
+		{"override", &Item{XMLLang: "de"}, &Channel{Language: "en-us"}, "de"},
+		{"nil channel", &Item{}, nil, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.item.EffectiveLanguage(tt.c); got != tt.want {
+			t.Errorf("%s: EffectiveLanguage() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestXMLLangRoundTrip(t *testing.T) {
+	const doc = `<rss version="2.0" xml:lang="en">
+  <channel xml:lang="en-us">
+    <title>t</title>
+    <item xml:lang="fr"><title>i</title></item>
+    <item><title>j</title></item>
+  </channel>
+</rss>`
+	r, err := Parse(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.XMLLang != "en" || r.Channel.XMLLang != "en-us" || r.Channel.Item[0].XMLLang != "fr" {
+		t.Fatalf("xml:lang not captured: %q %q %q", r.XMLLang, r.Channel.XMLLang, r.Channel.Item[0].XMLLang)
+	}
+	if got := r.Channel.Item[1].EffectiveLanguage(r.Channel); got != "en-us" {
+		t.Errorf("EffectiveLanguage() = %q, want %q", got, "en-us")
+	}
+	for _, opts := range []MarshalOptions{{}, {PreserveOrder: true}} {
+		b, err := opts.Marshal(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range []string{`<rss version="2.0" xml:lang="en">`, `<channel xml:lang="en-us">`, `<item xml:lang="fr">`} {
+			if !bytes.Contains(b, []byte(want)) {
+				t.Errorf("%+v: output does not contain %s:\n%s", opts, want, b)
+			}
+		}
+	}
+}
+
+func TestMediaRoundTrip(t *testing.T) {
+	const doc = `<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/">
+  <channel>
+    <title>Photos</title>
+    <item>
+      <title>Sunset</title>
+      <media:content url="http://example.com/sunset.jpg" type="image/jpeg" medium="image" width="1024" height="768"/>
+      <media:thumbnail url="http://example.com/sunset-thumb.jpg" width="75" height="50"/>
+    </item>
+  </channel>
+</rss>`
+	for _, opts := range []MarshalOptions{{}, {PreserveOrder: true}} {
+		r, err := Parse(strings.NewReader(doc))
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := opts.Marshal(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r, err = Parse(bytes.NewReader(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+		item := r.Channel.Item[0]
+		if len(item.MediaContent) != 1 || len(item.MediaThumbnail) != 1 {
+			t.Fatalf("%+v: got %d media:content and %d media:thumbnail, want 1 each:\n%s", opts, len(item.MediaContent), len(item.MediaThumbnail), b)
+		}
+		mc := item.MediaContent[0]
+		if mc.URL != "http://example.com/sunset.jpg" || mc.Type != "image/jpeg" || mc.Medium != "image" || mc.Width != "1024" || mc.Height != "768" {
+			t.Errorf("%+v: media:content = %+v", opts, mc)
+		}
+		if mt := item.MediaThumbnail[0]; mt.URL != "http://example.com/sunset-thumb.jpg" || mt.Width != "75" || mt.Height != "50" {
+			t.Errorf("%+v: media:thumbnail = %+v", opts, mt)
+		}
+		if len(item.Extensions) != 0 {
+			t.Errorf("%+v: unexpected extensions %+v", opts, item.Extensions)
+		}
+	}
+}
+
+func TestThumbnail(t *testing.T) {
+	const (
+		thumb = "http://example.com/thumb.jpg"
+		photo = "http://example.com/photo.png"
+		logo  = "http://example.com/logo.gif"
+	)
+	c := &Channel{Image: &Image{URL: logo}}
+	tests := []struct {
+		name string
+		item *Item
+		c    *Channel
+		want string
+	}{
+		{"media thumbnail", &Item{MediaThumbnail: []*MediaThumbnail{{URL: thumb}}, Enclosure: &Enclosure{URL: photo, Type: "image/png"}}, c, thumb},
+		{"media content image", &Item{MediaContent: []*MediaContent{{URL: "http://example.com/a.mp4", Type: "video/mp4"}, {URL: photo, Medium: "image"}}}, c, photo},
+		{"image enclosure", &Item{Enclosure: &Enclosure{URL: photo, Type: "image/png"}}, c, photo},
+		{"untyped image enclosure", &Item{Enclosure: &Enclosure{URL: photo}}, c, photo},
+		{"audio enclosure", &Item{Enclosure: &Enclosure{URL: "http://example.com/a.mp3", Type: "audio/mpeg"}}, c, logo},
+		{"channel image", &Item{}, c, logo},
+		{"no image", &Item{}, &Channel{}, ""},
+		{"nil channel", &Item{}, nil, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.item.Thumbnail(tt.c); got != tt.want {
+			t.Errorf("%s: Thumbnail() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestHourIsValid(t *testing.T) {
+	tests := []struct {
+		in   Hour
+		want bool
+	}{
+		{"0", true},
+		{"23", true},
+		{"24", false},
+		{"-1", false},
+		{"noon", false},
+	}
+	for _, tt := range tests {
+		if got := tt.in.IsValid(); got != tt.want {
+			t.Errorf("Hour(%q).IsValid() = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSkipHoursIsValid(t *testing.T) {
+	all := make([]Hour, 24)
+	for i := range all {
+		all[i] = Hour(strconv.Itoa(i))
+	}
+	tests := []struct {
+		name string
+		in   []Hour
+		want bool
+	}{
+		{"empty", nil, true},
+		{"some", []Hour{"0", "6", "12"}, true},
+		{"all", all, true},
+		{"duplicate", []Hour{"1", "2", "1"}, false},
+		{"duplicate with leading zero", []Hour{"1", "01"}, false},
+		{"out of range", []Hour{"24"}, false},
+		{"25 elements", append(append([]Hour{}, all...), "0"), false},
+	}
+	for _, tt := range tests {
+		if got := (SkipHours{Hour: tt.in}).IsValid(); got != tt.want {
+			t.Errorf("%s: SkipHours.IsValid() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestItemIsValid(t *testing.T) {
+	tests := []struct {
+		item *Item
+		want bool
+	}{
+		{&Item{Title: "t"}, true},
+		{&Item{Description: "d"}, true},
+		{&Item{Title: "t", Description: "d"}, true},
+		{&Item{Link: "http://a.com"}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.item.IsValid(); got != tt.want {
+			t.Errorf("%+v.IsValid() = %v, want %v", tt.item, got, tt.want)
+		}
+	}
+}
+
+func TestVersionIsValid(t *testing.T) {
+	for _, tt := range []struct {
+		in   Version
+		want bool
+	}{{"2.0", true}, {"", false}, {"1.0", false}, {"2.0.1", false}} {
+		if got := tt.in.IsValid(); got != tt.want {
+			t.Errorf("Version(%q).IsValid() = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRatingIsValid(t *testing.T) {
+	tests := []struct {
+		rating Rating
+		want   bool
+	}{
+		{"", true},
+		{`(PICS-1.1 "http://www.classify.org/safesurf/" l r (SS~~000 1))`, true},
+		{`(PICS-1.1 "http://www.icra.org/ratingsv02.html" l gen true for "http://a.com/(x" r (cz 1 lz 1 nz 1 oz 1 vz 1))`, true},
+		{"family friendly", false},
+		{`(PICS-1.1 "http://www.classify.org/safesurf/" l r (SS~~000 1)`, false},
+		{`(PICS-1.1 "http://www.classify.org/safesurf/ l r (SS~~000 1))`, false},
+	}
+	for _, tt := range tests {
+		if got := tt.rating.IsValid(); got != tt.want {
+			t.Errorf("Rating(%q).IsValid() = %v, want %v", tt.rating, got, tt.want)
+		}
+	}
+}
+
+func TestTitleTrimmed(t *testing.T) {
+	tests := []struct {
+		title Title
+		want  Title
+	}{
+		{"Star City", "Star City"},
+		{"  Star City\n", "Star City"},
+		{"Star\n\t\tCity:   How Yuri\r\nGagarin", "Star City: How Yuri Gagarin"},
+		{"\n  \n", ""},
+	}
+	for _, tt := range tests {
+		if got := tt.title.Trimmed(); got != tt.want {
+			t.Errorf("Title(%q).Trimmed() = %q, want %q", tt.title, got, tt.want)
+		}
+		if got, want := tt.title.IsValid(), tt.want != ""; got != want {
+			t.Errorf("Title(%q).IsValid() = %v, want %v", tt.title, got, want)
+		}
+	}
+}
+
+func TestDescriptionTrimmed(t *testing.T) {
+	d := Description("\n  Sky watchers in Europe,\n  Asia, and parts of Alaska.\n")
+	if got, want := d.Trimmed(), Description("Sky watchers in Europe, Asia, and parts of Alaska."); got != want {
+		t.Errorf("Trimmed() = %q, want %q", got, want)
+	}
+}
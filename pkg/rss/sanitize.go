@@ -0,0 +1,159 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// allowedAttrs maps the elements kept by SanitizeHTML to the attributes kept
+// on them.
+var allowedAttrs = map[atom.Atom][]string{
+	atom.A:          {"href", "title"},
+	atom.Abbr:       {"title"},
+	atom.B:          nil,
+	atom.Blockquote: {"cite"},
+	atom.Br:         nil,
+	atom.Code:       nil,
+	atom.Dd:         nil,
+	atom.Del:        nil,
+	atom.Div:        nil,
+	atom.Dl:         nil,
+	atom.Dt:         nil,
+	atom.Em:         nil,
+	atom.Figcaption: nil,
+	atom.Figure:     nil,
+	atom.H1:         nil,
+	atom.H2:         nil,
+	atom.H3:         nil,
+	atom.H4:         nil,
+	atom.H5:         nil,
+	atom.H6:         nil,
+	atom.Hr:         nil,
+	atom.I:          nil,
+	atom.Img:        {"src", "alt", "title", "width", "height"},
+	atom.Li:         nil,
+	atom.Ol:         nil,
+	atom.P:          nil,
+	atom.Pre:        nil,
+	atom.Q:          {"cite"},
+	atom.S:          nil,
+	atom.Small:      nil,
+	atom.Span:       nil,
+	atom.Strong:     nil,
+	atom.Sub:        nil,
+	atom.Sup:        nil,
+	atom.Table:      nil,
+	atom.Tbody:      nil,
+	atom.Td:         {"colspan", "rowspan"},
+	atom.Tfoot:      nil,
+	atom.Th:         {"colspan", "rowspan"},
+	atom.Thead:      nil,
+	atom.Tr:         nil,
+	atom.U:          nil,
+	atom.Ul:         nil,
+}
+
+// droppedElements are the elements removed by SanitizeHTML together with
+// their content. Other disallowed elements are removed, but their content is
+// kept.
+var droppedElements = map[atom.Atom]bool{
+	atom.Applet:   true,
+	atom.Embed:    true,
+	atom.Form:     true,
+	atom.Frame:    true,
+	atom.Frameset: true,
+	atom.Iframe:   true,
+	atom.Noscript: true,
+	atom.Object:   true,
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Template: true,
+	atom.Textarea: true,
+}
+
+// urlAttrs are the attributes whose values are URLs.
+var urlAttrs = map[string]bool{"href": true, "src": true, "cite": true}
+
+// SanitizeHTML returns s with all but an allow-list of elements and
+// attributes removed. Scripts, styles, iframes, embedded objects and forms
+// are removed along with their content, as are event handler attributes and
+// URLs with a scheme other than http, https or mailto. Comments are removed.
+func SanitizeHTML(s string) string {
+	var b strings.Builder
+	z := html.NewTokenizer(strings.NewReader(s))
+	// depth is the number of dropped elements enclosing the current token.
+	depth := 0
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			return b.String()
+		case html.TextToken:
+			if depth == 0 {
+				b.WriteString(html.EscapeString(string(z.Text())))
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			t := z.Token()
+			if droppedElements[t.DataAtom] {
+				if tt == html.StartTagToken {
+					depth++
+				}
+				continue
+			}
+			allowed, ok := allowedAttrs[t.DataAtom]
+			if depth > 0 || !ok {
+				continue
+			}
+			b.WriteString("<" + t.Data)
+			for _, a := range t.Attr {
+				if a.Namespace == "" && contains(allowed, a.Key) && (!urlAttrs[a.Key] || safeURL(a.Val)) {
+					b.WriteString(" " + a.Key + `="` + html.EscapeString(a.Val) + `"`)
+				}
+			}
+			if tt == html.SelfClosingTagToken {
+				b.WriteString("/")
+			}
+			b.WriteString(">")
+		case html.EndTagToken:
+			t := z.Token()
+			if droppedElements[t.DataAtom] {
+				if depth > 0 {
+					depth--
+				}
+				continue
+			}
+			if _, ok := allowedAttrs[t.DataAtom]; ok && depth == 0 {
+				b.WriteString("</" + t.Data + ">")
+			}
+		}
+	}
+}
+
+// safeURL returns true if s is a relative URL or an absolute URL with the
+// http, https or mailto scheme.
+func safeURL(s string) bool {
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "", "http", "https", "mailto":
+		return true
+	}
+	return false
+}
+
+func contains(a []string, s string) bool {
+	for _, x := range a {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import "testing"
+
+func TestSanitizeHTML(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{
+			`<p>Hello, <b>world</b>!</p>`,
+			`<p>Hello, <b>world</b>!</p>`,
+		},
+		{
+			`<p>a</p><script>alert("x")</script><p>b</p>`,
+			`<p>a</p><p>b</p>`,
+		},
+		{
+			`<a href="http://a.com/" onclick="steal()">link</a>`,
+			`<a href="http://a.com/">link</a>`,
+		},
+		{
+			`<a href="javascript:alert(1)">link</a>`,
+			`<a>link</a>`,
+		},
+		{
+			`<img src="/1.jpg" onerror="steal()" alt="1"/>`,
+			`<img src="/1.jpg" alt="1"/>`,
+		},
+		{
+			`before<iframe src="http://evil.com/"></iframe>after`,
+			`beforeafter`,
+		},
+		{
+			`<font color="red">kept</font><style>p { color: red }</style><!-- comment -->`,
+			`kept`,
+		},
+		{
+			`<p style="x" class="y">1 &lt; 2 &amp; 3</p>`,
+			`<p>1 &lt; 2 &amp; 3</p>`,
+		},
+	}
+	for _, tt := range tests {
+		if got := SanitizeHTML(tt.in); got != tt.want {
+			t.Errorf("SanitizeHTML(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
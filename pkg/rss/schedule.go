@@ -0,0 +1,55 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Duration returns the time to live as a time.Duration.
+func (r TTL) Duration() (time.Duration, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(string(r)))
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(n) * time.Minute, nil
+}
+
+// ShouldRefreshAt returns false if t, in UTC, falls in an hour listed in
+// <skipHours> or on a day listed in <skipDays>, during which aggregators
+// should not read the channel.
+func (c *Channel) ShouldRefreshAt(t time.Time) bool {
+	t = t.UTC()
+	if c.SkipHours != nil {
+		for _, h := range c.SkipHours.Hour {
+			if n, err := h.Int(); err == nil && n == t.Hour() {
+				return false
+			}
+		}
+	}
+	if c.SkipDays != nil {
+		for _, d := range c.SkipDays.Day {
+			if strings.EqualFold(strings.TrimSpace(string(d)), t.Weekday().String()) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// NextRefreshAt returns the earliest time at or after t at which the channel
+// should be refreshed (see ShouldRefreshAt). If every hour of the week is
+// skipped, t is returned.
+func (c *Channel) NextRefreshAt(t time.Time) time.Time {
+	next := t
+	for i := 0; i < 7*24; i++ {
+		if c.ShouldRefreshAt(next) {
+			return next
+		}
+		next = next.Truncate(time.Hour).Add(time.Hour)
+	}
+	return t
+}
@@ -0,0 +1,90 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"testing"
+	"time"
+)
+
+func TestShouldRefreshAt(t *testing.T) {
+	c := &Channel{
+		SkipHours: &SkipHours{Hour: []Hour{"0", "1", "13"}},
+		SkipDays:  &SkipDays{Day: []Day{"Saturday"}},
+	}
+	est := time.FixedZone("EST", -5*60*60)
+	tests := []struct {
+		t    time.Time
+		want bool
+	}{
+		// Friday, 12:59:59 UTC
+		{time.Date(2022, time.January, 7, 12, 59, 59, 0, time.UTC), true},
+		// Friday, 13:00:00 UTC
+		{time.Date(2022, time.January, 7, 13, 0, 0, 0, time.UTC), false},
+		// Friday, 13:59:59 UTC
+		{time.Date(2022, time.January, 7, 13, 59, 59, 0, time.UTC), false},
+		// Friday, 14:00:00 UTC
+		{time.Date(2022, time.January, 7, 14, 0, 0, 0, time.UTC), true},
+		// Friday, 08:30 EST is 13:30 UTC.
+		{time.Date(2022, time.January, 7, 8, 30, 0, 0, est), false},
+		// Saturday, 12:00 UTC
+		{time.Date(2022, time.January, 8, 12, 0, 0, 0, time.UTC), false},
+		// Friday, 20:00 EST is Saturday, 01:00 UTC.
+		{time.Date(2022, time.January, 7, 20, 0, 0, 0, est), false},
+		// Sunday, 02:00 UTC
+		{time.Date(2022, time.January, 9, 2, 0, 0, 0, time.UTC), true},
+	}
+	for _, tt := range tests {
+		if got := c.ShouldRefreshAt(tt.t); got != tt.want {
+			t.Errorf("ShouldRefreshAt(%s) = %v, want %v", tt.t, got, tt.want)
+		}
+	}
+	if !(&Channel{}).ShouldRefreshAt(time.Now()) {
+		t.Error("ShouldRefreshAt() = false for a channel without skipHours or skipDays")
+	}
+}
+
+func TestNextRefreshAt(t *testing.T) {
+	c := &Channel{
+		SkipHours: &SkipHours{Hour: []Hour{"0", "1", "13"}},
+		SkipDays:  &SkipDays{Day: []Day{"Saturday"}},
+	}
+	tests := []struct {
+		t    time.Time
+		want time.Time
+	}{
+		{
+			time.Date(2022, time.January, 7, 12, 30, 0, 0, time.UTC),
+			time.Date(2022, time.January, 7, 12, 30, 0, 0, time.UTC),
+		},
+		{
+			time.Date(2022, time.January, 7, 13, 30, 0, 0, time.UTC),
+			time.Date(2022, time.January, 7, 14, 0, 0, 0, time.UTC),
+		},
+		{
+			// Saturday, then the skipped hours 00 and 01 of Sunday.
+			time.Date(2022, time.January, 8, 9, 15, 0, 0, time.UTC),
+			time.Date(2022, time.January, 9, 2, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		if got := c.NextRefreshAt(tt.t); !got.Equal(tt.want) {
+			t.Errorf("NextRefreshAt(%s) = %s, want %s", tt.t, got, tt.want)
+		}
+	}
+	all := &Channel{SkipDays: &SkipDays{Day: []Day{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"}}}
+	now := time.Date(2022, time.January, 7, 12, 30, 0, 0, time.UTC)
+	if got := all.NextRefreshAt(now); !got.Equal(now) {
+		t.Errorf("NextRefreshAt() = %s, want %s when every day is skipped", got, now)
+	}
+}
+
+func TestTTLDuration(t *testing.T) {
+	if d, err := TTL("60").Duration(); err != nil || d != time.Hour {
+		t.Errorf("Duration() = %s, %v; want 1h", d, err)
+	}
+	if _, err := TTL("an hour").Duration(); err == nil {
+		t.Error("expected error for non-numeric ttl")
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// StripElements removes the child elements with the given names from the
+// channel and from each of its items, and returns the number of elements
+// removed. A name is either the name of an RSS element, such as "author", or
+// a prefixed name, such as "dc:creator", whose prefix is declared on the
+// <rss> element. Prefixed names whose prefix is not declared match no
+// elements.
+//
+// The required elements of the channel (title, link and description) and
+// the elements that structure the document (channel, item, and the required
+// children of image and textInput) cannot be removed: if names includes one,
+// StripElements returns an error wrapping ErrRequiredElement and leaves r
+// unchanged.
+func (r *RSS) StripElements(names ...string) (int, error) {
+	required := requiredElements(reflect.TypeOf(Channel{}))
+	for _, t := range []reflect.Type{reflect.TypeOf(Image{}), reflect.TypeOf(TextInput{})} {
+		for name := range requiredElements(t) {
+			required[name] = true
+		}
+	}
+	required["channel"], required["item"] = true, true
+	var targets []xml.Name
+	for _, s := range names {
+		prefix, local, ok := strings.Cut(s, ":")
+		if !ok {
+			if required[s] {
+				return 0, fmt.Errorf("cannot strip %s: %w", s, ErrRequiredElement)
+			}
+			targets = append(targets, xml.Name{Local: s})
+			continue
+		}
+		for _, ns := range r.Namespaces {
+			if ns.Name.Local == "xmlns:"+prefix {
+				targets = append(targets, xml.Name{Space: ns.Value, Local: local})
+			}
+		}
+	}
+	if r.Channel == nil {
+		return 0, nil
+	}
+	c := r.Channel
+	n := stripElements(reflect.ValueOf(c).Elem(), &c.order, targets)
+	for _, item := range c.Item {
+		n += stripElements(reflect.ValueOf(item).Elem(), &item.order, targets)
+	}
+	return n, nil
+}
+
+// requiredElements returns the names of the child elements of the struct
+// type t that are not tagged omitempty.
+func requiredElements(t reflect.Type) map[string]bool {
+	names := make(map[string]bool)
+	_, fields := structFields(t)
+	for _, f := range fields {
+		if !f.attr && !f.any && !f.omitempty {
+			names[f.name.Local] = true
+		}
+	}
+	return names
+}
+
+// stripElements clears the child elements of the struct v named targets,
+// removes them from order and returns the number of elements removed.
+func stripElements(v reflect.Value, order *[]xml.Name, targets []xml.Name) int {
+	name, fields := structFields(v.Type())
+	var n int
+	for _, t := range targets {
+		j, ok := match(fields, name.Space, t)
+		if !ok {
+			continue
+		}
+		fv := v.Field(fields[j].index)
+		switch {
+		case fields[j].any:
+			var kept []*Extension
+			for _, x := range fv.Interface().([]*Extension) {
+				if x.XMLName == t {
+					n++
+					continue
+				}
+				kept = append(kept, x)
+			}
+			fv.Set(reflect.ValueOf(kept))
+		case fv.Kind() == reflect.Slice:
+			n += fv.Len()
+			fv.Set(reflect.Zero(fv.Type()))
+		case !fv.IsZero():
+			n++
+			fv.Set(reflect.Zero(fv.Type()))
+		}
+		var kept []xml.Name
+		for _, o := range *order {
+			if o != t {
+				kept = append(kept, o)
+			}
+		}
+		*order = kept
+	}
+	return n
+}
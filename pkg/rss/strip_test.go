@@ -0,0 +1,67 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestStripElements(t *testing.T) {
+	const doc = `<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/"><channel>
+<title>Liftoff News</title><link>http://liftoff.msfc.nasa.gov/</link><description>Liftoff</description>
+<managingEditor>editor@example.com</managingEditor>
+<item><title>a</title><author>a@example.com</author><dc:creator>A</dc:creator><category>x</category><category>y</category></item>
+<item><title>b</title><author>b@example.com</author></item>
+</channel></rss>`
+	tests := []struct {
+		names []string
+		n     int
+		gone  []string
+	}{
+		{[]string{"author"}, 2, []string{"<author>"}},
+		{[]string{"dc:creator", "managingEditor"}, 2, []string{"<dc:creator>", "<managingEditor>"}},
+		{[]string{"category"}, 2, []string{"<category>"}},
+		{[]string{"atom:link", "unknown"}, 0, nil},
+	}
+	for _, tt := range tests {
+		r := mustParse(t, doc)
+		n, err := r.StripElements(tt.names...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != tt.n {
+			t.Errorf("StripElements(%q) = %d, want %d", tt.names, n, tt.n)
+		}
+		out, err := MarshalOptions{PreserveOrder: true}.Marshal(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, tag := range tt.gone {
+			if strings.Contains(string(out), tag) {
+				t.Errorf("StripElements(%q): output contains %s:\n%s", tt.names, tag, out)
+			}
+		}
+		if err := r.Validate(); err != nil {
+			t.Errorf("StripElements(%q): Validate() = %v", tt.names, err)
+		}
+	}
+}
+
+func TestStripElementsRequired(t *testing.T) {
+	for _, name := range []string{"title", "link", "description", "channel", "item", "url", "name"} {
+		r := validFeed()
+		r.Channel.Item = []*Item{{Title: "a", Author: "a@example.com"}}
+		if _, err := r.StripElements("author", name); !errors.Is(err, ErrRequiredElement) {
+			t.Errorf("StripElements(%q) = %v, want ErrRequiredElement", name, err)
+		}
+		if r.Channel.Title == "" || r.Channel.Link == "" || r.Channel.Description == "" {
+			t.Errorf("StripElements(%q) changed the feed", name)
+		}
+		if len(r.Channel.Item) != 1 || r.Channel.Item[0].Author == "" {
+			t.Errorf("StripElements(%q) changed the items", name)
+		}
+	}
+}
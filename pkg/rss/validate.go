@@ -0,0 +1,120 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import "fmt"
+
+// Validate checks r against the RSS 2.0 specification. If r is invalid,
+// Validate returns ValidationErrors describing every invalid element, each of
+// which wraps one of the sentinel errors of this package, so that callers can
+// use errors.Is to distinguish failures.
+func (r *RSS) Validate() error {
+	var v validator
+	v.rss(r)
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+// IsValid returns true if r is a valid RSS 2.0 document.
+func (r *RSS) IsValid() bool {
+	return r.Validate() == nil
+}
+
+// validator accumulates the validation errors of an RSS document.
+type validator struct {
+	errs ValidationErrors
+}
+
+func (v *validator) add(path string, err error) {
+	v.errs = append(v.errs, &ValidationError{Path: path, Err: err})
+}
+
+// element validates value as the element name, using the validator of
+// ValidateElement, and adds its error, if any, at path.
+func (v *validator) element(path, name, value string) {
+	if err := elementValidators[name](value); err != nil {
+		v.add(path, err)
+	}
+}
+
+func (v *validator) rss(r *RSS) {
+	v.element("version", "version", string(r.Version))
+	if r.Channel == nil {
+		v.add("channel", ErrMissingChannel)
+		return
+	}
+	v.channel(r.Channel)
+}
+
+func (v *validator) channel(c *Channel) {
+	v.element("title", "title", string(c.Title))
+	v.element("link", "link", string(c.Link))
+	v.element("description", "description", string(c.Description))
+	v.element("language", "language", string(c.Language))
+	v.element("pubDate", "pubDate", string(c.PubDate))
+	v.element("lastBuildDate", "lastBuildDate", string(c.LastBuildDate))
+	if c.Image != nil {
+		v.image(c.Image)
+	}
+	v.element("rating", "rating", string(c.Rating))
+	if c.TextInput != nil {
+		v.textInput(c.TextInput)
+	}
+	if c.SkipHours != nil && !c.SkipHours.IsValid() {
+		v.add("skipHours", ErrInvalidSkipHours)
+	}
+	v.extensions(-1, c.Extensions)
+	for i, item := range c.Item {
+		v.item(i, item)
+	}
+}
+
+// image validates the image of the channel.
+func (v *validator) image(i *Image) {
+	v.element("image.url", "url", string(i.URL))
+	v.element("image.title", "title", string(i.Title))
+	v.element("image.link", "link", string(i.Link))
+	v.element("image.width", "width", string(i.Width))
+	v.element("image.height", "height", string(i.Height))
+}
+
+// textInput validates the text input of the channel.
+func (v *validator) textInput(t *TextInput) {
+	v.element("textInput.title", "title", string(t.Title))
+	v.element("textInput.description", "description", string(t.Description))
+	v.element("textInput.name", "name", t.Name)
+	v.element("textInput.link", "link", string(t.Link))
+}
+
+// item validates the item at index n. The path of the item is only formatted
+// if it is invalid, as feeds may have thousands of items.
+func (v *validator) item(n int, i *Item) {
+	if !i.IsValid() {
+		v.add(fmt.Sprintf("item[%d]", n), ErrMissingTitleOrDescription)
+	}
+	if i.Link != "" {
+		v.itemElement(n, "link", "link", string(i.Link))
+	}
+	v.itemElement(n, "comments", "comments", string(i.Comments))
+	if e := i.Enclosure; e != nil {
+		v.itemElement(n, "enclosure", "url", string(e.URL))
+	}
+	if g := i.GUID; g != nil && !g.IsValid() {
+		v.add(fmt.Sprintf("item[%d].guid", n), ErrInvalidPermaLink)
+	}
+	if s := i.Source; s != nil {
+		v.itemElement(n, "source", "url", string(s.URL))
+	}
+	v.extensions(n, i.Extensions)
+}
+
+// itemElement is like element for the child element of the item at index n
+// at path, formatting the path of the item only if value is invalid.
+func (v *validator) itemElement(n int, path, name, value string) {
+	if err := elementValidators[name](value); err != nil {
+		v.add(fmt.Sprintf("item[%d].%s", n, path), err)
+	}
+}
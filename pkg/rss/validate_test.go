@@ -0,0 +1,374 @@
+// Copyright 2022 Nickolas Kraus. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+package rss
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// validFeed returns a minimal valid feed.
+func validFeed() *RSS {
+	return &RSS{
+		Version: "2.0",
+		Channel: &Channel{
+			Title:       "Liftoff News",
+			Link:        "http://liftoff.msfc.nasa.gov/",
+			Description: "Liftoff to Space Exploration.",
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := validFeed().Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+	if err := mustParseFile(t, "../../test/data/rss-0.xml").Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+}
+
+func TestValidateErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(r *RSS)
+		path   string
+		want   error
+	}{
+		{"missing channel", func(r *RSS) { r.Channel = nil }, "channel", ErrMissingChannel},
+		{"missing version", func(r *RSS) { r.Version = "" }, "version", ErrMissingVersion},
+		{"unsupported version", func(r *RSS) { r.Version = "1.0" }, "version", ErrUnsupportedVersion},
+		{"missing title", func(r *RSS) { r.Channel.Title = "" }, "title", ErrMissingTitle},
+		{"missing link", func(r *RSS) { r.Channel.Link = "" }, "link", ErrMissingLink},
+		{"invalid link", func(r *RSS) { r.Channel.Link = "/path" }, "link", ErrInvalidURL},
+		{"missing description", func(r *RSS) { r.Channel.Description = "" }, "description", ErrMissingDescription},
+		{"blank description", func(r *RSS) { r.Channel.Description = " \n\t" }, "description", ErrMissingDescription},
+		{"invalid pubDate", func(r *RSS) { r.Channel.PubDate = "yesterday" }, "pubDate", ErrInvalidDate},
+		{"invalid lastBuildDate", func(r *RSS) { r.Channel.LastBuildDate = "today" }, "lastBuildDate", ErrInvalidDate},
+		{"invalid isPermaLink", func(r *RSS) {
+			r.Channel.Item = []*Item{{Title: "a", GUID: &GUID{IsPermaLink: "yes", Value: "1"}}}
+		}, "item[0].guid", ErrInvalidPermaLink},
+		{"invalid skipHours", func(r *RSS) { r.Channel.SkipHours = &SkipHours{Hour: []Hour{"1", "1"}} }, "skipHours", ErrInvalidSkipHours},
+	}
+	for _, tt := range tests {
+		r := validFeed()
+		tt.modify(r)
+		err := r.Validate()
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: Validate() = %v, want error matching %v", tt.name, err, tt.want)
+			continue
+		}
+		var errs ValidationErrors
+		if !errors.As(err, &errs) || len(errs) != 1 {
+			t.Errorf("%s: Validate() = %v, want a single ValidationError", tt.name, err)
+			continue
+		}
+		if errs[0].Path != tt.path {
+			t.Errorf("%s: Path = %q, want %q", tt.name, errs[0].Path, tt.path)
+		}
+		if r.IsValid() {
+			t.Errorf("%s: IsValid() = true, want false", tt.name)
+		}
+	}
+}
+
+func TestValidateSentinelsAreDistinct(t *testing.T) {
+	r := validFeed()
+	r.Channel.Title = ""
+	if err := r.Validate(); errors.Is(err, ErrMissingLink) || errors.Is(err, ErrMissingDescription) {
+		t.Errorf("Validate() = %v, must only match ErrMissingTitle", err)
+	}
+	seen := make(map[string]bool)
+	for _, err := range []error{ErrMissingChannel, ErrMissingVersion, ErrMissingTitle, ErrMissingLink, ErrMissingDescription, ErrMissingName, ErrMissingURL} {
+		if seen[err.Error()] {
+			t.Errorf("%q is the message of more than one sentinel error", err)
+		}
+		seen[err.Error()] = true
+	}
+}
+
+func TestValidateMultipleErrors(t *testing.T) {
+	r := validFeed()
+	r.Version = ""
+	r.Channel.Title = ""
+	r.Channel.Link = ""
+	err := r.Validate()
+	for _, want := range []error{ErrMissingVersion, ErrMissingTitle, ErrMissingLink} {
+		if !errors.Is(err, want) {
+			t.Errorf("Validate() = %v, want error matching %v", err, want)
+		}
+	}
+	if want := "version: missing required version; title: missing required title; link: missing required link"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestValidateItems(t *testing.T) {
+	r := validFeed()
+	r.Channel.Item = []*Item{
+		{Title: "Star City"},
+		{Description: "Sky watchers in Europe."},
+		{Link: "http://liftoff.msfc.nasa.gov/news/2003/news-laundry.asp"},
+		{Title: "The Engine That Does More", Description: "Before man travels to Mars."},
+	}
+	err := r.Validate()
+	if !errors.Is(err, ErrMissingTitleOrDescription) {
+		t.Fatalf("Validate() = %v, want error matching ErrMissingTitleOrDescription", err)
+	}
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Path != "item[2]" {
+		t.Errorf("Validate() = %v, want a single error for item[2]", err)
+	}
+	if r.IsValid() {
+		t.Error("IsValid() = true, want false")
+	}
+}
+
+func TestValidateBlankTitle(t *testing.T) {
+	r := validFeed()
+	r.Channel.Title = "\n  \n"
+	if err := r.Validate(); !errors.Is(err, ErrMissingTitle) {
+		t.Errorf("Validate() = %v, want error matching ErrMissingTitle", err)
+	}
+}
+
+func TestValidateRating(t *testing.T) {
+	r := validFeed()
+	r.Channel.Rating = "family friendly"
+	err := r.Validate()
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Path != "rating" || !errors.Is(err, ErrInvalidRating) {
+		t.Errorf("Validate() = %v, want a single ErrInvalidRating for rating", err)
+	}
+}
+
+func TestValidateTextInput(t *testing.T) {
+	tests := []struct {
+		name  string
+		input *TextInput
+		path  string
+		want  error
+	}{
+		{
+			"bad link",
+			&TextInput{Title: "Search", Description: "Search Liftoff News", Name: "q", Link: "search.cgi"},
+			"textInput.link",
+			ErrInvalidURL,
+		},
+		{
+			"missing name",
+			&TextInput{Title: "Search", Description: "Search Liftoff News", Link: "http://liftoff.msfc.nasa.gov/search.cgi"},
+			"textInput.name",
+			ErrMissingName,
+		},
+	}
+	for _, tt := range tests {
+		if tt.input.IsValid() {
+			t.Errorf("%s: IsValid() = true, want false", tt.name)
+		}
+		r := validFeed()
+		r.Channel.TextInput = tt.input
+		err := r.Validate()
+		var errs ValidationErrors
+		if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Path != tt.path || !errors.Is(err, tt.want) {
+			t.Errorf("%s: Validate() = %v, want a single error for %s", tt.name, err, tt.path)
+		}
+	}
+	valid := &TextInput{Title: "Search", Description: "Search Liftoff News", Name: "q", Link: "http://liftoff.msfc.nasa.gov/search.cgi"}
+	if !valid.IsValid() {
+		t.Error("IsValid() = false, want true")
+	}
+}
+
+func TestValidateImage(t *testing.T) {
+	tests := []struct {
+		name  string
+		image *Image
+		path  string
+		want  error
+	}{
+		{
+			"missing url",
+			&Image{Title: "Liftoff News", Link: "http://liftoff.msfc.nasa.gov/"},
+			"image.url",
+			ErrMissingURL,
+		},
+		{
+			"bad url",
+			&Image{URL: "not a url", Title: "Liftoff News", Link: "http://liftoff.msfc.nasa.gov/"},
+			"image.url",
+			ErrInvalidURL,
+		},
+		{
+			"missing title",
+			&Image{URL: "http://liftoff.msfc.nasa.gov/logo.png", Title: " ", Link: "http://liftoff.msfc.nasa.gov/"},
+			"image.title",
+			ErrMissingTitle,
+		},
+		{
+			"missing link",
+			&Image{URL: "http://liftoff.msfc.nasa.gov/logo.png", Title: "Liftoff News"},
+			"image.link",
+			ErrMissingLink,
+		},
+		{
+			"bad link",
+			&Image{URL: "http://liftoff.msfc.nasa.gov/logo.png", Title: "Liftoff News", Link: "/"},
+			"image.link",
+			ErrInvalidURL,
+		},
+	}
+	for _, tt := range tests {
+		r := validFeed()
+		r.Channel.Image = tt.image
+		err := r.Validate()
+		var errs ValidationErrors
+		if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Path != tt.path || !errors.Is(err, tt.want) {
+			t.Errorf("%s: Validate() = %v, want a single error for %s", tt.name, err, tt.path)
+		}
+	}
+}
+
+func TestValidateImageDimensions(t *testing.T) {
+	tests := []struct {
+		width  Width
+		height Height
+		want   string
+		is     error
+	}{
+		{"88", "31", "", nil},
+		{"abc", "", `image.width: "abc" is not a number`, ErrNotANumber},
+		{"200", "", "image.width: 200 exceeds maximum 144", ErrExceedsMaximum},
+		{"", "31.5", `image.height: "31.5" is not a number`, ErrNotANumber},
+		{"", "401", "image.height: 401 exceeds maximum 400", ErrExceedsMaximum},
+	}
+	for _, tt := range tests {
+		r := validFeed()
+		r.Channel.Image = &Image{URL: "http://liftoff.msfc.nasa.gov/logo.png", Title: "Liftoff News", Link: "http://liftoff.msfc.nasa.gov/", Width: tt.width, Height: tt.height}
+		err := r.Validate()
+		got := ""
+		if err != nil {
+			got = err.Error()
+		}
+		if got != tt.want {
+			t.Errorf("Validate() = %q, want %q", got, tt.want)
+		}
+		if tt.is != nil && !errors.Is(err, tt.is) {
+			t.Errorf("Validate() = %v, want error matching %v", err, tt.is)
+		}
+		if valid := tt.width.IsValid() && tt.height.IsValid(); valid != (tt.want == "") {
+			t.Errorf("IsValid(%q, %q) = %v, want %v", tt.width, tt.height, valid, tt.want == "")
+		}
+	}
+}
+
+func TestValidateComments(t *testing.T) {
+	r := validFeed()
+	r.Channel.Item = []*Item{
+		{Title: "Star City", Comments: "http://liftoff.msfc.nasa.gov/comments/573"},
+		{Title: "Sky watchers", Comments: "see the forum"},
+	}
+	err := r.Validate()
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Path != "item[1].comments" || !errors.Is(err, ErrInvalidURL) {
+		t.Errorf("Validate() = %v, want a single ErrInvalidURL for item[1].comments", err)
+	}
+}
+
+func TestValidateSource(t *testing.T) {
+	tests := []struct {
+		name   string
+		source *Source
+		want   string
+	}{
+		{"url", &Source{URL: "http://www.tomalak.org/links2.xml", Value: "Tomalak's Realm"}, ""},
+		{"missing url", &Source{Value: "Tomalak's Realm"}, "item[0].source: missing required url"},
+		{"relative url", &Source{URL: "links2.xml", Value: "Tomalak's Realm"}, "item[0].source: invalid URL"},
+	}
+	for _, tt := range tests {
+		r := validFeed()
+		r.Channel.Item = []*Item{{Title: "Star City", Source: tt.source}}
+		err := r.Validate()
+		got := ""
+		if err != nil {
+			got = err.Error()
+		}
+		if got != tt.want {
+			t.Errorf("%s: Validate() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestValidateVersion(t *testing.T) {
+	tests := []struct {
+		version Version
+		want    error
+	}{
+		{"", ErrMissingVersion},
+		{"1.0", ErrUnsupportedVersion},
+		{"2.0", nil},
+	}
+	for _, tt := range tests {
+		r := validFeed()
+		r.Version = tt.version
+		err := r.Validate()
+		if tt.want == nil {
+			if err != nil {
+				t.Errorf("Version %q: Validate() = %v, want nil", tt.version, err)
+			}
+			continue
+		}
+		if !errors.Is(err, tt.want) {
+			t.Errorf("Version %q: Validate() = %v, want error matching %v", tt.version, err, tt.want)
+		}
+		other := ErrMissingVersion
+		if tt.want == ErrMissingVersion {
+			other = ErrUnsupportedVersion
+		}
+		if errors.Is(err, other) {
+			t.Errorf("Version %q: Validate() = %v, must not match %v", tt.version, err, other)
+		}
+	}
+}
+
+func TestValidateVersionAttributeAbsent(t *testing.T) {
+	r, err := Parse(strings.NewReader(`<rss><channel><title>t</title><link>http://a.com</link><description>d</description></channel></rss>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Validate(); !errors.Is(err, ErrMissingVersion) {
+		t.Errorf("Validate() = %v, want error matching ErrMissingVersion", err)
+	}
+}
+
+func TestValidateAgreesWithValidateElement(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		path  string
+		set   func(c *Channel, value string)
+	}{
+		{"language", "zzzz-bogus-!!", "language", func(c *Channel, s string) { c.Language = Language(s) }},
+		{"link", "nope", "item[0].link", func(c *Channel, s string) { c.Item[0].Link = Link(s) }},
+		{"url", "rel", "item[0].enclosure", func(c *Channel, s string) {
+			c.Item[0].Enclosure = &Enclosure{URL: URL(s), Length: "6", Type: "audio/mpeg"}
+		}},
+		{"comments", "see the forum", "item[0].comments", func(c *Channel, s string) { c.Item[0].Comments = Comments(s) }},
+	}
+	for _, tt := range tests {
+		want := ValidateElement(tt.name, tt.value)
+		if want == nil {
+			t.Fatalf("ValidateElement(%q, %q) = nil, want an error", tt.name, tt.value)
+		}
+		r := validFeed()
+		r.Channel.Item = []*Item{{Title: "Star City"}}
+		tt.set(r.Channel, tt.value)
+		err := r.Validate()
+		var errs ValidationErrors
+		if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Path != tt.path || !errors.Is(err, errors.Unwrap(want)) {
+			t.Errorf("%s: Validate() = %v, want a single %v at %s", tt.name, err, errors.Unwrap(want), tt.path)
+		}
+	}
+}